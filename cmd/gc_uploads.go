@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"main-service/config"
+	"main-service/internal/db"
+	"main-service/internal/repository"
+	"main-service/internal/storage"
+	"main-service/internal/usecase"
+)
+
+// openStorage membuat BlobStore dari konfigurasi; dipakai server dan subcommand
+func openStorage(cfg *config.Config) (storage.BlobStore, error) {
+	return storage.New(storage.Config{
+		Driver:        cfg.StorageDriver,
+		PublicURL:     cfg.StoragePublicURL,
+		LocalDir:      cfg.StorageLocalDir,
+		SignedURLBase: cfg.AppBaseURL + "/api/v1/files",
+		SigningSecret: cfg.StorageSigningSecret,
+		S3Endpoint:    cfg.S3Endpoint,
+		S3Region:      cfg.S3Region,
+		S3Bucket:      cfg.S3Bucket,
+		S3AccessKey:   cfg.S3AccessKey,
+		S3SecretKey:   cfg.S3SecretKey,
+		S3UseSSL:      cfg.S3UseSSL,
+	})
+}
+
+// runGCUploads menangani subcommand `gc-uploads`, mis.
+// `go run ./cmd gc-uploads -dry-run` untuk melihat orphan tanpa menghapus
+func runGCUploads(ctx context.Context, args []string) {
+	cfg := config.Load()
+
+	fs := flag.NewFlagSet("gc-uploads", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "hanya laporkan orphan, jangan hapus")
+	grace := fs.Duration("grace", time.Duration(cfg.UploadGCGraceHours)*time.Hour, "umur minimal object sebelum boleh dihapus")
+	verbose := fs.Bool("v", false, "tampilkan setiap orphan")
+	fs.Parse(args)
+	if *grace <= 0 {
+		log.Fatal("-grace harus lebih dari 0")
+	}
+
+	dbConn, err := db.Open(cfg)
+	if err != nil {
+		log.Fatalf("Database connection failed: %v", err)
+	}
+	defer dbConn.Close()
+
+	store, err := openStorage(cfg)
+	if err != nil {
+		log.Fatalf("Storage setup failed: %v", err)
+	}
+
+	gc := usecase.NewUploadGCUsecase(repository.NewBimbelRepository(dbConn), store, *grace)
+	report, err := gc.Run(ctx, *dryRun)
+	if err != nil {
+		dbConn.Close()
+		log.Fatalf("Upload GC failed: %v", err)
+	}
+
+	if *dryRun || *verbose {
+		for _, o := range report.Orphans {
+			state := "orphan "
+			if o.Deleted {
+				state = "deleted"
+			}
+			fmt.Printf("%s %-60s %10d  %s\n", state, o.Key, o.Size, o.ModTime.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	mode := "hapus"
+	if report.DryRun {
+		mode = "dry-run"
+	}
+	fmt.Printf("\nRingkasan (%s, prefix %s, cutoff %s)\n", mode, report.Prefix, report.Cutoff.Format("2006-01-02 15:04:05"))
+	fmt.Printf("  dipindai        %d\n", report.Scanned)
+	fmt.Printf("  dirujuk         %d\n", report.Referenced)
+	fmt.Printf("  masa tenggang   %d\n", report.InGracePeriod)
+	fmt.Printf("  orphan          %d\n", len(report.Orphans))
+	fmt.Printf("  dihapus         %d (%d bytes)\n", report.Deleted, report.DeletedBytes)
+	fmt.Printf("  gagal           %d\n", report.Failed)
+
+	if report.Failed > 0 {
+		dbConn.Close()
+		os.Exit(1)
+	}
+}
@@ -1,72 +1,243 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
+	_ "time/tzdata" // zona waktu tetap tersedia walau OS tidak punya tzdata
 
 	"main-service/config"
 	"main-service/internal/db"
 	httpHandler "main-service/internal/delivery/http"
+	"main-service/internal/mailer"
 	"main-service/internal/middleware"
+	"main-service/internal/payment"
 	"main-service/internal/repository"
+	"main-service/internal/storage"
 	"main-service/internal/usecase"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func main() {
+	// ===== Subcommand migrate (go run ./cmd migrate up|down|status|create) =====
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(context.Background(), os.Args[2:])
+		return
+	}
+
+	// ===== Subcommand gc-uploads (go run ./cmd gc-uploads [-dry-run]) =====
+	if len(os.Args) > 1 && os.Args[1] == "gc-uploads" {
+		runGCUploads(context.Background(), os.Args[2:])
+		return
+	}
+
+	// ===== Subcommand purge-trash (go run ./cmd purge-trash [-dry-run]) =====
+	if len(os.Args) > 1 && os.Args[1] == "purge-trash" {
+		runPurgeTrash(context.Background(), os.Args[2:])
+		return
+	}
+
 	// ===== Load konfigurasi dari .env =====
 	cfg := config.Load()
 
 	// ===== Koneksi ke database =====
-	dbConn, err := db.NewMySQLConnection(cfg)
+	dbConn, err := db.Open(cfg)
 	if err != nil {
 		log.Fatalf("Database connection failed: %v", err)
 	}
 
+	// ===== Cek schema database vs migration di binary =====
+	if cfg.DBSchemaCheck != "off" {
+		migrator, err := db.NewMigrator(dbConn)
+		if err != nil {
+			log.Fatalf("Load migrations failed: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = migrator.CheckDrift(ctx)
+		cancel()
+		if err != nil {
+			if cfg.DBSchemaCheck == "strict" {
+				log.Fatalf("Schema check failed: %v (jalankan `go run ./cmd migrate up`)", err)
+			}
+			log.Printf("⚠️  Schema check: %v", err)
+		}
+	}
+
 	// ===== Repository =====
 	userRepo := repository.NewUserRepository(dbConn)
 	featureRepo := repository.NewFeatureRepository(dbConn)
 	matpelRepo := repository.NewMatpelRepository(dbConn)
 	bimbelRepo := repository.NewBimbelRepository(dbConn)
+	enrollmentRepo := repository.NewEnrollmentRepository(dbConn)
+	sessionRepo := repository.NewSessionRepository(dbConn)
+	attendanceRepo := repository.NewAttendanceRepository(dbConn)
+	invoiceRepo := repository.NewInvoiceRepository(dbConn)
+	paymentNotificationRepo := repository.NewPaymentNotificationRepository(dbConn)
+	reviewRepo := repository.NewReviewRepository(dbConn)
+	tutorRepo := repository.NewTutorRepository(dbConn)
+	pesertaRepo := repository.NewPesertaRepository(dbConn)
+	authSessionRepo := repository.NewAuthSessionRepository(dbConn)
+	userTokenRepo := repository.NewUserTokenRepository(dbConn)
+	txManager := repository.NewTxManager(dbConn)
+
+	// ===== Payment provider =====
+	paymentProvider, err := payment.NewProvider(cfg.PaymentProvider, cfg.AppBaseURL)
+	if err != nil {
+		log.Fatalf("Payment provider setup failed: %v", err)
+	}
+
+	// ===== Mailer =====
+	mail, err := mailer.New(mailer.Config{
+		Driver:   cfg.MailDriver,
+		From:     cfg.MailFrom,
+		SMTPHost: cfg.SMTPHost,
+		SMTPPort: cfg.SMTPPort,
+		SMTPUser: cfg.SMTPUser,
+		SMTPPass: cfg.SMTPPass,
+		FileDir:  cfg.MailFileDir,
+	})
+	if err != nil {
+		log.Fatalf("Mailer setup failed: %v", err)
+	}
+
+	// ===== Storage (thumbnail, avatar, dokumen tutor) =====
+	store, err := openStorage(cfg)
+	if err != nil {
+		log.Fatalf("Storage setup failed: %v", err)
+	}
 
 	// ===== Usecase =====
-	userUC := usecase.NewUserUsecase(userRepo, cfg.JWTSecret, cfg.JWTExpHour)
-	featureUC := usecase.NewFeatureUsecase(featureRepo)
-	matpelUC := usecase.NewMatpelUsecase(matpelRepo, featureRepo)
-	bimbelUC := usecase.NewBimbelUsecase(bimbelRepo)
+	userUC := usecase.NewUserUsecase(userRepo, authSessionRepo, userTokenRepo, txManager, mail, usecase.AuthConfig{
+		JWTSecret:              cfg.JWTSecret,
+		AccessTTL:              time.Duration(cfg.JWTAccessMinutes) * time.Minute,
+		RefreshTTL:             time.Duration(cfg.RefreshTokenDays) * 24 * time.Hour,
+		PasswordResetTTL:       time.Duration(cfg.PasswordResetMinutes) * time.Minute,
+		EmailVerificationTTL:   time.Duration(cfg.EmailVerificationHours) * time.Hour,
+		EmailVerificationRoles: cfg.EmailVerificationRoles,
+		AppBaseURL:             cfg.AppBaseURL,
+	})
+	featureUC := usecase.NewFeatureUsecase(featureRepo, txManager)
+	matpelUC := usecase.NewMatpelUsecase(matpelRepo, txManager)
+	bimbelUC := usecase.NewBimbelUsecase(bimbelRepo, txManager, cfg.BimbelModeration)
+	invoiceUC := usecase.NewInvoiceUsecase(invoiceRepo, paymentProvider, time.Duration(cfg.InvoiceExpiryMinutes)*time.Minute)
+	enrollmentUC := usecase.NewEnrollmentUsecase(enrollmentRepo, bimbelRepo, invoiceUC, txManager)
+	paymentWebhookUC := usecase.NewPaymentWebhookUsecase(paymentNotificationRepo, invoiceRepo, cfg.PaymentWebhookSecret)
+	reviewUC := usecase.NewReviewUsecase(reviewRepo, enrollmentRepo, bimbelRepo)
+	sessionUC := usecase.NewSessionUsecase(sessionRepo, bimbelRepo)
+	attendanceUC := usecase.NewAttendanceUsecase(attendanceRepo, sessionRepo, enrollmentRepo, bimbelRepo, txManager)
+	tutorUC := usecase.NewTutorUsecase(tutorRepo, bimbelRepo, matpelRepo)
+	pesertaUC := usecase.NewPesertaUsecase(pesertaRepo)
+	userAdminUC := usecase.NewUserAdminUsecase(userRepo, txManager)
+	uploadGCUC := usecase.NewUploadGCUsecase(bimbelRepo, store, time.Duration(cfg.UploadGCGraceHours)*time.Hour)
 
 	// ===== Handler (HTTP Delivery) =====
 	userHandler := httpHandler.NewUserHandler(userUC)
 	featureHandler := httpHandler.NewFeatureHandler(featureUC)
 	matpelHandler := httpHandler.NewMatpelHandler(matpelUC)
-	bimbelHandler := httpHandler.NewBimbelHandler(bimbelUC, userRepo)
+	bimbelHandler := httpHandler.NewBimbelHandler(bimbelUC, store)
+	enrollmentHandler := httpHandler.NewEnrollmentHandler(enrollmentUC)
+	sessionHandler := httpHandler.NewSessionHandler(sessionUC)
+	attendanceHandler := httpHandler.NewAttendanceHandler(attendanceUC)
+	invoiceHandler := httpHandler.NewInvoiceHandler(invoiceUC)
+	paymentWebhookHandler := httpHandler.NewPaymentWebhookHandler(paymentWebhookUC)
+	reviewHandler := httpHandler.NewReviewHandler(reviewUC)
+	tutorHandler := httpHandler.NewTutorHandler(tutorUC, store)
+	pesertaHandler := httpHandler.NewPesertaHandler(pesertaUC)
+	userAdminHandler := httpHandler.NewUserAdminHandler(userAdminUC)
+	systemHandler := httpHandler.NewSystemHandler(dbConn)
+	fileHandler := httpHandler.NewFileHandler(store)
 
 	// ===== Fiber Setup =====
-	app := fiber.New()
+	// Semua error dari handler/middleware dibungkus envelope yang sama
+	app := fiber.New(fiber.Config{ErrorHandler: httpHandler.ErrorHandler})
 
-	// ===== Buat folder uploads jika belum ada =====
-	if _, err := os.Stat("uploads"); os.IsNotExist(err) {
-		if err := os.MkdirAll("uploads/thumbnails", os.ModePerm); err != nil {
-			log.Fatalf("Failed to create uploads folder: %v", err)
+	// ===== Static file serving storage local (akses: http://localhost:8080/uploads/thumbnails/...) =====
+	// Hanya prefix publik yang disajikan; dokumen tutor lewat signed URL /api/v1/files
+	if cfg.StorageDriver == "local" {
+		for _, prefix := range storage.PublicPrefixes {
+			app.Static("/uploads/"+prefix, filepath.Join(cfg.StorageLocalDir, prefix))
 		}
 	}
 
-	// ===== Static file serving (akses: http://localhost:8080/uploads/...) =====
-	app.Static("/uploads", "./uploads")
-
 	// ===== Routes =====
-	api := app.Group("/api/v1")
+	// Setiap request API punya deadline; query yang melewatinya dibatalkan
+	api := app.Group("/api/v1", middleware.Timeout(time.Duration(cfg.RequestTimeoutSeconds)*time.Second))
 
 	// Public routes (tanpa login)
-	userHandler.RegisterRoutes(api) // Login & Register
+	userHandler.RegisterRoutes(api)           // Login, Register & Refresh token
+	bimbelHandler.RegisterPublicRoutes(api)   // Katalog bimbel
+	paymentWebhookHandler.RegisterRoutes(api) // Webhook payment gateway (HMAC)
+	reviewHandler.RegisterPublicRoutes(api)   // Review bimbel
+	tutorHandler.RegisterPublicRoutes(api)    // Profil publik tutor
+	fileHandler.RegisterPublicRoutes(api)     // Signed URL storage local
 
 	// Protected routes (harus login)
 	protected := api.Group("") // group kosong untuk endpoint di bawahnya
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(authSessionRepo))
+	userHandler.RegisterSessionRoutes(protected)
 	featureHandler.RegisterRoutes(protected)
 	matpelHandler.RegisterRoutes(protected)
 	bimbelHandler.RegisterRoutes(protected)
+	enrollmentHandler.RegisterRoutes(protected)
+	sessionHandler.RegisterRoutes(protected)
+	attendanceHandler.RegisterRoutes(protected)
+	invoiceHandler.RegisterRoutes(protected)
+	paymentWebhookHandler.RegisterAdminRoutes(protected)
+	reviewHandler.RegisterRoutes(protected)
+	tutorHandler.RegisterRoutes(protected)
+	pesertaHandler.RegisterRoutes(protected)
+	userAdminHandler.RegisterRoutes(protected)
+	systemHandler.RegisterRoutes(protected)
+
+	// ===== Job: expire invoice yang tidak dibayar =====
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if n, err := invoiceUC.ExpireOverdue(ctx); err != nil {
+				log.Printf("Expire invoice failed: %v", err)
+			} else if n > 0 {
+				log.Printf("%d invoice expired", n)
+			}
+			cancel()
+		}
+	}()
+
+	// ===== Job: tutup bimbel yang semua sesinya sudah selesai =====
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if n, err := bimbelUC.CloseFinished(ctx); err != nil {
+				log.Printf("Close finished bimbel failed: %v", err)
+			} else if n > 0 {
+				log.Printf("%d bimbel ditutup karena semua sesi selesai", n)
+			}
+			cancel()
+		}
+	}()
+
+	// ===== Job: hapus thumbnail yang tidak dirujuk bimbel mana pun =====
+	if cfg.UploadGCIntervalHours > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Duration(cfg.UploadGCIntervalHours) * time.Hour)
+			defer ticker.Stop()
+			for range ticker.C {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+				if report, err := uploadGCUC.Run(ctx, false); err != nil {
+					log.Printf("Upload GC failed: %v", err)
+				} else if len(report.Orphans) > 0 {
+					log.Printf("Upload GC: %d orphan dihapus (%d bytes), %d gagal", report.Deleted, report.DeletedBytes, report.Failed)
+				}
+				cancel()
+			}
+		}()
+	}
 
 	// ===== Jalankan server =====
 	log.Printf("🚀 Server running on port %s", cfg.AppPort)
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"main-service/config"
+	"main-service/internal/db"
+)
+
+const migrateUsage = `Penggunaan: go run ./cmd migrate <perintah>
+
+  up           jalankan semua migration yang belum dijalankan
+  down [N]     rollback N migration terakhir (default 1)
+  status       tampilkan migration yang sudah / belum dijalankan
+  create NAME  buat file up/down baru di ` + db.MigrationsDir + `/<dialect>
+`
+
+// runMigrate menangani subcommand `migrate`, mis. `go run ./cmd migrate up`
+func runMigrate(ctx context.Context, args []string) {
+	if len(args) == 0 {
+		fmt.Print(migrateUsage)
+		os.Exit(2)
+	}
+
+	// create hanya menulis file, tidak butuh koneksi database
+	if args[0] == "create" {
+		if len(args) < 2 {
+			log.Fatal("nama migration wajib diisi: migrate create NAME")
+		}
+		files, err := db.CreateMigration(db.MigrationsDir, args[1])
+		for _, file := range files {
+			fmt.Println("created", file)
+		}
+		if err != nil {
+			log.Fatalf("Create migration failed: %v", err)
+		}
+		return
+	}
+
+	cfg := config.Load()
+	dbConn, err := db.Open(cfg)
+	if err != nil {
+		log.Fatalf("Database connection failed: %v", err)
+	}
+	defer dbConn.Close()
+
+	migrator, err := db.NewMigrator(dbConn)
+	if err != nil {
+		log.Fatalf("Load migrations failed: %v", err)
+	}
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(ctx)
+		for _, m := range done {
+			fmt.Printf("applied  %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migrate up failed: %v", err)
+		}
+		if len(done) == 0 {
+			fmt.Println("schema sudah up to date")
+		}
+
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			if n, err = strconv.Atoi(args[1]); err != nil || n <= 0 {
+				log.Fatalf("N harus bilangan bulat positif: %s", args[1])
+			}
+		}
+		done, err := migrator.Down(ctx, n)
+		for _, m := range done {
+			fmt.Printf("reverted %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migrate down failed: %v", err)
+		}
+		if len(done) == 0 {
+			fmt.Println("tidak ada migration untuk di-rollback")
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Migrate status failed: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			switch {
+			case s.Missing:
+				state = "MISSING (tidak ada di binary)"
+			case s.Modified:
+				state = "MODIFIED (file berubah setelah dijalankan)"
+			case s.AppliedAt != nil:
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d_%-40s %s\n", s.Version, s.Name, state)
+		}
+
+	default:
+		fmt.Print(migrateUsage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"main-service/config"
+	"main-service/internal/db"
+	"main-service/internal/repository"
+	"main-service/internal/usecase"
+)
+
+// runPurgeTrash menangani subcommand `purge-trash`, mis.
+// `go run ./cmd purge-trash -dry-run` untuk melihat jumlahnya tanpa menghapus
+func runPurgeTrash(ctx context.Context, args []string) {
+	cfg := config.Load()
+
+	fs := flag.NewFlagSet("purge-trash", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "hitung baris yang akan dihapus tanpa menghapus")
+	retention := fs.Duration("retention", time.Duration(cfg.TrashRetentionDays)*24*time.Hour, "umur minimal data di trash sebelum dihapus permanen")
+	fs.Parse(args)
+	if *retention <= 0 {
+		log.Fatal("-retention harus lebih dari 0")
+	}
+
+	dbConn, err := db.Open(cfg)
+	if err != nil {
+		log.Fatalf("Database connection failed: %v", err)
+	}
+	defer dbConn.Close()
+
+	purger := usecase.NewPurgeUsecase(repository.NewTxManager(dbConn))
+	report, err := purger.Purge(ctx, time.Now().UTC().Add(-*retention), *dryRun)
+	if err != nil {
+		dbConn.Close()
+		log.Fatalf("Purge trash failed: %v", err)
+	}
+
+	mode := "hapus"
+	if report.DryRun {
+		mode = "dry-run"
+	}
+	fmt.Printf("Purge trash (%s, dihapus sebelum %s)\n", mode, report.Before.Format("2006-01-02 15:04:05"))
+	fmt.Printf("  bimbel          %d\n", report.Bimbels)
+	fmt.Printf("  mata pelajaran  %d\n", report.Subjects)
+	fmt.Printf("  fitur           %d\n", report.Features)
+	fmt.Println("Bimbel yang punya enrollment/invoice/sesi/review, serta induknya, tetap disimpan.")
+}
@@ -4,43 +4,272 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	AppPort    string
-	DBUser     string
-	DBPass     string
-	DBHost     string
-	DBPort     string
-	DBName     string
-	JWTSecret  string
-	JWTExpHour int
+	AppPort   string
+	DBDriver  string // mysql (default) atau postgres
+	DBUser    string
+	DBPass    string
+	DBHost    string
+	DBPort    string
+	DBName    string
+	DBSSLMode string // khusus postgres, default disable
+	JWTSecret string
+
+	// DBSchemaCheck menentukan reaksi server saat schema tertinggal dari
+	// migration di binary: "strict" (default, gagal start), "warn", atau "off"
+	DBSchemaCheck string
+
+	// Connection pool; lifetime/idle time dalam menit, 0 berarti tanpa batas
+	DBMaxOpenConns           int
+	DBMaxIdleConns           int
+	DBConnMaxLifetimeMinutes int
+	DBConnMaxIdleMinutes     int
+
+	// RequestTimeoutSeconds adalah deadline per request; query yang masih
+	// berjalan saat deadline habis dibatalkan
+	RequestTimeoutSeconds int
+
+	JWTAccessMinutes int
+	RefreshTokenDays int
+
+	AppBaseURL           string
+	PaymentProvider      string
+	PaymentWebhookSecret string
+	InvoiceExpiryMinutes int
+
+	MailDriver  string
+	MailFrom    string
+	MailFileDir string
+	SMTPHost    string
+	SMTPPort    string
+	SMTPUser    string
+	SMTPPass    string
+
+	// Storage file upload: local (default) atau s3
+	StorageDriver        string
+	StorageLocalDir      string
+	StoragePublicURL     string
+	StorageSigningSecret string // wajib untuk driver local, terpisah dari JWT_SECRET
+	S3Endpoint           string
+	S3Region             string
+	S3Bucket             string
+	S3AccessKey          string
+	S3SecretKey          string
+	S3UseSSL             bool
+
+	// Garbage collection upload yang tidak dirujuk database
+	UploadGCIntervalHours int // 0 = job berkala dimatikan
+	UploadGCGraceHours    int
+
+	// Lama data di trash sebelum boleh di-purge permanen
+	TrashRetentionDays int
+
+	// BimbelModeration true berarti bimbel baru wajib diajukan dan disetujui
+	// admin sebelum tampil di katalog
+	BimbelModeration bool
+
+	EmailVerificationRoles []string
+	PasswordResetMinutes   int
+	EmailVerificationHours int
 }
 
 func Load() *Config {
 	_ = godotenv.Load(".env")
 
-	expHour, err := strconv.Atoi(os.Getenv("JWT_EXP_HOURS"))
-	if err != nil || expHour <= 0 {
-		expHour = 24 // default
+	accessMinutes, err := strconv.Atoi(os.Getenv("JWT_ACCESS_MINUTES"))
+	if err != nil || accessMinutes <= 0 {
+		accessMinutes = 15 // default, access token sengaja dibuat singkat
+	}
+
+	refreshDays, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_DAYS"))
+	if err != nil || refreshDays <= 0 {
+		refreshDays = 30 // default
+	}
+
+	invoiceExpiry, err := strconv.Atoi(os.Getenv("INVOICE_EXPIRY_MINUTES"))
+	if err != nil || invoiceExpiry <= 0 {
+		invoiceExpiry = 24 * 60 // default 1 hari
+	}
+
+	resetMinutes, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_MINUTES"))
+	if err != nil || resetMinutes <= 0 {
+		resetMinutes = 60 // default
+	}
+
+	verifyHours, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_HOURS"))
+	if err != nil || verifyHours <= 0 {
+		verifyHours = 24 // default
+	}
+
+	maxOpen, err := strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNS"))
+	if err != nil || maxOpen <= 0 {
+		maxOpen = 25 // default
+	}
+
+	maxIdle, err := strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNS"))
+	if err != nil || maxIdle < 0 {
+		maxIdle = 10 // default
+	}
+	if maxIdle > maxOpen {
+		maxIdle = maxOpen
+	}
+
+	connLifetime, err := strconv.Atoi(os.Getenv("DB_CONN_MAX_LIFETIME_MINUTES"))
+	if err != nil || connLifetime < 0 {
+		connLifetime = 30 // default, di bawah wait_timeout MySQL / idle timeout proxy
+	}
+
+	connIdle, err := strconv.Atoi(os.Getenv("DB_CONN_MAX_IDLE_MINUTES"))
+	if err != nil || connIdle < 0 {
+		connIdle = 5 // default
+	}
+
+	requestTimeout, err := strconv.Atoi(os.Getenv("REQUEST_TIMEOUT_SECONDS"))
+	if err != nil || requestTimeout <= 0 {
+		requestTimeout = 15 // default
+	}
+
+	gcInterval, err := strconv.Atoi(os.Getenv("UPLOAD_GC_INTERVAL_HOURS"))
+	if err != nil || gcInterval < 0 {
+		gcInterval = 24 // default
+	}
+
+	gcGrace, err := strconv.Atoi(os.Getenv("UPLOAD_GC_GRACE_HOURS"))
+	if err != nil || gcGrace <= 0 {
+		gcGrace = 24 // default
+	}
+
+	trashRetention, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || trashRetention <= 0 {
+		trashRetention = 30 // default
+	}
+
+	s3UseSSL := true // default
+	if v := os.Getenv("S3_USE_SSL"); v != "" {
+		if s3UseSSL, err = strconv.ParseBool(v); err != nil {
+			log.Fatalf("S3_USE_SSL harus true atau false: %s", v)
+		}
+	}
+
+	bimbelModeration := false // default, tutor terverifikasi boleh publish langsung
+	if v := os.Getenv("BIMBEL_MODERATION"); v != "" {
+		if bimbelModeration, err = strconv.ParseBool(v); err != nil {
+			log.Fatalf("BIMBEL_MODERATION harus true atau false: %s", v)
+		}
+	}
+
+	// Role yang wajib verifikasi email sebelum login, mis. "tutor,peserta"
+	var verifyRoles []string
+	for _, role := range strings.Split(os.Getenv("EMAIL_VERIFICATION_ROLES"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			verifyRoles = append(verifyRoles, role)
+		}
 	}
 
 	cfg := &Config{
-		AppPort:    os.Getenv("APP_PORT"),
-		DBUser:     os.Getenv("DB_USER"),
-		DBPass:     os.Getenv("DB_PASS"),
-		DBHost:     os.Getenv("DB_HOST"),
-		DBPort:     os.Getenv("DB_PORT"),
-		DBName:     os.Getenv("DB_NAME"),
-		JWTSecret:  os.Getenv("JWT_SECRET"),
-		JWTExpHour: expHour,
+		AppPort:   os.Getenv("APP_PORT"),
+		DBDriver:  strings.ToLower(os.Getenv("DB_DRIVER")),
+		DBUser:    os.Getenv("DB_USER"),
+		DBPass:    os.Getenv("DB_PASS"),
+		DBHost:    os.Getenv("DB_HOST"),
+		DBPort:    os.Getenv("DB_PORT"),
+		DBName:    os.Getenv("DB_NAME"),
+		DBSSLMode: os.Getenv("DB_SSLMODE"),
+		JWTSecret: os.Getenv("JWT_SECRET"),
+
+		DBSchemaCheck: strings.ToLower(os.Getenv("DB_SCHEMA_CHECK")),
+
+		DBMaxOpenConns:           maxOpen,
+		DBMaxIdleConns:           maxIdle,
+		DBConnMaxLifetimeMinutes: connLifetime,
+		DBConnMaxIdleMinutes:     connIdle,
+
+		RequestTimeoutSeconds: requestTimeout,
+
+		JWTAccessMinutes: accessMinutes,
+		RefreshTokenDays: refreshDays,
+
+		AppBaseURL:           os.Getenv("APP_BASE_URL"),
+		PaymentProvider:      os.Getenv("PAYMENT_PROVIDER"),
+		PaymentWebhookSecret: os.Getenv("PAYMENT_WEBHOOK_SECRET"),
+		InvoiceExpiryMinutes: invoiceExpiry,
+
+		MailDriver:  os.Getenv("MAIL_DRIVER"),
+		MailFrom:    os.Getenv("MAIL_FROM"),
+		MailFileDir: os.Getenv("MAIL_FILE_DIR"),
+		SMTPHost:    os.Getenv("SMTP_HOST"),
+		SMTPPort:    os.Getenv("SMTP_PORT"),
+		SMTPUser:    os.Getenv("SMTP_USER"),
+		SMTPPass:    os.Getenv("SMTP_PASS"),
+
+		StorageDriver:        strings.ToLower(os.Getenv("STORAGE_DRIVER")),
+		StorageLocalDir:      os.Getenv("STORAGE_LOCAL_DIR"),
+		StoragePublicURL:     os.Getenv("STORAGE_PUBLIC_URL"),
+		StorageSigningSecret: os.Getenv("STORAGE_SIGNING_SECRET"),
+		S3Endpoint:           os.Getenv("S3_ENDPOINT"),
+		S3Region:             os.Getenv("S3_REGION"),
+		S3Bucket:             os.Getenv("S3_BUCKET"),
+		S3AccessKey:          os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:          os.Getenv("S3_SECRET_KEY"),
+		S3UseSSL:             s3UseSSL,
+
+		UploadGCIntervalHours: gcInterval,
+		UploadGCGraceHours:    gcGrace,
+
+		TrashRetentionDays: trashRetention,
+
+		BimbelModeration: bimbelModeration,
+
+		EmailVerificationRoles: verifyRoles,
+		PasswordResetMinutes:   resetMinutes,
+		EmailVerificationHours: verifyHours,
 	}
 
 	if cfg.AppPort == "" {
 		log.Fatal("APP_PORT is not set in .env")
 	}
 
+	if cfg.DBDriver == "" {
+		cfg.DBDriver = "mysql"
+	}
+
+	if cfg.DBSSLMode == "" {
+		cfg.DBSSLMode = "disable"
+	}
+
+	switch cfg.DBSchemaCheck {
+	case "strict", "warn", "off":
+	case "":
+		cfg.DBSchemaCheck = "strict"
+	default:
+		log.Fatalf("DB_SCHEMA_CHECK tidak dikenal: %s (strict, warn, off)", cfg.DBSchemaCheck)
+	}
+
+	if cfg.AppBaseURL == "" {
+		cfg.AppBaseURL = "http://localhost:" + cfg.AppPort
+	}
+
+	if cfg.StorageDriver == "" {
+		cfg.StorageDriver = "local"
+	}
+
+	if cfg.StorageLocalDir == "" {
+		cfg.StorageLocalDir = "uploads"
+	}
+
+	// Driver local menyajikan file publik di /uploads milik aplikasi ini
+	if cfg.StoragePublicURL == "" && cfg.StorageDriver == "local" {
+		cfg.StoragePublicURL = cfg.AppBaseURL + "/uploads"
+	}
+
+	if cfg.MailFrom == "" {
+		cfg.MailFrom = "no-reply@localhost"
+	}
+
 	return cfg
 }
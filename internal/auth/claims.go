@@ -0,0 +1,57 @@
+// Package auth berisi format access token (JWT) dan cara menyimpan identitas
+// user yang sedang login di context request.
+package auth
+
+import (
+	"errors"
+	"main-service/internal/domain"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims adalah isi access token. Field identitas sama persis dengan
+// domain.AuthPrincipal sehingga handler tidak perlu query ulang ke database.
+type Claims struct {
+	domain.AuthPrincipal
+	jwt.RegisteredClaims
+}
+
+func NewClaims(p domain.AuthPrincipal, exp time.Time) *Claims {
+	return &Claims{
+		AuthPrincipal: p,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	}
+}
+
+// Validate dipanggil jwt setelah exp/iat dicek; token tanpa identitas lengkap
+// (mis. token format lama tanpa sid) dianggap tidak valid.
+func (c *Claims) Validate() error {
+	if c.UserID == 0 || c.Role == "" || c.SessionID == 0 {
+		return errors.New("claims tidak lengkap")
+	}
+	if c.ExpiresAt == nil {
+		return errors.New("claims tanpa exp")
+	}
+	return nil
+}
+
+// Sign membuat access token HS256
+func Sign(p domain.AuthPrincipal, exp time.Time, secret string) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, NewClaims(p, exp)).SignedString([]byte(secret))
+}
+
+// Parse memverifikasi tanda tangan + masa berlaku token dan mengembalikan principal
+func Parse(tokenString, secret string) (domain.AuthPrincipal, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return domain.AuthPrincipal{}, err
+	}
+	return claims.AuthPrincipal, nil
+}
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"main-service/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const principalKey = "auth_principal"
+
+// SetPrincipal dipanggil AuthMiddleware setelah token dan sesi valid
+func SetPrincipal(c *fiber.Ctx, p domain.AuthPrincipal) {
+	c.Locals(principalKey, p)
+}
+
+// PrincipalFrom mengambil identitas user dari context; route yang tidak
+// melewati AuthMiddleware akan mendapat domain.ErrUnauthenticated
+func PrincipalFrom(c *fiber.Ctx) (domain.AuthPrincipal, error) {
+	p, ok := c.Locals(principalKey).(domain.AuthPrincipal)
+	if !ok || p.UserID == 0 {
+		return domain.AuthPrincipal{}, domain.ErrUnauthenticated
+	}
+	return p, nil
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"database/sql"
+	"main-service/config"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Open membuka koneksi sesuai DB_DRIVER (mysql atau postgres)
+func Open(cfg *config.Config) (*DB, error) {
+	dialect, err := NewDialect(cfg.DBDriver)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sql.Open(dialect.DriverName(), dialect.DSN(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	conn.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	conn.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetimeMinutes) * time.Minute)
+	conn.SetConnMaxIdleTime(time.Duration(cfg.DBConnMaxIdleMinutes) * time.Minute)
+
+	if err = conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return New(conn, dialect), nil
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Conn adalah koneksi yang dipakai repository: *DB, atau *Tx saat repository
+// dijalankan di dalam transaksi milik usecase (lihat repository.TxManager)
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	InsertIDContext(ctx context.Context, query string, args ...any) (uint64, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error)
+	WithTx(ctx context.Context, fn func(tx *Tx) error) error
+}
+
+var (
+	_ Conn = (*DB)(nil)
+	_ Conn = (*Tx)(nil)
+)
+
+// DB membungkus *sql.DB dan menerapkan Dialect pada setiap query, sehingga
+// repository tetap menulis placeholder `?` apa pun database-nya
+type DB struct {
+	sql     *sql.DB
+	dialect Dialect
+}
+
+// New membungkus koneksi yang sudah dibuka dengan dialect-nya
+func New(conn *sql.DB, dialect Dialect) *DB {
+	return &DB{sql: conn, dialect: dialect}
+}
+
+func (d *DB) Dialect() Dialect { return d.dialect }
+
+// SQL mengembalikan koneksi mentah; query lewat jalur ini tidak di-rebind
+func (d *DB) SQL() *sql.DB { return d.sql }
+
+func (d *DB) Close() error { return d.sql.Close() }
+
+// Stats mengembalikan statistik connection pool untuk monitoring
+func (d *DB) Stats() sql.DBStats { return d.sql.Stats() }
+
+// Semua query wajib membawa context supaya bisa dibatalkan saat deadline
+// request habis; tidak ada varian tanpa context.
+
+func (d *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.sql.ExecContext(ctx, d.dialect.Rebind(query), utcArgs(args)...)
+}
+
+func (d *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.sql.QueryContext(ctx, d.dialect.Rebind(query), utcArgs(args)...)
+}
+
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.sql.QueryRowContext(ctx, d.dialect.Rebind(query), utcArgs(args)...)
+}
+
+// InsertIDContext menjalankan INSERT dan mengembalikan id baris baru
+func (d *DB) InsertIDContext(ctx context.Context, query string, args ...any) (uint64, error) {
+	return insertID(ctx, d.dialect, d.sql.ExecContext, d.sql.QueryRowContext, query, args)
+}
+
+func insertID(
+	ctx context.Context,
+	dialect Dialect,
+	exec func(context.Context, string, ...any) (sql.Result, error),
+	queryRow func(context.Context, string, ...any) *sql.Row,
+	query string, args []any,
+) (uint64, error) {
+	args = utcArgs(args)
+	if dialect.ReturningID() {
+		var id uint64
+		query = strings.TrimRight(strings.TrimSpace(query), ";") + " RETURNING id"
+		err := queryRow(ctx, dialect.Rebind(query), args...).Scan(&id)
+		return id, err
+	}
+
+	res, err := exec(ctx, dialect.Rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return uint64(id), err
+}
+
+// utcArgs mengubah argumen waktu ke UTC. Driver MySQL sudah melakukannya
+// (loc=UTC), sedangkan pgx menyimpan jam lokal apa adanya ke kolom TIMESTAMP.
+func utcArgs(args []any) []any {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			args[i] = v.UTC()
+		case *time.Time:
+			if v != nil {
+				args[i] = v.UTC()
+			}
+		}
+	}
+	return args
+}
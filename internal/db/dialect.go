@@ -0,0 +1,146 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"main-service/config"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Dialect membungkus perbedaan antar database sehingga repository cukup
+// menulis satu versi query: placeholder `?`, NOW(), TRUE/FALSE, dan
+// LIMIT/OFFSET yang sama-sama dipahami MySQL dan PostgreSQL.
+type Dialect interface {
+	Name() string
+	DriverName() string
+	DSN(cfg *config.Config) string
+	// Rebind mengubah placeholder `?` ke format driver
+	Rebind(query string) string
+	// ReturningID true jika id baris baru diambil lewat `RETURNING id`
+	// karena driver tidak mendukung LastInsertId
+	ReturningID() bool
+	// IsRetryable true jika transaksi gagal karena deadlock / konflik
+	// serialisasi dan aman diulang dari awal
+	IsRetryable(err error) bool
+}
+
+const (
+	DialectMySQL    = "mysql"
+	DialectPostgres = "postgres"
+)
+
+// NewDialect memilih dialect berdasarkan DB_DRIVER di konfigurasi
+func NewDialect(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "", DialectMySQL:
+		return mysqlDialect{}, nil
+	case DialectPostgres, "postgresql", "pgx":
+		return postgresDialect{}, nil
+	default:
+		return nil, fmt.Errorf("database driver %q belum didukung", name)
+	}
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string       { return DialectMySQL }
+func (mysqlDialect) DriverName() string { return "mysql" }
+func (mysqlDialect) ReturningID() bool  { return false }
+
+func (mysqlDialect) DSN(cfg *config.Config) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
+		cfg.DBUser, cfg.DBPass, cfg.DBHost, cfg.DBPort, cfg.DBName,
+	)
+}
+
+func (mysqlDialect) Rebind(query string) string { return query }
+
+// 1213 = ER_LOCK_DEADLOCK; InnoDB sudah me-rollback seluruh transaksi
+func (mysqlDialect) IsRetryable(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && me.Number == 1213
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string       { return DialectPostgres }
+func (postgresDialect) DriverName() string { return "pgx" }
+func (postgresDialect) ReturningID() bool  { return true }
+
+// DSN memaksa zona waktu sesi UTC supaya NOW() dan kolom TIMESTAMP
+// berperilaku sama dengan MySQL (waktu disimpan dalam UTC)
+func (postgresDialect) DSN(cfg *config.Config) string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(cfg.DBUser, cfg.DBPass),
+		Host:   cfg.DBHost + ":" + cfg.DBPort,
+		Path:   "/" + cfg.DBName,
+	}
+	q := url.Values{}
+	q.Set("sslmode", cfg.DBSSLMode)
+	q.Set("timezone", "UTC")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// 40P01 = deadlock_detected, 40001 = serialization_failure
+func (postgresDialect) IsRetryable(err error) bool {
+	var pe *pgconn.PgError
+	return errors.As(err, &pe) && (pe.Code == "40P01" || pe.Code == "40001")
+}
+
+// IsDuplicateKey true jika err berasal dari pelanggaran unique index:
+// 1062 = ER_DUP_ENTRY (MySQL), 23505 = unique_violation (PostgreSQL)
+func IsDuplicateKey(err error) bool {
+	var me *mysql.MySQLError
+	if errors.As(err, &me) {
+		return me.Number == 1062
+	}
+	var pe *pgconn.PgError
+	return errors.As(err, &pe) && pe.Code == "23505"
+}
+
+// Rebind mengganti `?` menjadi $1, $2, ... kecuali yang berada di dalam
+// string literal ('...') atau komentar baris (-- ...)
+func (postgresDialect) Rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var (
+		b       strings.Builder
+		n       int
+		inStr   bool
+		comment bool
+	)
+	b.Grow(len(query) + 8)
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case comment:
+			if ch == '\n' {
+				comment = false
+			}
+		case inStr:
+			if ch == '\'' {
+				inStr = false
+			}
+		case ch == '\'':
+			inStr = true
+		case ch == '-' && i+1 < len(query) && query[i+1] == '-':
+			comment = true
+		case ch == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
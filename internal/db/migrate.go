@@ -0,0 +1,463 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Semua file migration ikut ter-compile ke binary, jadi server dan perintah
+// `migrate` selalu memakai versi schema yang sama dengan kodenya. Setiap
+// dialect punya folder sendiri (migrations/mysql, migrations/postgres) dengan
+// versi dan nama file yang sama.
+//
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+// MigrationsDir adalah lokasi file migration di source tree, dipakai `migrate create`
+const MigrationsDir = "internal/db/migrations"
+
+// migrationSQL berisi query pengelolaan schema_migrations yang berbeda per dialect
+type migrationSQL struct {
+	lock        string // mengembalikan 1 jika lock didapat
+	unlock      string
+	tableExists string
+	createTable string
+}
+
+var migrationDialectSQL = map[string]migrationSQL{
+	DialectMySQL: {
+		lock:   `SELECT GET_LOCK('schema_migrations', 30)`,
+		unlock: `SELECT RELEASE_LOCK('schema_migrations')`,
+		tableExists: `
+			SELECT COUNT(*) > 0 FROM information_schema.tables
+			WHERE table_schema = DATABASE() AND table_name = 'schema_migrations'`,
+		createTable: `
+			CREATE TABLE IF NOT EXISTS schema_migrations (
+				version    BIGINT       NOT NULL,
+				name       VARCHAR(255) NOT NULL,
+				checksum   CHAR(64)     NOT NULL,
+				applied_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (version)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+	},
+	DialectPostgres: {
+		// advisory lock tidak menunggu; key bebas asal konsisten
+		lock:   `SELECT CASE WHEN pg_try_advisory_lock(724100017) THEN 1 ELSE 0 END`,
+		unlock: `SELECT pg_advisory_unlock(724100017)`,
+		tableExists: `
+			SELECT COUNT(*) > 0 FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_name = 'schema_migrations'`,
+		createTable: `
+			CREATE TABLE IF NOT EXISTS schema_migrations (
+				version    BIGINT       PRIMARY KEY,
+				name       VARCHAR(255) NOT NULL,
+				checksum   CHAR(64)     NOT NULL,
+				applied_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)`,
+	},
+}
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration adalah satu pasang file <version>_<name>.up.sql / .down.sql
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // sha256 file up, untuk mendeteksi file yang diubah setelah dijalankan
+}
+
+// MigrationStatus adalah kondisi satu migration di database
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	Modified  bool // sudah dijalankan tapi file up-nya berubah
+	Missing   bool // tercatat di database tapi tidak ada di binary
+}
+
+// SchemaDriftError menjelaskan perbedaan schema database dengan migration di binary
+type SchemaDriftError struct {
+	Pending  []int64 // belum dijalankan
+	Unknown  []int64 // sudah dijalankan tapi tidak dikenal binary (binary lebih lama)
+	Modified []int64 // file up diubah setelah dijalankan
+}
+
+func (e *SchemaDriftError) Error() string {
+	var parts []string
+	if len(e.Pending) > 0 {
+		parts = append(parts, "migration belum dijalankan: "+joinVersions(e.Pending))
+	}
+	if len(e.Unknown) > 0 {
+		parts = append(parts, "migration tidak dikenal binary: "+joinVersions(e.Unknown))
+	}
+	if len(e.Modified) > 0 {
+		parts = append(parts, "migration diubah setelah dijalankan: "+joinVersions(e.Modified))
+	}
+	return "schema database tidak sesuai (" + strings.Join(parts, "; ") + ")"
+}
+
+func joinVersions(versions []int64) string {
+	s := make([]string, len(versions))
+	for i, v := range versions {
+		s[i] = strconv.FormatInt(v, 10)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Migrator menjalankan migration yang di-embed terhadap satu database
+type Migrator struct {
+	db         *DB
+	queries    migrationSQL
+	migrations []Migration
+}
+
+func NewMigrator(db *DB) (*Migrator, error) {
+	name := db.Dialect().Name()
+	queries, ok := migrationDialectSQL[name]
+	if !ok {
+		return nil, fmt.Errorf("migration untuk dialect %s belum tersedia", name)
+	}
+	migrations, err := loadMigrations(migrationFiles, path.Join("migrations", name))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, queries: queries, migrations: migrations}, nil
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		m := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("nama file migration tidak valid: %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		body, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("versi migration %d dipakai dua nama: %s dan %s", version, mig.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(body)
+			sum := sha256.Sum256(body)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s harus punya file up dan down", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+func appliedMigrations(ctx context.Context, q interface {
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+}) (map[int64]appliedMigration, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var (
+			version int64
+			a       appliedMigration
+		)
+		if err := rows.Scan(&version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+// withLock menjalankan fn pada satu koneksi yang memegang lock database
+// (named lock MySQL / advisory lock PostgreSQL), supaya dua proses `migrate`
+// tidak berjalan bersamaan
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.SQL().Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, m.queries.lock).Scan(&locked); err != nil {
+		return err
+	}
+	if locked.Int64 != 1 {
+		return fmt.Errorf("gagal mengunci schema_migrations: migrate lain sedang berjalan")
+	}
+	defer conn.ExecContext(ctx, m.queries.unlock)
+
+	if _, err := conn.ExecContext(ctx, m.queries.createTable); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// Up menjalankan semua migration yang belum tercatat, urut dari versi terkecil
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := execScript(ctx, conn, mig.Up); err != nil {
+				return fmt.Errorf("migration %d_%s gagal: %w", mig.Version, mig.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, m.db.Dialect().Rebind(
+				`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, NOW())`),
+				mig.Version, mig.Name, mig.Checksum,
+			); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down membatalkan n migration terakhir yang sudah dijalankan
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions {
+			if len(done) == n {
+				break
+			}
+			mig, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("migration %d tidak ada di binary, tidak bisa di-rollback", version)
+			}
+			if err := execScript(ctx, conn, mig.Down); err != nil {
+				return fmt.Errorf("rollback migration %d_%s gagal: %w", mig.Version, mig.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, m.db.Dialect().Rebind(
+				`DELETE FROM schema_migrations WHERE version = ?`), mig.Version,
+			); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+// Status menggabungkan migration di binary dengan yang tercatat di database
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var result []MigrationStatus
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			s := MigrationStatus{Version: mig.Version, Name: mig.Name}
+			if a, ok := applied[mig.Version]; ok {
+				appliedAt := a.appliedAt
+				s.AppliedAt = &appliedAt
+				s.Modified = a.checksum != mig.Checksum
+				delete(applied, mig.Version)
+			}
+			result = append(result, s)
+		}
+		for version, a := range applied {
+			appliedAt := a.appliedAt
+			result = append(result, MigrationStatus{Version: version, Name: a.name, AppliedAt: &appliedAt, Missing: true})
+		}
+		sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+		return nil
+	})
+	return result, err
+}
+
+// CheckDrift dipanggil saat server start. Tidak membuat tabel apa pun: database
+// yang belum pernah di-migrate dianggap semua migration-nya pending.
+func (m *Migrator) CheckDrift(ctx context.Context) error {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, m.queries.tableExists).Scan(&exists); err != nil {
+		return err
+	}
+
+	applied := map[int64]appliedMigration{}
+	if exists {
+		var err error
+		if applied, err = appliedMigrations(ctx, m.db.SQL()); err != nil {
+			return err
+		}
+	}
+
+	drift := &SchemaDriftError{}
+	for _, mig := range m.migrations {
+		a, ok := applied[mig.Version]
+		if !ok {
+			drift.Pending = append(drift.Pending, mig.Version)
+			continue
+		}
+		if a.checksum != mig.Checksum {
+			drift.Modified = append(drift.Modified, mig.Version)
+		}
+		delete(applied, mig.Version)
+	}
+	for version := range applied {
+		drift.Unknown = append(drift.Unknown, version)
+	}
+	sort.Slice(drift.Unknown, func(i, j int) bool { return drift.Unknown[i] < drift.Unknown[j] })
+
+	if len(drift.Pending) == 0 && len(drift.Unknown) == 0 && len(drift.Modified) == 0 {
+		return nil
+	}
+	return drift
+}
+
+// execScript menjalankan file SQL statement per statement karena driver MySQL
+// tidak menerima banyak statement sekaligus tanpa multiStatements=true.
+// Statement dipisah oleh ';' di akhir baris. File migration ditulis per
+// dialect sehingga tidak di-rebind.
+func execScript(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitStatements(script string) []string {
+	var (
+		stmts   []string
+		current strings.Builder
+		hasCode bool
+	)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		current.WriteString(line)
+		current.WriteByte('\n')
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		hasCode = true
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+			hasCode = false
+		}
+	}
+	if hasCode {
+		stmts = append(stmts, strings.TrimSpace(current.String()))
+	}
+	return stmts
+}
+
+// CreateMigration membuat pasangan file up/down kosong dengan versi berikutnya
+// di setiap folder dialect di bawah dir (folder source, bukan hasil embed) dan
+// mengembalikan path file yang dibuat
+func CreateMigration(dir, name string) ([]string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return nil, fmt.Errorf("nama migration wajib diisi")
+	}
+
+	dialects := []string{DialectMySQL, DialectPostgres}
+
+	// Versi diambil dari folder dialect mana pun yang paling maju
+	var next int64 = 1
+	for _, dialect := range dialects {
+		migrations, err := loadMigrations(os.DirFS(filepath.Join(dir, dialect)), ".")
+		if err != nil {
+			return nil, err
+		}
+		if n := len(migrations); n > 0 && migrations[n-1].Version >= next {
+			next = migrations[n-1].Version + 1
+		}
+	}
+
+	var created []string
+	for _, dialect := range dialects {
+		base := filepath.Join(dir, dialect, fmt.Sprintf("%06d_%s", next, name))
+		for _, direction := range []string{"up", "down"} {
+			file := base + "." + direction + ".sql"
+			header := fmt.Sprintf("-- %s %s (%s)\n", name, direction, dialect)
+			if err := os.WriteFile(file, []byte(header), 0o644); err != nil {
+				return created, err
+			}
+			created = append(created, file)
+		}
+	}
+	return created, nil
+}
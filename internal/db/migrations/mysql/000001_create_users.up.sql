@@ -0,0 +1,60 @@
+-- Akun login beserta profil tutor/peserta. users.tutor_id / users.peserta_id
+-- menunjuk ke profil sesuai role.
+--
+-- Memakai IF NOT EXISTS supaya database lama yang tabelnya dibuat manual bisa
+-- langsung di-`migrate up` tanpa error.
+
+CREATE TABLE IF NOT EXISTS tutors (
+    id                        BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    is_active                 TINYINT(1)      NOT NULL DEFAULT 1,
+    bio                       TEXT            NULL,
+    education                 VARCHAR(255)    NULL,
+    experience                TEXT            NULL,
+    city                      VARCHAR(100)    NULL,
+    avatar_url                VARCHAR(500)    NULL,
+    verification_status       VARCHAR(20)     NOT NULL DEFAULT 'unverified',
+    rejection_reason          TEXT            NULL,
+    verification_reviewed_by  BIGINT UNSIGNED NULL,
+    verification_submitted_at DATETIME        NULL,
+    verified_at               DATETIME        NULL,
+    rating_sum                BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    rating_count              INT UNSIGNED    NOT NULL DEFAULT 0,
+    created_at                DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_tutors_verification_status (verification_status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS pesertas (
+    id             BIGINT UNSIGNED  NOT NULL AUTO_INCREMENT,
+    is_active      TINYINT(1)       NOT NULL DEFAULT 1,
+    school         VARCHAR(150)     NULL,
+    jenjang        VARCHAR(10)      NULL,
+    grade          TINYINT UNSIGNED NULL,
+    birth_date     DATE             NULL,
+    guardian_name  VARCHAR(100)     NULL,
+    guardian_phone VARCHAR(20)      NULL,
+    created_at     DATETIME         NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     DATETIME         NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS users (
+    id                BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name              VARCHAR(100)    NOT NULL,
+    email             VARCHAR(150)    NOT NULL,
+    password          VARCHAR(255)    NOT NULL,
+    role              VARCHAR(20)     NOT NULL,
+    tutor_id          BIGINT UNSIGNED NULL,
+    peserta_id        BIGINT UNSIGNED NULL,
+    is_active         TINYINT(1)      NOT NULL DEFAULT 1,
+    email_verified_at DATETIME        NULL,
+    created_at        DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        DATETIME        NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_users_email (email),
+    KEY idx_users_role (role),
+    CONSTRAINT fk_users_tutor FOREIGN KEY (tutor_id) REFERENCES tutors (id),
+    CONSTRAINT fk_users_peserta FOREIGN KEY (peserta_id) REFERENCES pesertas (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
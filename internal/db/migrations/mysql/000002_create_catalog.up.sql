@@ -0,0 +1,71 @@
+-- Katalog: fitur (kategori), mata pelajaran, bimbel, serta data pendukung tutor
+
+CREATE TABLE IF NOT EXISTS features (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name       VARCHAR(100)    NOT NULL,
+    is_active  TINYINT(1)      NOT NULL DEFAULT 1,
+    roles      VARCHAR(255)    NOT NULL,
+    created_at DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_features_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS subjects (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    feature_id BIGINT UNSIGNED NOT NULL,
+    name       VARCHAR(100)    NOT NULL,
+    deskripsi  TEXT            NULL,
+    is_active  TINYINT(1)      NOT NULL DEFAULT 1,
+    created_at DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_subjects_feature (feature_id, name),
+    CONSTRAINT fk_subjects_feature FOREIGN KEY (feature_id) REFERENCES features (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS tutor_subjects (
+    tutor_id   BIGINT UNSIGNED NOT NULL,
+    subject_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (tutor_id, subject_id),
+    KEY idx_tutor_subjects_subject (subject_id),
+    CONSTRAINT fk_tutor_subjects_tutor FOREIGN KEY (tutor_id) REFERENCES tutors (id) ON DELETE CASCADE,
+    CONSTRAINT fk_tutor_subjects_subject FOREIGN KEY (subject_id) REFERENCES subjects (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS tutor_documents (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    tutor_id   BIGINT UNSIGNED NOT NULL,
+    doc_type   VARCHAR(50)     NOT NULL,
+    file_name  VARCHAR(255)    NOT NULL,
+    file_path  VARCHAR(500)    NOT NULL,
+    created_at DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_tutor_documents_tutor (tutor_id),
+    CONSTRAINT fk_tutor_documents_tutor FOREIGN KEY (tutor_id) REFERENCES tutors (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS bimbels (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    tutor_id      BIGINT UNSIGNED NOT NULL,
+    feature_id    BIGINT UNSIGNED NOT NULL,
+    subject_id    BIGINT UNSIGNED NOT NULL,
+    name          VARCHAR(150)    NOT NULL,
+    limit_peserta INT UNSIGNED    NOT NULL DEFAULT 0,
+    is_active     TINYINT(1)      NOT NULL DEFAULT 1,
+    thumbnail     VARCHAR(500)    NOT NULL DEFAULT '',
+    deskripsi     TEXT            NOT NULL,
+    harga         DECIMAL(12, 2)  NOT NULL,
+    rating_sum    BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    rating_count  INT UNSIGNED    NOT NULL DEFAULT 0,
+    created_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at    DATETIME        NULL,
+    PRIMARY KEY (id),
+    KEY idx_bimbels_tutor (tutor_id),
+    KEY idx_bimbels_catalog (feature_id, subject_id),
+    KEY idx_bimbels_subject (subject_id),
+    CONSTRAINT fk_bimbels_tutor FOREIGN KEY (tutor_id) REFERENCES tutors (id),
+    CONSTRAINT fk_bimbels_feature FOREIGN KEY (feature_id) REFERENCES features (id),
+    CONSTRAINT fk_bimbels_subject FOREIGN KEY (subject_id) REFERENCES subjects (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS payment_notifications;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS enrollments;
@@ -0,0 +1,62 @@
+-- Pendaftaran peserta ke bimbel, tagihan, dan log webhook payment gateway
+
+CREATE TABLE IF NOT EXISTS enrollments (
+    id           BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    bimbel_id    BIGINT UNSIGNED NOT NULL,
+    peserta_id   BIGINT UNSIGNED NOT NULL,
+    status       VARCHAR(20)     NOT NULL,
+    enrolled_at  DATETIME        NOT NULL,
+    cancelled_at DATETIME        NULL,
+    created_at   DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    -- join ulang memakai baris yang sama, jadi satu peserta satu baris per bimbel
+    UNIQUE KEY uq_enrollments_bimbel_peserta (bimbel_id, peserta_id),
+    KEY idx_enrollments_peserta (peserta_id, status),
+    CONSTRAINT fk_enrollments_bimbel FOREIGN KEY (bimbel_id) REFERENCES bimbels (id),
+    CONSTRAINT fk_enrollments_peserta FOREIGN KEY (peserta_id) REFERENCES pesertas (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    number        VARCHAR(40)     NOT NULL,
+    enrollment_id BIGINT UNSIGNED NOT NULL,
+    bimbel_id     BIGINT UNSIGNED NOT NULL,
+    peserta_id    BIGINT UNSIGNED NOT NULL,
+    amount        BIGINT          NOT NULL,
+    status        VARCHAR(20)     NOT NULL,
+    provider      VARCHAR(30)     NOT NULL DEFAULT '',
+    provider_ref  VARCHAR(100)    NULL,
+    payment_url   VARCHAR(500)    NOT NULL DEFAULT '',
+    expires_at    DATETIME        NOT NULL,
+    paid_at       DATETIME        NULL,
+    created_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_invoices_number (number),
+    KEY idx_invoices_enrollment (enrollment_id),
+    KEY idx_invoices_peserta (peserta_id),
+    KEY idx_invoices_status_expires (status, expires_at),
+    CONSTRAINT fk_invoices_enrollment FOREIGN KEY (enrollment_id) REFERENCES enrollments (id),
+    CONSTRAINT fk_invoices_bimbel FOREIGN KEY (bimbel_id) REFERENCES bimbels (id),
+    CONSTRAINT fk_invoices_peserta FOREIGN KEY (peserta_id) REFERENCES pesertas (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Tidak ada FK ke invoices: notifikasi dengan nomor invoice tak dikenal tetap dicatat
+CREATE TABLE IF NOT EXISTS payment_notifications (
+    id              BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    provider        VARCHAR(30)     NOT NULL,
+    event_id        VARCHAR(100)    NOT NULL DEFAULT '',
+    invoice_number  VARCHAR(40)     NOT NULL DEFAULT '',
+    gateway_status  VARCHAR(30)     NOT NULL DEFAULT '',
+    signature_valid TINYINT(1)      NOT NULL DEFAULT 0,
+    payload         MEDIUMTEXT      NOT NULL,
+    result          VARCHAR(20)     NOT NULL,
+    result_message  VARCHAR(500)    NOT NULL DEFAULT '',
+    processed_at    DATETIME        NULL,
+    created_at      DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_payment_notifications_event (provider, event_id),
+    KEY idx_payment_notifications_invoice (invoice_number),
+    KEY idx_payment_notifications_result (result)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,63 @@
+-- Jadwal pertemuan bimbel dan absensi peserta. Waktu sesi disimpan dalam UTC.
+
+CREATE TABLE IF NOT EXISTS bimbel_session_recurrences (
+    id               BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    bimbel_id        BIGINT UNSIGNED NOT NULL,
+    title            VARCHAR(150)    NOT NULL DEFAULT '',
+    weekdays         VARCHAR(20)     NOT NULL, -- mis. "1,3,5" (0 = Minggu)
+    start_time       CHAR(5)         NOT NULL, -- HH:MM waktu lokal timezone
+    duration_minutes INT UNSIGNED    NOT NULL,
+    timezone         VARCHAR(64)     NOT NULL,
+    start_date       DATE            NOT NULL,
+    end_date         DATE            NOT NULL,
+    created_at       DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at       DATETIME        NULL,
+    PRIMARY KEY (id),
+    KEY idx_recurrences_bimbel (bimbel_id),
+    CONSTRAINT fk_recurrences_bimbel FOREIGN KEY (bimbel_id) REFERENCES bimbels (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS bimbel_sessions (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    bimbel_id     BIGINT UNSIGNED NOT NULL,
+    recurrence_id BIGINT UNSIGNED NULL,
+    title         VARCHAR(150)    NOT NULL DEFAULT '',
+    start_at      DATETIME        NOT NULL,
+    end_at        DATETIME        NOT NULL,
+    timezone      VARCHAR(64)     NOT NULL,
+    created_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at    DATETIME        NULL,
+    PRIMARY KEY (id),
+    KEY idx_sessions_bimbel_start (bimbel_id, start_at),
+    KEY idx_sessions_recurrence (recurrence_id),
+    CONSTRAINT fk_sessions_bimbel FOREIGN KEY (bimbel_id) REFERENCES bimbels (id),
+    CONSTRAINT fk_sessions_recurrence FOREIGN KEY (recurrence_id) REFERENCES bimbel_session_recurrences (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Satu kode check-in aktif per sesi; hanya hash sha256 yang disimpan
+CREATE TABLE IF NOT EXISTS session_checkin_codes (
+    session_id BIGINT UNSIGNED NOT NULL,
+    code_hash  CHAR(64)        NOT NULL,
+    expires_at DATETIME        NOT NULL,
+    created_at DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id),
+    CONSTRAINT fk_checkin_codes_session FOREIGN KEY (session_id) REFERENCES bimbel_sessions (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS attendances (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    session_id    BIGINT UNSIGNED NOT NULL,
+    peserta_id    BIGINT UNSIGNED NOT NULL,
+    status        VARCHAR(20)     NOT NULL,
+    note          VARCHAR(255)    NOT NULL DEFAULT '',
+    checked_in_at DATETIME        NULL,
+    marked_by     BIGINT UNSIGNED NULL, -- users.id tutor/admin yang mengabsen manual
+    created_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_attendances_session_peserta (session_id, peserta_id),
+    KEY idx_attendances_peserta (peserta_id),
+    CONSTRAINT fk_attendances_session FOREIGN KEY (session_id) REFERENCES bimbel_sessions (id),
+    CONSTRAINT fk_attendances_peserta FOREIGN KEY (peserta_id) REFERENCES pesertas (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
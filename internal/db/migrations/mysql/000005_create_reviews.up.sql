@@ -0,0 +1,22 @@
+-- Ulasan peserta untuk bimbel yang pernah diikuti, satu ulasan per peserta per bimbel
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id            BIGINT UNSIGNED  NOT NULL AUTO_INCREMENT,
+    bimbel_id     BIGINT UNSIGNED  NOT NULL,
+    peserta_id    BIGINT UNSIGNED  NOT NULL,
+    enrollment_id BIGINT UNSIGNED  NOT NULL,
+    rating        TINYINT UNSIGNED NOT NULL,
+    comment       TEXT             NOT NULL,
+    tutor_reply   TEXT             NULL,
+    replied_at    DATETIME         NULL,
+    is_hidden     TINYINT(1)       NOT NULL DEFAULT 0,
+    hidden_reason VARCHAR(255)     NULL,
+    created_at    DATETIME         NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    DATETIME         NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_reviews_bimbel_peserta (bimbel_id, peserta_id),
+    KEY idx_reviews_peserta (peserta_id),
+    CONSTRAINT fk_reviews_bimbel FOREIGN KEY (bimbel_id) REFERENCES bimbels (id),
+    CONSTRAINT fk_reviews_peserta FOREIGN KEY (peserta_id) REFERENCES pesertas (id),
+    CONSTRAINT fk_reviews_enrollment FOREIGN KEY (enrollment_id) REFERENCES enrollments (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
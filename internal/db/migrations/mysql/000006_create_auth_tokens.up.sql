@@ -0,0 +1,43 @@
+-- Sesi login (per device), refresh token berotasi, dan token sekali pakai
+-- (reset password, verifikasi email). Semua token hanya disimpan hash-nya.
+
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id       BIGINT UNSIGNED NOT NULL,
+    user_agent    VARCHAR(255)    NOT NULL DEFAULT '',
+    ip_address    VARCHAR(45)     NOT NULL DEFAULT '',
+    created_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at  DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at    DATETIME        NULL,
+    revoke_reason VARCHAR(30)     NULL,
+    PRIMARY KEY (id),
+    KEY idx_auth_sessions_user (user_id, revoked_at),
+    CONSTRAINT fk_auth_sessions_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    session_id BIGINT UNSIGNED NOT NULL,
+    token_hash CHAR(64)        NOT NULL,
+    expires_at DATETIME        NOT NULL,
+    used_at    DATETIME        NULL,
+    created_at DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_refresh_tokens_hash (token_hash),
+    KEY idx_refresh_tokens_session (session_id),
+    CONSTRAINT fk_refresh_tokens_session FOREIGN KEY (session_id) REFERENCES auth_sessions (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id         BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id    BIGINT UNSIGNED NOT NULL,
+    purpose    VARCHAR(30)     NOT NULL,
+    token_hash CHAR(64)        NOT NULL,
+    expires_at DATETIME        NOT NULL,
+    used_at    DATETIME        NULL,
+    created_at DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_user_tokens_hash (token_hash),
+    KEY idx_user_tokens_user_purpose (user_id, purpose),
+    CONSTRAINT fk_user_tokens_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,14 @@
+-- Host asal tidak disimpan, jadi dikembalikan sebagai path relatif
+-- (/uploads/<key> dan storage/<key>) yang masih dipahami kode lama
+
+UPDATE bimbels
+SET thumbnail = CONCAT('/uploads/', thumbnail)
+WHERE thumbnail <> '' AND thumbnail NOT LIKE '%/uploads/%';
+
+UPDATE tutors
+SET avatar_url = CONCAT('/uploads/', avatar_url)
+WHERE avatar_url <> '' AND avatar_url NOT LIKE '%/uploads/%';
+
+UPDATE tutor_documents
+SET file_path = CONCAT('storage/', file_path)
+WHERE file_path LIKE 'tutor_documents/%';
@@ -0,0 +1 @@
+ALTER TABLE bimbels DROP COLUMN thumbnail_variants;
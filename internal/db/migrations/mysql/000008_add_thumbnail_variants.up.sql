@@ -0,0 +1,4 @@
+-- Varian thumbnail hasil resize (JPEG & WebP) disimpan sebagai JSON:
+-- [{"key":"thumbnails/bimbel_1_320w.webp","width":320,"height":180,"format":"webp"}, ...]
+-- NULL untuk bimbel lama yang thumbnail-nya belum diproses.
+ALTER TABLE bimbels ADD COLUMN thumbnail_variants TEXT NULL AFTER thumbnail;
@@ -0,0 +1,14 @@
+-- Baris yang masih di trash dinonaktifkan supaya tidak muncul kembali
+-- sebagai data aktif setelah kolom deleted_at dibuang
+UPDATE features SET is_active = 0 WHERE deleted_at IS NOT NULL;
+UPDATE subjects SET is_active = 0 WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE bimbels DROP KEY idx_bimbels_deleted;
+
+ALTER TABLE subjects
+    DROP KEY idx_subjects_deleted,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE features
+    DROP KEY idx_features_deleted,
+    DROP COLUMN deleted_at;
@@ -0,0 +1,13 @@
+-- Feature dan subject kini di-soft-delete seperti bimbel, sehingga bimbel
+-- yang merujuknya tidak pernah kehilangan induk. Baris dihapus permanen
+-- oleh `go run ./cmd purge-trash` setelah masa retensi.
+ALTER TABLE features
+    ADD COLUMN deleted_at DATETIME NULL AFTER updated_at,
+    ADD KEY idx_features_deleted (deleted_at);
+
+ALTER TABLE subjects
+    ADD COLUMN deleted_at DATETIME NULL AFTER updated_at,
+    ADD KEY idx_subjects_deleted (deleted_at);
+
+ALTER TABLE bimbels
+    ADD KEY idx_bimbels_deleted (deleted_at);
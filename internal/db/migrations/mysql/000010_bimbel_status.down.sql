@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS bimbel_status_history;
+
+ALTER TABLE bimbels ADD COLUMN is_active TINYINT(1) NOT NULL DEFAULT 1 AFTER limit_peserta;
+
+UPDATE bimbels SET is_active = CASE WHEN status = 'published' THEN 1 ELSE 0 END;
+
+ALTER TABLE bimbels
+    DROP KEY idx_bimbels_status,
+    DROP COLUMN status;
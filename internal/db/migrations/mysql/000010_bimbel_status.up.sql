@@ -0,0 +1,26 @@
+-- is_active diganti status siklus hidup bimbel:
+-- draft -> pending_review -> published -> closed -> archived.
+-- Bimbel aktif menjadi published, yang nonaktif menjadi archived.
+ALTER TABLE bimbels ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft' AFTER limit_peserta;
+
+UPDATE bimbels SET status = CASE WHEN is_active = 1 THEN 'published' ELSE 'archived' END;
+
+ALTER TABLE bimbels
+    DROP COLUMN is_active,
+    ADD KEY idx_bimbels_status (status);
+
+-- Riwayat perubahan status; actor_user_id NULL berarti oleh sistem (mis. kuota penuh)
+CREATE TABLE IF NOT EXISTS bimbel_status_history (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    bimbel_id     BIGINT UNSIGNED NOT NULL,
+    from_status   VARCHAR(20)     NULL,
+    to_status     VARCHAR(20)     NOT NULL,
+    actor_user_id BIGINT UNSIGNED NULL,
+    actor_role    VARCHAR(20)     NOT NULL,
+    reason        VARCHAR(500)    NULL,
+    created_at    DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_bimbel_status_history_bimbel (bimbel_id, id),
+    CONSTRAINT fk_bimbel_status_history_bimbel FOREIGN KEY (bimbel_id) REFERENCES bimbels (id) ON DELETE CASCADE,
+    CONSTRAINT fk_bimbel_status_history_user FOREIGN KEY (actor_user_id) REFERENCES users (id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
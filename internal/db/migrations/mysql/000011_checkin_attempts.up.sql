@@ -0,0 +1,11 @@
+-- Jumlah kode check-in salah per peserta per sesi. Setelah batas tercapai
+-- peserta dikunci sampai tutor membuat kode baru (baris sesi dihapus).
+CREATE TABLE IF NOT EXISTS session_checkin_attempts (
+    session_id   BIGINT UNSIGNED NOT NULL,
+    peserta_id   BIGINT UNSIGNED NOT NULL,
+    failed_count INT UNSIGNED    NOT NULL DEFAULT 0,
+    updated_at   DATETIME        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, peserta_id),
+    CONSTRAINT fk_checkin_attempts_session FOREIGN KEY (session_id) REFERENCES bimbel_sessions (id) ON DELETE CASCADE,
+    CONSTRAINT fk_checkin_attempts_peserta FOREIGN KEY (peserta_id) REFERENCES pesertas (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
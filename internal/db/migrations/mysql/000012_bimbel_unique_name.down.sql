@@ -0,0 +1,3 @@
+ALTER TABLE bimbels
+    DROP INDEX uq_bimbels_tutor_name,
+    DROP COLUMN active_name;
@@ -0,0 +1,6 @@
+-- Nama bimbel unik per tutor di antara bimbel yang belum dihapus. MySQL tidak
+-- punya partial index, jadi dipakai kolom generated yang NULL untuk baris di
+-- trash (NULL tidak pernah bentrok di unique index).
+ALTER TABLE bimbels
+    ADD COLUMN active_name VARCHAR(150) GENERATED ALWAYS AS (IF(deleted_at IS NULL, name, NULL)) VIRTUAL,
+    ADD UNIQUE KEY uq_bimbels_tutor_name (tutor_id, active_name);
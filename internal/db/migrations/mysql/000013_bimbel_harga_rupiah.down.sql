@@ -0,0 +1 @@
+ALTER TABLE bimbels MODIFY COLUMN harga DECIMAL(12, 2) NOT NULL;
@@ -0,0 +1,3 @@
+-- Harga bimbel disimpan dalam rupiah bulat, sama dengan invoices.amount
+UPDATE bimbels SET harga = ROUND(harga);
+ALTER TABLE bimbels MODIFY COLUMN harga BIGINT UNSIGNED NOT NULL;
@@ -0,0 +1,58 @@
+-- Versi PostgreSQL dari mysql/000001_create_users.up.sql. Nama dan urutan
+-- kolom dibuat sama supaya repository memakai query yang sama.
+--
+-- Catatan: email dibandingkan case-sensitive (MySQL memakai collation _ci).
+
+CREATE TABLE IF NOT EXISTS tutors (
+    id                        BIGSERIAL    PRIMARY KEY,
+    is_active                 BOOLEAN      NOT NULL DEFAULT TRUE,
+    bio                       TEXT         NULL,
+    education                 VARCHAR(255) NULL,
+    experience                TEXT         NULL,
+    city                      VARCHAR(100) NULL,
+    avatar_url                VARCHAR(500) NULL,
+    verification_status       VARCHAR(20)  NOT NULL DEFAULT 'unverified',
+    rejection_reason          TEXT         NULL,
+    verification_reviewed_by  BIGINT       NULL,
+    verification_submitted_at TIMESTAMP    NULL,
+    verified_at               TIMESTAMP    NULL,
+    rating_sum                BIGINT       NOT NULL DEFAULT 0,
+    rating_count              INTEGER      NOT NULL DEFAULT 0,
+    created_at                TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tutors_verification_status ON tutors (verification_status);
+
+CREATE TABLE IF NOT EXISTS pesertas (
+    id             BIGSERIAL    PRIMARY KEY,
+    is_active      BOOLEAN      NOT NULL DEFAULT TRUE,
+    school         VARCHAR(150) NULL,
+    jenjang        VARCHAR(10)  NULL,
+    grade          SMALLINT     NULL,
+    birth_date     DATE         NULL,
+    guardian_name  VARCHAR(100) NULL,
+    guardian_phone VARCHAR(20)  NULL,
+    created_at     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id                BIGSERIAL    PRIMARY KEY,
+    name              VARCHAR(100) NOT NULL,
+    email             VARCHAR(150) NOT NULL,
+    password          VARCHAR(255) NOT NULL,
+    role              VARCHAR(20)  NOT NULL,
+    tutor_id          BIGINT       NULL REFERENCES tutors (id),
+    peserta_id        BIGINT       NULL REFERENCES pesertas (id),
+    is_active         BOOLEAN      NOT NULL DEFAULT TRUE,
+    email_verified_at TIMESTAMP    NULL,
+    created_at        TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        TIMESTAMP    NULL,
+    CONSTRAINT uq_users_email UNIQUE (email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_role ON users (role);
+CREATE INDEX IF NOT EXISTS idx_users_tutor ON users (tutor_id);
+CREATE INDEX IF NOT EXISTS idx_users_peserta ON users (peserta_id);
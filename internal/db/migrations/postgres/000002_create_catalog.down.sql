@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS bimbels;
+DROP TABLE IF EXISTS tutor_documents;
+DROP TABLE IF EXISTS tutor_subjects;
+DROP TABLE IF EXISTS subjects;
+DROP TABLE IF EXISTS features;
@@ -0,0 +1,65 @@
+-- Versi PostgreSQL dari mysql/000002_create_catalog.up.sql
+
+CREATE TABLE IF NOT EXISTS features (
+    id         BIGSERIAL    PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    is_active  BOOLEAN      NOT NULL DEFAULT TRUE,
+    roles      VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_features_name ON features (name);
+
+CREATE TABLE IF NOT EXISTS subjects (
+    id         BIGSERIAL    PRIMARY KEY,
+    feature_id BIGINT       NOT NULL REFERENCES features (id),
+    name       VARCHAR(100) NOT NULL,
+    deskripsi  TEXT         NULL,
+    is_active  BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_subjects_feature ON subjects (feature_id, name);
+
+CREATE TABLE IF NOT EXISTS tutor_subjects (
+    tutor_id   BIGINT NOT NULL REFERENCES tutors (id) ON DELETE CASCADE,
+    subject_id BIGINT NOT NULL REFERENCES subjects (id) ON DELETE CASCADE,
+    PRIMARY KEY (tutor_id, subject_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tutor_subjects_subject ON tutor_subjects (subject_id);
+
+CREATE TABLE IF NOT EXISTS tutor_documents (
+    id         BIGSERIAL    PRIMARY KEY,
+    tutor_id   BIGINT       NOT NULL REFERENCES tutors (id) ON DELETE CASCADE,
+    doc_type   VARCHAR(50)  NOT NULL,
+    file_name  VARCHAR(255) NOT NULL,
+    file_path  VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tutor_documents_tutor ON tutor_documents (tutor_id);
+
+CREATE TABLE IF NOT EXISTS bimbels (
+    id            BIGSERIAL      PRIMARY KEY,
+    tutor_id      BIGINT         NOT NULL REFERENCES tutors (id),
+    feature_id    BIGINT         NOT NULL REFERENCES features (id),
+    subject_id    BIGINT         NOT NULL REFERENCES subjects (id),
+    name          VARCHAR(150)   NOT NULL,
+    limit_peserta INTEGER        NOT NULL DEFAULT 0,
+    is_active     BOOLEAN        NOT NULL DEFAULT TRUE,
+    thumbnail     VARCHAR(500)   NOT NULL DEFAULT '',
+    deskripsi     TEXT           NOT NULL,
+    harga         NUMERIC(12, 2) NOT NULL,
+    rating_sum    BIGINT         NOT NULL DEFAULT 0,
+    rating_count  INTEGER        NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at    TIMESTAMP      NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_bimbels_tutor ON bimbels (tutor_id);
+CREATE INDEX IF NOT EXISTS idx_bimbels_catalog ON bimbels (feature_id, subject_id);
+CREATE INDEX IF NOT EXISTS idx_bimbels_subject ON bimbels (subject_id);
@@ -0,0 +1,57 @@
+-- Versi PostgreSQL dari mysql/000003_create_enrollments_invoices.up.sql
+
+CREATE TABLE IF NOT EXISTS enrollments (
+    id           BIGSERIAL   PRIMARY KEY,
+    bimbel_id    BIGINT      NOT NULL REFERENCES bimbels (id),
+    peserta_id   BIGINT      NOT NULL REFERENCES pesertas (id),
+    status       VARCHAR(20) NOT NULL,
+    enrolled_at  TIMESTAMP   NOT NULL,
+    cancelled_at TIMESTAMP   NULL,
+    created_at   TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- join ulang memakai baris yang sama, jadi satu peserta satu baris per bimbel
+    CONSTRAINT uq_enrollments_bimbel_peserta UNIQUE (bimbel_id, peserta_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_enrollments_peserta ON enrollments (peserta_id, status);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id            BIGSERIAL    PRIMARY KEY,
+    number        VARCHAR(40)  NOT NULL,
+    enrollment_id BIGINT       NOT NULL REFERENCES enrollments (id),
+    bimbel_id     BIGINT       NOT NULL REFERENCES bimbels (id),
+    peserta_id    BIGINT       NOT NULL REFERENCES pesertas (id),
+    amount        BIGINT       NOT NULL,
+    status        VARCHAR(20)  NOT NULL,
+    provider      VARCHAR(30)  NOT NULL DEFAULT '',
+    provider_ref  VARCHAR(100) NULL,
+    payment_url   VARCHAR(500) NOT NULL DEFAULT '',
+    expires_at    TIMESTAMP    NOT NULL,
+    paid_at       TIMESTAMP    NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_invoices_number UNIQUE (number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_enrollment ON invoices (enrollment_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_peserta ON invoices (peserta_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_status_expires ON invoices (status, expires_at);
+
+-- Tidak ada FK ke invoices: notifikasi dengan nomor invoice tak dikenal tetap dicatat
+CREATE TABLE IF NOT EXISTS payment_notifications (
+    id              BIGSERIAL    PRIMARY KEY,
+    provider        VARCHAR(30)  NOT NULL,
+    event_id        VARCHAR(100) NOT NULL DEFAULT '',
+    invoice_number  VARCHAR(40)  NOT NULL DEFAULT '',
+    gateway_status  VARCHAR(30)  NOT NULL DEFAULT '',
+    signature_valid BOOLEAN      NOT NULL DEFAULT FALSE,
+    payload         TEXT         NOT NULL,
+    result          VARCHAR(20)  NOT NULL,
+    result_message  VARCHAR(500) NOT NULL DEFAULT '',
+    processed_at    TIMESTAMP    NULL,
+    created_at      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_notifications_event ON payment_notifications (provider, event_id);
+CREATE INDEX IF NOT EXISTS idx_payment_notifications_invoice ON payment_notifications (invoice_number);
+CREATE INDEX IF NOT EXISTS idx_payment_notifications_result ON payment_notifications (result);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS attendances;
+DROP TABLE IF EXISTS session_checkin_codes;
+DROP TABLE IF EXISTS bimbel_sessions;
+DROP TABLE IF EXISTS bimbel_session_recurrences;
@@ -0,0 +1,56 @@
+-- Versi PostgreSQL dari mysql/000004_create_sessions_attendance.up.sql
+
+CREATE TABLE IF NOT EXISTS bimbel_session_recurrences (
+    id               BIGSERIAL    PRIMARY KEY,
+    bimbel_id        BIGINT       NOT NULL REFERENCES bimbels (id),
+    title            VARCHAR(150) NOT NULL DEFAULT '',
+    weekdays         VARCHAR(20)  NOT NULL, -- mis. "1,3,5" (0 = Minggu)
+    start_time       CHAR(5)      NOT NULL, -- HH:MM waktu lokal timezone
+    duration_minutes INTEGER      NOT NULL,
+    timezone         VARCHAR(64)  NOT NULL,
+    start_date       DATE         NOT NULL,
+    end_date         DATE         NOT NULL,
+    created_at       TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at       TIMESTAMP    NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurrences_bimbel ON bimbel_session_recurrences (bimbel_id);
+
+CREATE TABLE IF NOT EXISTS bimbel_sessions (
+    id            BIGSERIAL    PRIMARY KEY,
+    bimbel_id     BIGINT       NOT NULL REFERENCES bimbels (id),
+    recurrence_id BIGINT       NULL REFERENCES bimbel_session_recurrences (id),
+    title         VARCHAR(150) NOT NULL DEFAULT '',
+    start_at      TIMESTAMP    NOT NULL,
+    end_at        TIMESTAMP    NOT NULL,
+    timezone      VARCHAR(64)  NOT NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at    TIMESTAMP    NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_bimbel_start ON bimbel_sessions (bimbel_id, start_at);
+CREATE INDEX IF NOT EXISTS idx_sessions_recurrence ON bimbel_sessions (recurrence_id);
+
+-- Satu kode check-in aktif per sesi; hanya hash sha256 yang disimpan
+CREATE TABLE IF NOT EXISTS session_checkin_codes (
+    session_id BIGINT    PRIMARY KEY REFERENCES bimbel_sessions (id) ON DELETE CASCADE,
+    code_hash  CHAR(64)  NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS attendances (
+    id            BIGSERIAL    PRIMARY KEY,
+    session_id    BIGINT       NOT NULL REFERENCES bimbel_sessions (id),
+    peserta_id    BIGINT       NOT NULL REFERENCES pesertas (id),
+    status        VARCHAR(20)  NOT NULL,
+    note          VARCHAR(255) NOT NULL DEFAULT '',
+    checked_in_at TIMESTAMP    NULL,
+    marked_by     BIGINT       NULL, -- users.id tutor/admin yang mengabsen manual
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_attendances_session_peserta UNIQUE (session_id, peserta_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendances_peserta ON attendances (peserta_id);
@@ -0,0 +1,19 @@
+-- Versi PostgreSQL dari mysql/000005_create_reviews.up.sql
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id            BIGSERIAL    PRIMARY KEY,
+    bimbel_id     BIGINT       NOT NULL REFERENCES bimbels (id),
+    peserta_id    BIGINT       NOT NULL REFERENCES pesertas (id),
+    enrollment_id BIGINT       NOT NULL REFERENCES enrollments (id),
+    rating        SMALLINT     NOT NULL,
+    comment       TEXT         NOT NULL,
+    tutor_reply   TEXT         NULL,
+    replied_at    TIMESTAMP    NULL,
+    is_hidden     BOOLEAN      NOT NULL DEFAULT FALSE,
+    hidden_reason VARCHAR(255) NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_reviews_bimbel_peserta UNIQUE (bimbel_id, peserta_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_peserta ON reviews (peserta_id);
@@ -0,0 +1,39 @@
+-- Versi PostgreSQL dari mysql/000006_create_auth_tokens.up.sql
+
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id            BIGSERIAL    PRIMARY KEY,
+    user_id       BIGINT       NOT NULL REFERENCES users (id),
+    user_agent    VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address    VARCHAR(45)  NOT NULL DEFAULT '',
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at    TIMESTAMP    NULL,
+    revoke_reason VARCHAR(30)  NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user ON auth_sessions (user_id, revoked_at);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id         BIGSERIAL PRIMARY KEY,
+    session_id BIGINT    NOT NULL REFERENCES auth_sessions (id) ON DELETE CASCADE,
+    token_hash CHAR(64)  NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at    TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_refresh_tokens_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session ON refresh_tokens (session_id);
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id         BIGSERIAL   PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose    VARCHAR(30) NOT NULL,
+    token_hash CHAR(64)    NOT NULL,
+    expires_at TIMESTAMP   NOT NULL,
+    used_at    TIMESTAMP   NULL,
+    created_at TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_user_tokens_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_purpose ON user_tokens (user_id, purpose);
@@ -0,0 +1,17 @@
+-- thumbnail & avatar sebelumnya berisi URL absolut (http://host/uploads/<key>)
+-- dan dokumen tutor berisi path absolut (/.../storage/tutor_documents/...).
+-- Sekarang kolom-kolom ini berisi object key storage, mis. thumbnails/bimbel_1.jpg.
+-- File dokumen lama perlu dipindah dari storage/tutor_documents ke
+-- <STORAGE_LOCAL_DIR>/tutor_documents (atau di-upload ke bucket yang sama).
+
+UPDATE bimbels
+SET thumbnail = SUBSTRING(thumbnail FROM POSITION('/uploads/' IN thumbnail) + 9)
+WHERE thumbnail LIKE '%/uploads/%';
+
+UPDATE tutors
+SET avatar_url = SUBSTRING(avatar_url FROM POSITION('/uploads/' IN avatar_url) + 9)
+WHERE avatar_url LIKE '%/uploads/%';
+
+UPDATE tutor_documents
+SET file_path = SUBSTRING(file_path FROM POSITION('/storage/tutor_documents/' IN file_path) + 9)
+WHERE file_path LIKE '%/storage/tutor_documents/%';
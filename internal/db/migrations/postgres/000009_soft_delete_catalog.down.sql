@@ -0,0 +1,12 @@
+-- Baris yang masih di trash dinonaktifkan supaya tidak muncul kembali
+-- sebagai data aktif setelah kolom deleted_at dibuang
+UPDATE features SET is_active = FALSE WHERE deleted_at IS NOT NULL;
+UPDATE subjects SET is_active = FALSE WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_bimbels_deleted;
+
+DROP INDEX IF EXISTS idx_subjects_deleted;
+ALTER TABLE subjects DROP COLUMN deleted_at;
+
+DROP INDEX IF EXISTS idx_features_deleted;
+ALTER TABLE features DROP COLUMN deleted_at;
@@ -0,0 +1,10 @@
+-- Feature dan subject kini di-soft-delete seperti bimbel, sehingga bimbel
+-- yang merujuknya tidak pernah kehilangan induk. Baris dihapus permanen
+-- oleh `go run ./cmd purge-trash` setelah masa retensi.
+ALTER TABLE features ADD COLUMN deleted_at TIMESTAMP NULL;
+CREATE INDEX idx_features_deleted ON features (deleted_at);
+
+ALTER TABLE subjects ADD COLUMN deleted_at TIMESTAMP NULL;
+CREATE INDEX idx_subjects_deleted ON subjects (deleted_at);
+
+CREATE INDEX idx_bimbels_deleted ON bimbels (deleted_at);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS bimbel_status_history;
+
+ALTER TABLE bimbels ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+UPDATE bimbels SET is_active = (status = 'published');
+
+DROP INDEX IF EXISTS idx_bimbels_status;
+ALTER TABLE bimbels DROP COLUMN status;
@@ -0,0 +1,23 @@
+-- is_active diganti status siklus hidup bimbel:
+-- draft -> pending_review -> published -> closed -> archived.
+-- Bimbel aktif menjadi published, yang nonaktif menjadi archived.
+ALTER TABLE bimbels ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft';
+
+UPDATE bimbels SET status = CASE WHEN is_active THEN 'published' ELSE 'archived' END;
+
+ALTER TABLE bimbels DROP COLUMN is_active;
+CREATE INDEX idx_bimbels_status ON bimbels (status);
+
+-- Riwayat perubahan status; actor_user_id NULL berarti oleh sistem (mis. kuota penuh)
+CREATE TABLE IF NOT EXISTS bimbel_status_history (
+    id            BIGSERIAL    PRIMARY KEY,
+    bimbel_id     BIGINT       NOT NULL REFERENCES bimbels (id) ON DELETE CASCADE,
+    from_status   VARCHAR(20)  NULL,
+    to_status     VARCHAR(20)  NOT NULL,
+    actor_user_id BIGINT       NULL REFERENCES users (id) ON DELETE SET NULL,
+    actor_role    VARCHAR(20)  NOT NULL,
+    reason        VARCHAR(500) NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_bimbel_status_history_bimbel ON bimbel_status_history (bimbel_id, id);
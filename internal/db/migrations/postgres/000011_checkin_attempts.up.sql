@@ -0,0 +1,9 @@
+-- Jumlah kode check-in salah per peserta per sesi. Setelah batas tercapai
+-- peserta dikunci sampai tutor membuat kode baru (baris sesi dihapus).
+CREATE TABLE IF NOT EXISTS session_checkin_attempts (
+    session_id   BIGINT    NOT NULL REFERENCES bimbel_sessions (id) ON DELETE CASCADE,
+    peserta_id   BIGINT    NOT NULL REFERENCES pesertas (id) ON DELETE CASCADE,
+    failed_count INT       NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, peserta_id)
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS uq_bimbels_tutor_name;
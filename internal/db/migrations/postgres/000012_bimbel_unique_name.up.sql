@@ -0,0 +1,2 @@
+-- Nama bimbel unik per tutor di antara bimbel yang belum dihapus
+CREATE UNIQUE INDEX IF NOT EXISTS uq_bimbels_tutor_name ON bimbels (tutor_id, name) WHERE deleted_at IS NULL;
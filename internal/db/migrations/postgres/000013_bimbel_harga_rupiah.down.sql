@@ -0,0 +1,2 @@
+ALTER TABLE bimbels DROP CONSTRAINT IF EXISTS chk_bimbels_harga;
+ALTER TABLE bimbels ALTER COLUMN harga TYPE NUMERIC(12, 2);
@@ -0,0 +1,3 @@
+-- Harga bimbel disimpan dalam rupiah bulat, sama dengan invoices.amount
+ALTER TABLE bimbels ALTER COLUMN harga TYPE BIGINT USING ROUND(harga);
+ALTER TABLE bimbels ADD CONSTRAINT chk_bimbels_harga CHECK (harga >= 0);
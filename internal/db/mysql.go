@@ -1,26 +0,0 @@
-package db
-
-import (
-	"database/sql"
-	"fmt"
-	"main-service/config"
-
-	_ "github.com/go-sql-driver/mysql"
-)
-
-func NewMySQLConnection(cfg *config.Config) (*sql.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
-		cfg.DBUser, cfg.DBPass, cfg.DBHost, cfg.DBPort, cfg.DBName,
-	)
-
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		return nil, err
-	}
-
-	if err = db.Ping(); err != nil {
-		return nil, err
-	}
-
-	return db, nil
-}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// maxTxAttempts adalah jumlah percobaan WithTx saat transaksi terkena deadlock
+const maxTxAttempts = 3
+
+func (d *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := d.sql.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx, dialect: d.dialect}, nil
+}
+
+// WithTx menjalankan fn di dalam satu transaksi. Transaksi di-commit jika fn
+// mengembalikan nil, dan di-rollback jika fn mengembalikan error atau panic.
+// Deadlock / konflik serialisasi diulang sampai maxTxAttempts kali, jadi fn
+// harus aman dijalankan ulang (jangan memanggil layanan eksternal di dalamnya).
+func (d *DB) WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	for attempt := 1; ; attempt++ {
+		err := d.runTx(ctx, fn)
+		if err == nil || attempt == maxTxAttempts || !d.dialect.IsRetryable(err) {
+			return err
+		}
+
+		// Beri jeda acak supaya transaksi yang bentrok tidak bertemu lagi
+		backoff := time.Duration(attempt)*20*time.Millisecond + rand.N(20*time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (d *DB) runTx(ctx context.Context, fn func(tx *Tx) error) error {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return tx.run(fn)
+}
+
+// Tx adalah pasangan DB untuk *sql.Tx. BeginTx/WithTx pada Tx membuat
+// SAVEPOINT, sehingga repository yang membuka transaksi sendiri tetap ikut
+// transaksi luar ketika dipanggil lewat TxManager.
+type Tx struct {
+	tx        *sql.Tx
+	dialect   Dialect
+	savepoint string // kosong untuk transaksi utama
+	depth     int
+	done      bool
+}
+
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, t.dialect.Rebind(query), utcArgs(args)...)
+}
+
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, t.dialect.Rebind(query), utcArgs(args)...)
+}
+
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.tx.QueryRowContext(ctx, t.dialect.Rebind(query), utcArgs(args)...)
+}
+
+func (t *Tx) InsertIDContext(ctx context.Context, query string, args ...any) (uint64, error) {
+	return insertID(ctx, t.dialect, t.tx.ExecContext, t.tx.QueryRowContext, query, args)
+}
+
+// BeginTx membuat transaksi bersarang berupa SAVEPOINT. opts diabaikan karena
+// isolation level mengikuti transaksi luar.
+func (t *Tx) BeginTx(ctx context.Context, _ *sql.TxOptions) (*Tx, error) {
+	depth := t.depth + 1
+	name := fmt.Sprintf("sp_%d", depth)
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, err
+	}
+	return &Tx{tx: t.tx, dialect: t.dialect, savepoint: name, depth: depth}, nil
+}
+
+// WithTx pada Tx menjalankan fn di dalam SAVEPOINT. Tidak ada retry di sini:
+// deadlock membatalkan seluruh transaksi, jadi hanya WithTx terluar yang mengulang.
+func (t *Tx) WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	nested, err := t.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return nested.run(fn)
+}
+
+func (t *Tx) run(fn func(tx *Tx) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = t.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(t); err != nil {
+		_ = t.Rollback()
+		return err
+	}
+	return t.Commit()
+}
+
+func (t *Tx) Commit() error {
+	if t.savepoint == "" {
+		return t.tx.Commit()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.tx.Exec("RELEASE SAVEPOINT " + t.savepoint)
+	return err
+}
+
+// Rollback aman dipanggil lewat defer setelah Commit (mengembalikan sql.ErrTxDone)
+func (t *Tx) Rollback() error {
+	if t.savepoint == "" {
+		return t.tx.Rollback()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.tx.Exec("ROLLBACK TO SAVEPOINT " + t.savepoint)
+	return err
+}
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AttendanceHandler struct {
+	Usecase usecase.AttendanceUsecase
+}
+
+func NewAttendanceHandler(u usecase.AttendanceUsecase) *AttendanceHandler {
+	return &AttendanceHandler{Usecase: u}
+}
+
+// ✅ Daftar semua route handler
+func (h *AttendanceHandler) RegisterRoutes(api fiber.Router) {
+	session := api.Group("/bimbels/:id/sessions/:session_id")
+	session.Post("/checkin-code", h.GenerateCheckinCode)
+	session.Post("/checkin", h.CheckIn)
+	session.Get("/attendances", h.Roster)
+	session.Put("/attendances", h.Mark)
+
+	api.Get("/bimbels/:id/attendances/summary", h.BimbelSummary)
+	api.Get("/bimbels/:id/attendances/pesertas/:peserta_id", h.PesertaSummary)
+}
+
+// parseSessionParams membaca :id dan :session_id dari URL
+func parseSessionParams(c *fiber.Ctx) (uint64, uint64, error) {
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, domain.InvalidParam("id")
+	}
+	sessionID, err := strconv.ParseUint(c.Params("session_id"), 10, 64)
+	if err != nil {
+		return 0, 0, domain.InvalidParam("session_id")
+	}
+	return bimbelID, sessionID, nil
+}
+
+// ✅ GENERATE KODE ABSENSI (tutor/admin)
+func (h *AttendanceHandler) GenerateCheckinCode(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, sessionID, err := parseSessionParams(c)
+	if err != nil {
+		return err
+	}
+
+	// Body opsional; tanpa expires_in_minutes usecase memakai masa berlaku default
+	var req struct {
+		ExpiresInMinutes int `json:"expires_in_minutes" validate:"min=0,max=60"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	code, err := h.Usecase.GenerateCheckinCode(c.UserContext(), principal.Role, principal.TutorID, bimbelID, sessionID, time.Duration(req.ExpiresInMinutes)*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusCreated, "Kode absensi berhasil dibuat", code)
+}
+
+// ✅ CHECK-IN MANDIRI PESERTA
+func (h *AttendanceHandler) CheckIn(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, sessionID, err := parseSessionParams(c)
+	if err != nil {
+		return err
+	}
+
+	if principal.PesertaID == 0 {
+		return domain.ErrNoPesertaProfile
+	}
+
+	var req struct {
+		Code string `json:"code" validate:"required"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	attendance, err := h.Usecase.CheckIn(c.UserContext(), principal.Role, principal.PesertaID, bimbelID, sessionID, req.Code)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Absensi berhasil", attendance)
+}
+
+// ✅ DAFTAR KEHADIRAN SATU SESI
+func (h *AttendanceHandler) Roster(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, sessionID, err := parseSessionParams(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.Roster(c.UserContext(), principal.Role, principal.TutorID, bimbelID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Daftar kehadiran ditemukan", data)
+}
+
+// ✅ INPUT / EDIT KEHADIRAN (tutor pemilik/admin)
+func (h *AttendanceHandler) Mark(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, sessionID, err := parseSessionParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		Items []struct {
+			PesertaID uint64 `json:"peserta_id" validate:"required"`
+			Status    string `json:"status" validate:"required,oneof=present absent excused late"`
+			Note      string `json:"note" validate:"max=255"`
+		} `json:"items" validate:"required"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	items := make([]domain.Attendance, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, domain.Attendance{
+			PesertaID: item.PesertaID,
+			Status:    item.Status,
+			Note:      item.Note,
+		})
+	}
+
+	data, err := h.Usecase.Mark(c.UserContext(), principal.Role, principal.TutorID, principal.UserID, bimbelID, sessionID, items)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Kehadiran berhasil disimpan", data)
+}
+
+// ✅ REKAP KEHADIRAN PER BIMBEL
+func (h *AttendanceHandler) BimbelSummary(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.BimbelSummary(c.UserContext(), principal.Role, principal.TutorID, bimbelID)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Rekap kehadiran bimbel ditemukan", data)
+}
+
+// ✅ REKAP KEHADIRAN PER PESERTA
+func (h *AttendanceHandler) PesertaSummary(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+	pesertaID, err := strconv.ParseUint(c.Params("peserta_id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("peserta_id")
+	}
+
+	data, err := h.Usecase.PesertaSummary(c.UserContext(), principal.Role, principal.TutorID, bimbelID, pesertaID)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Rekap kehadiran peserta ditemukan", data)
+}
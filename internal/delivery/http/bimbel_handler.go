@@ -2,25 +2,28 @@ package http
 
 import (
 	"fmt"
+	"main-service/internal/auth"
 	"main-service/internal/domain"
-	"main-service/internal/repository"
+	"main-service/internal/imaging"
+	"main-service/internal/middleware"
+	"main-service/internal/policy"
+	"main-service/internal/storage"
 	"main-service/internal/usecase"
-	"os"
-	"path/filepath"
+	"main-service/internal/validation"
+	"mime/multipart"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type BimbelHandler struct {
-	Usecase  usecase.BimbelUsecase
-	UserRepo repository.UserRepository
+	Usecase usecase.BimbelUsecase
+	Store   storage.BlobStore
 }
 
-func NewBimbelHandler(u usecase.BimbelUsecase, ur repository.UserRepository) *BimbelHandler {
-	return &BimbelHandler{Usecase: u, UserRepo: ur}
+func NewBimbelHandler(u usecase.BimbelUsecase, store storage.BlobStore) *BimbelHandler {
+	return &BimbelHandler{Usecase: u, Store: store}
 }
 
 // ✅ Daftar semua route handler
@@ -30,236 +33,416 @@ func (h *BimbelHandler) RegisterRoutes(api fiber.Router) {
 	bimbels.Put("/:id", h.Update)
 	bimbels.Delete("/:id", h.Delete)
 	bimbels.Get("/show/:id", h.GetDetail)
+	bimbels.Get("/trash", middleware.Authorize(policy.BimbelTrash), h.Trash)
+	bimbels.Post("/:id/restore", middleware.Authorize(policy.BimbelTrash), h.Restore)
+
+	// Siklus hidup: draft -> (pending_review) -> published -> closed / archived
+	bimbels.Get("/:id/history", h.History)
+	bimbels.Post("/:id/submit", h.Submit)
+	bimbels.Post("/:id/withdraw", h.Withdraw)
+	bimbels.Post("/:id/publish", h.Publish)
+	bimbels.Post("/:id/reject", middleware.Authorize(policy.BimbelModerate), h.Reject)
+	bimbels.Post("/:id/close", h.Close)
+	bimbels.Post("/:id/archive", h.Archive)
+	bimbels.Post("/:id/unarchive", h.Unarchive)
 }
 
-// ✅ Helper standardized response
-func jsonError(c *fiber.Ctx, code int, msg string) error {
-	return c.Status(code).JSON(fiber.Map{
-		"status_code": code,
-		"status":      "error",
-		"message":     msg,
-	})
+// ✅ Route publik (tanpa login)
+func (h *BimbelHandler) RegisterPublicRoutes(api fiber.Router) {
+	api.Get("/bimbels", h.List)
 }
 
-func jsonSuccess(c *fiber.Ctx, code int, msg string, data any) error {
-	return c.Status(code).JSON(fiber.Map{
-		"status_code": code,
-		"status":      "success",
-		"message":     msg,
-		"data":        data,
-	})
+// bimbelForm adalah body multipart untuk create/update bimbel.
+// limit_peserta dan tutor_id hanya dipakai saat create. harga 0 berarti
+// bimbel gratis (pendaftaran langsung aktif tanpa invoice).
+type bimbelForm struct {
+	Name         string                `form:"name" validate:"required,max=150"`
+	Deskripsi    string                `form:"deskripsi" validate:"required"`
+	Harga        *int64                `form:"harga" validate:"required,min=0"`
+	FeatureID    uint64                `form:"feature_id" validate:"required"`
+	SubjectID    uint64                `form:"subject_id" validate:"required"`
+	LimitPeserta int                   `form:"limit_peserta" validate:"min=0"`
+	TutorID      uint64                `form:"tutor_id"`
+	Thumbnail    *multipart.FileHeader `form:"thumbnail"`
 }
 
 // ✅ CREATE BIMBEL
 func (h *BimbelHandler) Create(c *fiber.Ctx) error {
-	role, _ := c.Locals("role").(string)
-	userID, _ := c.Locals("user_id").(uint64)
-
-	// Ambil data dari form
-	name := strings.TrimSpace(c.FormValue("name"))
-	deskripsi := strings.TrimSpace(c.FormValue("deskripsi"))
-	harga, _ := strconv.ParseFloat(c.FormValue("harga"), 64)
-	featureID, _ := strconv.ParseUint(c.FormValue("feature_id"), 10, 64)
-	subjectID, _ := strconv.ParseUint(c.FormValue("subject_id"), 10, 64)
-	limitPeserta, _ := strconv.Atoi(c.FormValue("limit_peserta"))
-	tutorIDForm := c.FormValue("tutor_id")
-
-	// Upload thumbnail
-	thumbnailPath, err := saveThumbnail(c)
+	principal, err := auth.PrincipalFrom(c)
 	if err != nil {
-		return jsonError(c, fiber.StatusBadRequest, err.Error())
+		return err
 	}
 
-	// Validasi field wajib
-	if name == "" || deskripsi == "" || harga <= 0 || subjectID == 0 || featureID == 0 {
-		os.Remove(thumbnailPath)
-		return jsonError(c, fiber.StatusBadRequest, "name, deskripsi, harga, feature_id, dan subject_id wajib diisi")
+	var req bimbelForm
+	err = bindForm(c, &req)
+	if req.Thumbnail == nil {
+		err = validation.With(err, "thumbnail", "thumbnail wajib diupload")
+	}
+	if err != nil {
+		return err
 	}
 
 	// Tentukan tutor_id
 	var tutorID uint64
-	if role == "tutor" {
-		user, err := h.UserRepo.FindTutorIDByUserID(userID)
-		if err != nil {
-			return jsonError(c, fiber.StatusInternalServerError, "gagal mengambil data user")
+	if principal.Role == domain.RoleTutor {
+		if principal.TutorID == 0 {
+			return domain.ErrNoTutorProfile
 		}
-		if user.TutorID == nil {
-			return jsonError(c, fiber.StatusBadRequest, "user belum memiliki tutor_id")
+		tutorID = principal.TutorID
+	} else if principal.Role == domain.RoleAdmin {
+		if req.TutorID == 0 {
+			return domain.InvalidField("tutor_id", "tutor_id wajib diisi oleh admin")
 		}
-		tutorID = *user.TutorID
-	} else if role == "admin" {
-		if tutorIDForm == "" {
-			os.Remove(thumbnailPath)
-			return jsonError(c, fiber.StatusBadRequest, "tutor_id wajib diisi oleh admin")
-		}
-		tid, err := strconv.ParseUint(tutorIDForm, 10, 64)
-		if err != nil {
-			os.Remove(thumbnailPath)
-			return jsonError(c, fiber.StatusBadRequest, "tutor_id tidak valid")
-		}
-		tutorID = tid
+		tutorID = req.TutorID
 	} else {
-		os.Remove(thumbnailPath)
-		return jsonError(c, fiber.StatusForbidden, "role tidak memiliki akses untuk membuat bimbel")
+		return domain.Forbidden(domain.CodeForbidden, "role tidak memiliki akses untuk membuat bimbel")
 	}
 
-	// Cek nama duplikat
-	exists, err := h.Usecase.IsDuplicateName(name, tutorID)
-	if err != nil {
-		os.Remove(thumbnailPath)
-		return jsonError(c, fiber.StatusInternalServerError, err.Error())
+	// Izin dicek sebelum gambar diproses supaya request yang ditolak tidak
+	// meninggalkan file di storage
+	if err := h.Usecase.AuthorizeCreate(principal.Role, principal.TutorID, tutorID); err != nil {
+		return err
 	}
-	if exists {
-		os.Remove(thumbnailPath)
-		return jsonError(c, fiber.StatusConflict, "nama bimbel sudah digunakan")
+
+	// Upload thumbnail
+	thumbnailKey, variants, err := h.saveThumbnail(c, req.Thumbnail)
+	if err != nil {
+		return err
 	}
+	discard := func() { discardThumbnail(c.UserContext(), h.Store, thumbnailKey, variants) }
 
-	// Simpan ke database
+	// Simpan ke database; nama duplikat dicek usecase di dalam transaksi
 	bimbel := &domain.Bimbel{
-		TutorID:      tutorID,
-		FeatureID:    featureID,
-		SubjectID:    subjectID,
-		Name:         name,
-		Deskripsi:    deskripsi,
-		Thumbnail:    thumbnailPath,
-		Harga:        harga,
-		IsActive:     true,
-		LimitPeserta: limitPeserta,
+		TutorID:           tutorID,
+		FeatureID:         req.FeatureID,
+		SubjectID:         req.SubjectID,
+		Name:              strings.TrimSpace(req.Name),
+		Deskripsi:         strings.TrimSpace(req.Deskripsi),
+		Thumbnail:         thumbnailKey,
+		ThumbnailVariants: variants,
+		Harga:             *req.Harga,
+		LimitPeserta:      req.LimitPeserta,
 	}
 
-	if err := h.Usecase.Create(role, tutorID, bimbel); err != nil {
-		os.Remove(thumbnailPath)
-		return jsonError(c, fiber.StatusInternalServerError, err.Error())
+	if err := h.Usecase.Create(c.UserContext(), principal.Role, principal.UserID, principal.TutorID, bimbel); err != nil {
+		discard()
+		return err
 	}
 
-	return jsonSuccess(c, fiber.StatusCreated, "Bimbel berhasil dibuat", bimbel)
+	resolveBimbel(h.Store, bimbel)
+	return jsonSuccess(c, fiber.StatusCreated, "Bimbel berhasil dibuat sebagai draft", bimbel)
 }
 
 // ✅ SAVE THUMBNAIL
-func saveThumbnail(c *fiber.Ctx) (string, error) {
-	file, err := c.FormFile("thumbnail")
+// Gambar divalidasi & di-resize ke beberapa varian JPEG/WebP. Thumbnail utama
+// adalah varian JPEG terbesar; semua dikembalikan sebagai object key.
+func (h *BimbelHandler) saveThumbnail(c *fiber.Ctx, file *multipart.FileHeader) (string, []domain.ImageVariant, error) {
+	f, err := file.Open()
 	if err != nil {
-		return "", fmt.Errorf("thumbnail wajib diupload")
+		return "", nil, fmt.Errorf("gagal membaca file upload: %w", err)
 	}
+	defer f.Close()
 
-	// Validasi ekstensi file
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
-		return "", fmt.Errorf("format thumbnail harus jpg, jpeg, atau png")
+	processed, err := imaging.Process(f, imaging.ThumbnailOptions)
+	if err != nil {
+		if isImageValidationError(err) {
+			return "", nil, domain.InvalidField("thumbnail", err.Error())
+		}
+		return "", nil, err
 	}
 
-	// Tentukan direktori penyimpanan absolut
-	wd, _ := os.Getwd() // direktori project
-	uploadDir := filepath.Join(wd, "uploads", "thumbnails")
-
-	// Buat folder jika belum ada
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-		return "", fmt.Errorf("gagal membuat folder upload: %v", err)
+	variants, err := putImageVariants(c.UserContext(), h.Store, storage.NewKey("thumbnails", "bimbel", ""), processed)
+	if err != nil {
+		return "", nil, err
 	}
 
-	// Buat nama file unik
-	filename := fmt.Sprintf("bimbel_%d%s", time.Now().UnixNano(), ext)
-	fullPath := filepath.Join(uploadDir, filename)
-
-	// Simpan file ke disk
-	if err := c.SaveFile(file, fullPath); err != nil {
-		return "", fmt.Errorf("gagal menyimpan file thumbnail: %v", err)
+	mainKey := ""
+	for _, v := range variants {
+		if v.Format == imaging.FormatJPEG {
+			mainKey = v.Key // urut dari yang terkecil, jadi yang terakhir terbesar
+		}
 	}
-
-	// Buat URL publik
-	baseURL := fmt.Sprintf("%s://%s", c.Protocol(), c.Hostname())
-	publicURL := fmt.Sprintf("%s/uploads/thumbnails/%s", baseURL, filename)
-
-	return publicURL, nil
+	return mainKey, variants, nil
 }
 
 // ✅ UPDATE BIMBEL
 func (h *BimbelHandler) Update(c *fiber.Ctx) error {
-	role := c.Locals("role").(string)
-	userTutorID := c.Locals("tutor_id").(uint64)
-	id, _ := strconv.ParseUint(c.Params("id"), 10, 64)
-
-	existing, err := h.Usecase.FindByID(role, userTutorID, id)
+	principal, err := auth.PrincipalFrom(c)
 	if err != nil {
-		return jsonError(c, fiber.StatusNotFound, err.Error())
+		return err
 	}
 
-	featureID, _ := strconv.ParseUint(c.FormValue("feature_id"), 10, 64)
-	subjectID, _ := strconv.ParseUint(c.FormValue("subject_id"), 10, 64)
-	name := c.FormValue("name")
-	deskripsi := c.FormValue("deskripsi")
-	harga, _ := strconv.ParseFloat(c.FormValue("harga"), 64)
-
-	if name == "" || deskripsi == "" || harga <= 0 || subjectID == 0 {
-		return jsonError(c, fiber.StatusBadRequest, "name, deskripsi, subject_id, dan harga wajib diisi")
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
 	}
 
-	thumbnail := existing.Thumbnail
-	file, _ := c.FormFile("thumbnail")
+	// Kepemilikan dicek sebelum thumbnail baru diproses dan disimpan
+	existing, err := h.Usecase.FindForUpdate(c.UserContext(), principal.Role, principal.TutorID, id)
+	if err != nil {
+		return err
+	}
 
-	if file != nil {
-		// Upload thumbnail baru
-		newThumb, err := saveThumbnail(c)
-		if err != nil {
-			return jsonError(c, fiber.StatusBadRequest, err.Error())
-		}
+	var form bimbelForm
+	if err := bindForm(c, &form); err != nil {
+		return err
+	}
 
-		// Hapus file lama (jika ada)
-		if existing.Thumbnail != "" {
-			// Ambil nama file dari URL lama
-			parts := strings.Split(existing.Thumbnail, "/uploads/")
-			if len(parts) == 2 {
-				localPath := filepath.Join("uploads", parts[1])
-				_ = os.Remove(localPath) // hapus file fisik
-			}
+	// Upload thumbnail baru; file lama baru dihapus setelah update berhasil
+	thumbnail, variants := existing.Thumbnail, existing.ThumbnailVariants
+	newThumb := ""
+	var newVariants []domain.ImageVariant
+	if form.Thumbnail != nil {
+		if newThumb, newVariants, err = h.saveThumbnail(c, form.Thumbnail); err != nil {
+			return err
 		}
-
-		// Update ke thumbnail baru
-		thumbnail = newThumb
+		thumbnail, variants = newThumb, newVariants
 	}
 
 	req := &domain.Bimbel{
-		ID:           id,
-		FeatureID:    featureID,
-		SubjectID:    subjectID,
-		Name:         name,
-		LimitPeserta: existing.LimitPeserta,
-		IsActive:     existing.IsActive,
-		Thumbnail:    thumbnail,
-		Deskripsi:    deskripsi,
-		Harga:        harga,
+		ID:                id,
+		FeatureID:         form.FeatureID,
+		SubjectID:         form.SubjectID,
+		Name:              strings.TrimSpace(form.Name),
+		LimitPeserta:      existing.LimitPeserta,
+		Status:            existing.Status,
+		Thumbnail:         thumbnail,
+		ThumbnailVariants: variants,
+		Deskripsi:         strings.TrimSpace(form.Deskripsi),
+		Harga:             *form.Harga,
 	}
 
-	if err := h.Usecase.Update(role, userTutorID, req); err != nil {
-		return jsonError(c, fiber.StatusBadRequest, err.Error())
+	if err := h.Usecase.Update(c.UserContext(), principal.Role, principal.TutorID, req); err != nil {
+		discardThumbnail(c.UserContext(), h.Store, newThumb, newVariants)
+		return err
+	}
+	if newThumb != "" {
+		discardThumbnail(c.UserContext(), h.Store, existing.Thumbnail, existing.ThumbnailVariants)
 	}
 
+	resolveBimbel(h.Store, req)
 	return jsonSuccess(c, fiber.StatusOK, "Bimbel berhasil diperbarui", req)
 }
 
 // ✅ DELETE BIMBEL
 func (h *BimbelHandler) Delete(c *fiber.Ctx) error {
-	role := c.Locals("role").(string)
-	userTutorID := c.Locals("tutor_id").(uint64)
-	id, _ := strconv.ParseUint(c.Params("id"), 10, 64)
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
 
-	if err := h.Usecase.Delete(role, userTutorID, id); err != nil {
-		return jsonError(c, fiber.StatusBadRequest, err.Error())
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	if err := h.Usecase.Delete(c.UserContext(), principal.Role, principal.TutorID, id); err != nil {
+		return err
 	}
 
 	return jsonSuccess(c, fiber.StatusOK, "Bimbel berhasil dihapus", nil)
 }
 
+// ✅ TRASH BIMBEL (admin)
+func (h *BimbelHandler) Trash(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	items, err := h.Usecase.Trash(c.UserContext(), principal.Role)
+	if err != nil {
+		return err
+	}
+
+	resolveBimbels(h.Store, items)
+	return jsonSuccess(c, fiber.StatusOK, "Daftar bimbel di trash", items)
+}
+
+// ✅ RESTORE BIMBEL (admin)
+func (h *BimbelHandler) Restore(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.Restore(c.UserContext(), principal.Role, id)
+	if err != nil {
+		return err
+	}
+
+	resolveBimbel(h.Store, data)
+	return jsonSuccess(c, fiber.StatusOK, "Bimbel berhasil dipulihkan", data)
+}
+
+// ✅ SUBMIT BIMBEL (draft -> pending_review)
+func (h *BimbelHandler) Submit(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.BimbelActionSubmit, false, "Bimbel berhasil diajukan untuk review")
+}
+
+// ✅ WITHDRAW BIMBEL (pending_review -> draft)
+func (h *BimbelHandler) Withdraw(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.BimbelActionWithdraw, false, "Pengajuan review bimbel dibatalkan")
+}
+
+// ✅ PUBLISH BIMBEL (draft / pending_review / closed -> published)
+func (h *BimbelHandler) Publish(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.BimbelActionPublish, false, "Bimbel berhasil dipublikasikan")
+}
+
+// ✅ REJECT BIMBEL (admin, pending_review -> draft)
+func (h *BimbelHandler) Reject(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.BimbelActionReject, true, "Pengajuan bimbel ditolak")
+}
+
+// ✅ CLOSE BIMBEL (published -> closed)
+func (h *BimbelHandler) Close(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.BimbelActionClose, false, "Pendaftaran bimbel ditutup")
+}
+
+// ✅ ARCHIVE BIMBEL (draft / published / closed -> archived)
+func (h *BimbelHandler) Archive(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.BimbelActionArchive, false, "Bimbel berhasil diarsipkan")
+}
+
+// ✅ UNARCHIVE BIMBEL (archived -> draft)
+func (h *BimbelHandler) Unarchive(c *fiber.Ctx) error {
+	return h.changeStatus(c, domain.BimbelActionUnarchive, false, "Bimbel dikembalikan menjadi draft")
+}
+
+// changeStatus dipakai semua endpoint siklus hidup. Body opsional
+// {"reason": "..."}; alasan wajib diisi jika needReason.
+func (h *BimbelHandler) changeStatus(c *fiber.Ctx, action domain.BimbelAction, needReason bool, message string) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	var req struct {
+		Reason string `json:"reason" validate:"max=500"`
+	}
+	err = bindJSON(c, &req)
+	req.Reason = strings.TrimSpace(req.Reason)
+	if needReason && req.Reason == "" {
+		err = validation.With(err, "reason", "reason wajib diisi")
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.ChangeStatus(c.UserContext(), principal.Role, principal.UserID, principal.TutorID, id, action, req.Reason)
+	if err != nil {
+		return err
+	}
+
+	resolveBimbel(h.Store, data)
+	return jsonSuccess(c, fiber.StatusOK, message, data)
+}
+
+// ✅ RIWAYAT STATUS BIMBEL (pemilik & admin)
+func (h *BimbelHandler) History(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	history, err := h.Usecase.StatusHistory(c.UserContext(), principal.Role, principal.TutorID, id)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Riwayat status bimbel ditemukan", history)
+}
+
 // ✅ GET DETAIL
 func (h *BimbelHandler) GetDetail(c *fiber.Ctx) error {
-	role := c.Locals("role").(string)
-	userTutorID := c.Locals("tutor_id").(uint64)
-	id, _ := strconv.ParseUint(c.Params("id"), 10, 64)
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
 
-	data, err := h.Usecase.FindByID(role, userTutorID, id)
+	data, err := h.Usecase.FindByID(c.UserContext(), principal.Role, principal.TutorID, id)
 	if err != nil {
-		return jsonError(c, fiber.StatusNotFound, err.Error())
+		return err
 	}
 
+	resolveBimbel(h.Store, data)
 	return jsonSuccess(c, fiber.StatusOK, "Detail bimbel ditemukan", data)
 }
+
+// ✅ KATALOG BIMBEL (publik)
+func (h *BimbelHandler) List(c *fiber.Ctx) error {
+	filter := domain.BimbelFilter{Sort: c.Query("sort")}
+
+	uintParams := map[string]*uint64{
+		"feature_id": &filter.FeatureID,
+		"subject_id": &filter.SubjectID,
+		"tutor_id":   &filter.TutorID,
+	}
+	for key, dst := range uintParams {
+		if v := c.Query(key); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return domain.InvalidParam(key)
+			}
+			*dst = n
+		}
+	}
+
+	// Harga dalam rupiah bulat; pecahan, NaN, dan Inf ditolak ParseInt
+	hargaParams := map[string]**int64{
+		"min_harga": &filter.MinHarga,
+		"max_harga": &filter.MaxHarga,
+	}
+	for key, dst := range hargaParams {
+		if v := c.Query(key); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 {
+				return domain.InvalidParam(key)
+			}
+			*dst = &n
+		}
+	}
+
+	intParams := map[string]*int{
+		"page":  &filter.Page,
+		"limit": &filter.Limit,
+	}
+	for key, dst := range intParams {
+		if v := c.Query(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return domain.InvalidParam(key)
+			}
+			*dst = n
+		}
+	}
+
+	page, err := h.Usecase.Catalog(c.UserContext(), filter)
+	if err != nil {
+		return err
+	}
+
+	resolveBimbels(h.Store, page.Items)
+	return jsonSuccess(c, fiber.StatusOK, "Katalog bimbel ditemukan", page)
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"main-service/internal/domain"
+	"main-service/internal/middleware"
+	"main-service/internal/policy"
+	"main-service/internal/storage"
+	"main-service/internal/usecase"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Parameter yang tidak valid harus ditolak 400 sebelum usecase dipanggil
+// (usecase nil di sini akan menjadi 500)
+func TestBimbelHandlerRejectsInvalidID(t *testing.T) {
+	app := newProtectedApp(t)
+	for _, tc := range []struct{ method, path string }{
+		{"GET", "/bimbels/show/abc"},
+		{"PUT", "/bimbels/abc"},
+		{"DELETE", "/bimbels/-1"},
+	} {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			status, body := doRequest(t, app, tc.method, tc.path, domain.RoleAdmin)
+			if status != fiber.StatusBadRequest {
+				t.Errorf("status = %d (%s), want 400", status, body.Message)
+			}
+		})
+	}
+}
+
+func TestBimbelCatalogRejectsInvalidHarga(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	NewBimbelHandler(nil, nil).RegisterPublicRoutes(app)
+
+	for _, q := range []string{
+		"min_harga=NaN", "max_harga=nan", "min_harga=Inf", "max_harga=-Inf",
+		"min_harga=1e309", "max_harga=-5", "min_harga=abc", "min_harga=1500.5",
+	} {
+		t.Run(q, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", "/bimbels?"+q, nil), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != fiber.StatusBadRequest {
+				t.Errorf("status = %d, want 400", resp.StatusCode)
+			}
+		})
+	}
+}
+
+// denyBimbelUsecase menolak semua akses seperti policy untuk non-pemilik
+type denyBimbelUsecase struct {
+	usecase.BimbelUsecase
+}
+
+func (denyBimbelUsecase) AuthorizeCreate(role string, userTutorID, tutorID uint64) error {
+	return policy.ErrForbidden
+}
+
+func (denyBimbelUsecase) FindForUpdate(ctx context.Context, role string, userTutorID, id uint64) (*domain.Bimbel, error) {
+	return nil, policy.ErrForbidden
+}
+
+// thumbnailRequest membuat request multipart bimbel lengkap dengan gambar PNG
+func thumbnailRequest(t *testing.T, method, path, role string) *http.Request {
+	t.Helper()
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewNRGBA(image.Rect(0, 0, 64, 64))); err != nil {
+		t.Fatal(err)
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fields := map[string]string{
+		"name": "Kelas Fisika", "deskripsi": "x", "harga": "0",
+		"feature_id": "1", "subject_id": "2", "tutor_id": "11",
+	}
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	part, err := w.CreateFormFile("thumbnail", "a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(img.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(method, "/api/v1"+path, &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+testToken(t, role))
+	return req
+}
+
+// Request yang ditolak policy tidak boleh meninggalkan file di storage
+func TestBimbelUploadAuthorizedBeforeStore(t *testing.T) {
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	root := t.TempDir()
+	store := storage.NewLocalStore(root, "http://app/uploads", "http://app/api/v1/files", "rahasia")
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	protected := app.Group("/api/v1")
+	protected.Use(middleware.AuthMiddleware(activeSessions{}))
+	NewBimbelHandler(denyBimbelUsecase{}, store).RegisterRoutes(protected)
+
+	for _, tc := range []struct{ method, path, role string }{
+		{"POST", "/bimbels/", domain.RoleTutor},
+		{"POST", "/bimbels/", domain.RoleAdmin},
+		{"PUT", "/bimbels/5", domain.RoleTutor},
+	} {
+		t.Run(tc.method+" "+tc.role, func(t *testing.T) {
+			resp, err := app.Test(thumbnailRequest(t, tc.method, tc.path, tc.role), -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != fiber.StatusForbidden {
+				t.Errorf("status = %d, want 403", resp.StatusCode)
+			}
+
+			entries, err := os.ReadDir(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("storage berisi %d entri, want kosong", len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"main-service/internal/domain"
+	"main-service/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// bindJSON mem-parse body JSON ke dst dan menjalankan aturan `validate`-nya.
+// Semua field yang gagal dikembalikan sekaligus sebagai error 422.
+func bindJSON(c *fiber.Ctx, dst any) error {
+	return validation.DecodeJSON(c.Body(), dst)
+}
+
+// bindForm sama seperti bindJSON untuk body multipart/form-data, memakai tag `form`
+func bindForm(c *fiber.Ctx, dst any) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return domain.ErrInvalidBody
+	}
+	return validation.DecodeForm(form, dst)
+}
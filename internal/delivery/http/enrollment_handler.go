@@ -0,0 +1,136 @@
+package http
+
+import (
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type EnrollmentHandler struct {
+	Usecase usecase.EnrollmentUsecase
+}
+
+func NewEnrollmentHandler(u usecase.EnrollmentUsecase) *EnrollmentHandler {
+	return &EnrollmentHandler{Usecase: u}
+}
+
+// ✅ Daftar semua route handler
+func (h *EnrollmentHandler) RegisterRoutes(api fiber.Router) {
+	api.Post("/bimbels/:id/enroll", h.Enroll)
+	api.Delete("/bimbels/:id/enroll", h.Cancel)
+	api.Get("/enrollments", h.ListMine)
+	api.Get("/bimbels/:id/enrollments", h.ListByBimbel)
+	api.Post("/bimbels/:id/enrollments/:enrollment_id/complete", h.Complete)
+}
+
+// ✅ ENROLL / JOIN ULANG BIMBEL
+func (h *EnrollmentHandler) Enroll(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	if principal.PesertaID == 0 {
+		return domain.ErrNoPesertaProfile
+	}
+
+	enrollment, err := h.Usecase.Enroll(c.UserContext(), principal.Role, principal.PesertaID, bimbelID)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusCreated, "Berhasil mendaftar bimbel", enrollment)
+}
+
+// ✅ CANCEL ENROLLMENT
+func (h *EnrollmentHandler) Cancel(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	if principal.PesertaID == 0 {
+		return domain.ErrNoPesertaProfile
+	}
+
+	if err := h.Usecase.Cancel(c.UserContext(), principal.Role, principal.PesertaID, bimbelID); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Pendaftaran bimbel dibatalkan", nil)
+}
+
+// ✅ LIST ENROLLMENT MILIK PESERTA
+func (h *EnrollmentHandler) ListMine(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	if principal.PesertaID == 0 {
+		return domain.ErrNoPesertaProfile
+	}
+
+	data, err := h.Usecase.ListMine(c.UserContext(), principal.Role, principal.PesertaID)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Daftar enrollment ditemukan", data)
+}
+
+// ✅ LIST PESERTA SATU BIMBEL (tutor pemilik/admin)
+func (h *EnrollmentHandler) ListByBimbel(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.ListByBimbel(c.UserContext(), principal.Role, principal.TutorID, bimbelID)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Daftar peserta bimbel ditemukan", data)
+}
+
+// ✅ TANDAI ENROLLMENT SELESAI (tutor pemilik/admin)
+func (h *EnrollmentHandler) Complete(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+	enrollmentID, err := strconv.ParseUint(c.Params("enrollment_id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("enrollment_id")
+	}
+
+	if err := h.Usecase.Complete(c.UserContext(), principal.Role, principal.TutorID, bimbelID, enrollmentID); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Enrollment ditandai selesai", nil)
+}
@@ -2,6 +2,10 @@ package http
 
 import (
 	"fmt"
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/middleware"
+	"main-service/internal/policy"
 	"main-service/internal/usecase"
 	"strconv"
 	"strings"
@@ -19,239 +23,141 @@ func NewFeatureHandler(uc usecase.FeatureUsecase) *FeatureHandler {
 
 func (h *FeatureHandler) RegisterRoutes(api fiber.Router) {
 	features := api.Group("/features")
-	features.Get("/", h.GetFeatures)
-	features.Post("/", h.Create)
-	features.Put("/:id", h.Update)
-	features.Delete("/:id", h.Delete)
-	features.Get("/show/:id", h.GetDetail)
+	features.Get("/", middleware.Authorize(policy.FeatureList), h.GetFeatures)
+	features.Post("/", middleware.Authorize(policy.FeatureCreate), h.Create)
+	features.Put("/:id", middleware.Authorize(policy.FeatureUpdate), h.Update)
+	features.Delete("/:id", middleware.Authorize(policy.FeatureDelete), h.Delete)
+	features.Get("/show/:id", middleware.Authorize(policy.FeatureView), h.GetDetail)
+	features.Get("/trash", middleware.Authorize(policy.FeatureTrash), h.Trash)
+	features.Post("/:id/restore", middleware.Authorize(policy.FeatureTrash), h.Restore)
 }
 
 func (h *FeatureHandler) GetFeatures(c *fiber.Ctx) error {
-	role := c.Locals("role")
-	if role == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status_code": fiber.StatusUnauthorized,
-			"status":      "error",
-			"message":     "unauthorized",
-		})
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
 	}
 
-	features, err := h.usecase.GetFeaturesByRole(role.(string))
+	features, err := h.usecase.GetFeaturesByRole(c.UserContext(), principal.Role)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status_code": fiber.StatusInternalServerError,
-			"status":      "error",
-			"message":     err.Error(),
-		})
+		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status_code": fiber.StatusOK,
-		"status":      "success",
-		"message":     "daftar fitur untuk role " + role.(string),
-		"data":        features,
-	})
+	return jsonSuccess(c, fiber.StatusOK, "daftar fitur untuk role "+principal.Role, features)
 }
 
 func (h *FeatureHandler) Create(c *fiber.Ctx) error {
-	role := c.Locals("role")
-	if role == nil || role.(string) != "admin" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status_code": fiber.StatusForbidden,
-			"status":      "error",
-			"message":     "forbidden: hanya admin yang dapat menambahkan mata pelajaran",
-		})
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
 	}
 
-	type request struct {
-		Name     string `json:"name"`
-		Roles    string `json:"roles"`
+	var req struct {
+		Name     string `json:"name" validate:"required,min=3,max=100"`
+		Roles    string `json:"roles" validate:"required,min=5"`
 		IsActive *bool  `json:"is_active"`
 	}
-
-	var req request
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "invalid request body",
-		})
+	if err := bindJSON(c, &req); err != nil {
+		return err
 	}
 
-	// Manual Validation + Trim
-	req.Name = strings.TrimSpace(req.Name)
-	if req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "name wajib diisi",
-		})
+	feature, err := h.usecase.Create(c.UserContext(), strings.TrimSpace(req.Name), strings.TrimSpace(req.Roles), req.IsActive, principal.Role)
+	if err != nil {
+		return err
 	}
 
-	if len(req.Name) < 3 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "nama minimal 3 karakter",
-		})
+	return jsonSuccess(c, fiber.StatusCreated, "fitur berhasil dibuat", feature)
+}
+
+func (h *FeatureHandler) Update(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
 	}
 
-	req.Roles = strings.TrimSpace(req.Roles)
-	if req.Roles == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "roles wajib diisi",
-		})
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
 	}
 
-	if len(req.Roles) < 5 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "roles minimal 5 karakter",
-		})
+	var req struct {
+		Name     string `json:"name" validate:"required,min=3,max=100"`
+		Roles    string `json:"roles" validate:"required,min=5"`
+		IsActive bool   `json:"is_active"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
 	}
 
-	feature, err := h.usecase.Create(req.Name, req.Roles, req.IsActive)
+	feature, err := h.usecase.Update(c.UserContext(), id, req.Name, req.Roles, req.IsActive, principal.Role)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status_code": fiber.StatusInternalServerError,
-			"status":      "error",
-			"message":     err.Error(),
-		})
+		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"status_code": fiber.StatusCreated,
-		"status":      "success",
-		"data":        feature,
-	})
+	return jsonSuccess(c, fiber.StatusOK, "fitur berhasil diperbarui", feature)
 }
 
-func (h *FeatureHandler) Update(c *fiber.Ctx) error {
-	role := c.Locals("role").(string)
-	if role != "admin" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status_code": fiber.StatusForbidden,
-			"status":      "error",
-			"message":     "akses ditolak, hanya admin yang dapat mengedit fitur",
-		})
+func (h *FeatureHandler) Delete(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
 	}
 
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "id tidak valid",
-		})
+		return domain.InvalidParam("id")
 	}
 
-	var req struct {
-		Name     string `json:"name"`
-		Roles    string `json:"roles"`
-		IsActive bool   `json:"is_active"`
-	}
-
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "input tidak valid",
-		})
+	if err := h.usecase.Delete(c.UserContext(), id, principal.Role); err != nil {
+		return err
 	}
 
-	if strings.TrimSpace(req.Name) == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "nama fitur wajib diisi",
-		})
-	}
+	return jsonSuccess(c, fiber.StatusOK, "fitur dan mata pelajarannya dipindahkan ke trash", nil)
+}
 
-	if strings.TrimSpace(req.Roles) == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "roles wajib diisi",
-		})
+func (h *FeatureHandler) GetDetail(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
 	}
 
-	feature, err := h.usecase.Update(id, req.Name, req.Roles, req.IsActive)
+	feature, err := h.usecase.GetDetail(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     err.Error(),
-		})
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status_code": fiber.StatusOK,
-		"status":      "success",
-		"message":     "fitur berhasil diperbarui",
-		"data":        feature,
-	})
+	return jsonSuccess(c, fiber.StatusOK, fmt.Sprintf("data detail dari id %d", id), feature)
 }
 
-func (h *FeatureHandler) Delete(c *fiber.Ctx) error {
-	role := c.Locals("role").(string)
-	if role != "admin" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status_code": fiber.StatusForbidden,
-			"status":      "error",
-			"message":     "akses ditolak, hanya admin yang dapat menghapus fitur",
-		})
-	}
-
-	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+func (h *FeatureHandler) Trash(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "id tidak valid",
-		})
+		return err
 	}
 
-	if err := h.usecase.Delete(id, role); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     err.Error(),
-		})
+	features, err := h.usecase.Trash(c.UserContext(), principal.Role)
+	if err != nil {
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status_code": fiber.StatusOK,
-		"status":      "success",
-		"message":     "fitur berhasil dihapus",
-	})
+	return jsonSuccess(c, fiber.StatusOK, "daftar fitur di trash", features)
 }
 
-func (h *FeatureHandler) GetDetail(c *fiber.Ctx) error {
+func (h *FeatureHandler) Restore(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "id tidak valid",
-		})
+		return domain.InvalidParam("id")
 	}
 
-	feature, err := h.usecase.GetDetail(id)
+	feature, err := h.usecase.Restore(c.UserContext(), id, principal.Role)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     err.Error(),
-		})
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status_code": fiber.StatusOK,
-		"status":      "success",
-		"message":     fmt.Sprintf("data detail dari id %d", id),
-		"data":        feature,
-	})
+	return jsonSuccess(c, fiber.StatusOK, "fitur berhasil dipulihkan", feature)
 }
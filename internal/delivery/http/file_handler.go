@@ -0,0 +1,52 @@
+package http
+
+import (
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/storage"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FileHandler melayani signed URL dari storage local. Untuk s3, signed URL
+// langsung mengarah ke bucket sehingga route ini tidak didaftarkan.
+type FileHandler struct {
+	Store storage.BlobStore
+}
+
+func NewFileHandler(store storage.BlobStore) *FileHandler {
+	return &FileHandler{Store: store}
+}
+
+// ✅ Route publik (akses dijaga tanda tangan di query string)
+func (h *FileHandler) RegisterPublicRoutes(api fiber.Router) {
+	if _, ok := h.Store.(storage.SignedURLVerifier); !ok {
+		return
+	}
+	api.Get("/files/*", h.Download)
+}
+
+// ✅ UNDUH FILE LEWAT SIGNED URL
+func (h *FileHandler) Download(c *fiber.Ctx) error {
+	key := c.Params("*")
+	verifier := h.Store.(storage.SignedURLVerifier)
+	if err := verifier.VerifySignedURL(key, c.Query("expires"), c.Query("signature")); err != nil {
+		return domain.Forbidden("SIGNED_URL_INVALID", "link tidak valid atau sudah kedaluwarsa")
+	}
+
+	rc, info, err := h.Store.Get(c.UserContext(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return domain.NotFound("FILE_NOT_FOUND", "file tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.ContentType != "" {
+		c.Set(fiber.HeaderContentType, info.ContentType)
+	}
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	// Store local mengembalikan *os.File yang tidak terikat context request,
+	// jadi aman di-stream setelah handler selesai; Fiber yang menutupnya
+	return c.SendStream(rc, int(info.Size))
+}
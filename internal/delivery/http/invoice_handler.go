@@ -0,0 +1,123 @@
+package http
+
+import (
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InvoiceHandler struct {
+	Usecase usecase.InvoiceUsecase
+}
+
+func NewInvoiceHandler(u usecase.InvoiceUsecase) *InvoiceHandler {
+	return &InvoiceHandler{Usecase: u}
+}
+
+// ✅ Daftar semua route handler
+func (h *InvoiceHandler) RegisterRoutes(api fiber.Router) {
+	invoices := api.Group("/invoices")
+	invoices.Get("/", h.List)
+	invoices.Get("/:id", h.GetDetail)
+	invoices.Post("/:id/cancel", h.Cancel)
+	invoices.Post("/:id/refund", h.Refund)
+	invoices.Post("/:id/simulate-payment", h.SimulatePayment)
+}
+
+// ✅ LIST INVOICE (peserta: miliknya, admin: semua)
+func (h *InvoiceHandler) List(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.List(c.UserContext(), principal.Role, principal.PesertaID, c.Query("status"))
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Daftar invoice ditemukan", data)
+}
+
+// ✅ DETAIL INVOICE
+func (h *InvoiceHandler) GetDetail(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.Detail(c.UserContext(), principal.Role, principal.PesertaID, id)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Detail invoice ditemukan", data)
+}
+
+// ✅ CANCEL INVOICE (enrollment pending ikut dibatalkan)
+func (h *InvoiceHandler) Cancel(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.Cancel(c.UserContext(), principal.Role, principal.PesertaID, id)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Invoice dibatalkan", data)
+}
+
+// ✅ REFUND INVOICE (admin)
+func (h *InvoiceHandler) Refund(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.Refund(c.UserContext(), principal.Role, id)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Invoice berhasil direfund", data)
+}
+
+// ✅ SIMULASI PEMBAYARAN (khusus provider fake)
+func (h *InvoiceHandler) SimulatePayment(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.SimulatePayment(c.UserContext(), principal.Role, principal.PesertaID, id)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Pembayaran berhasil disimulasikan", data)
+}
@@ -2,6 +2,10 @@ package http
 
 import (
 	"fmt"
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/middleware"
+	"main-service/internal/policy"
 	"main-service/internal/usecase"
 	"strconv"
 	"strings"
@@ -19,225 +23,144 @@ func NewMatpelHandler(uc usecase.MatpelUsecase) *MatpelHandler {
 
 func (h *MatpelHandler) RegisterRoutes(api fiber.Router) {
 	subjects := api.Group("/matpels")
-	subjects.Post("/", h.Create)
-	subjects.Put("/:id", h.Update)
-	subjects.Get("/:feature_id", h.GetByFeatureID)
-	subjects.Delete("/:id", h.Delete)
-	subjects.Get("/show/:id", h.GetDetail)
+	subjects.Post("/", middleware.Authorize(policy.MatpelCreate), h.Create)
+	subjects.Put("/:id", middleware.Authorize(policy.MatpelUpdate), h.Update)
+	subjects.Get("/trash", middleware.Authorize(policy.MatpelTrash), h.Trash) // sebelum /:feature_id
+	subjects.Post("/:id/restore", middleware.Authorize(policy.MatpelTrash), h.Restore)
+	subjects.Get("/:feature_id", middleware.Authorize(policy.MatpelList), h.GetByFeatureID)
+	subjects.Delete("/:id", middleware.Authorize(policy.MatpelDelete), h.Delete)
+	subjects.Get("/show/:id", middleware.Authorize(policy.MatpelView), h.GetDetail)
 }
 
 func (h *MatpelHandler) GetByFeatureID(c *fiber.Ctx) error {
 	idParam := c.Params("feature_id")
 	featureID, err := strconv.ParseUint(idParam, 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "invalid feature_id parameter",
-		})
+		return domain.InvalidParam("feature_id")
 	}
 
-	subjects, err := h.usecase.GetMatpelByFeature(featureID)
+	subjects, err := h.usecase.GetMatpelByFeature(c.UserContext(), featureID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status_code": fiber.StatusInternalServerError,
-			"status":      "error",
-			"message":     "failed to get matpels",
-		})
+		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status_code": fiber.StatusOK,
-		"status":      "success",
-		"data":        subjects,
-	})
+	return jsonSuccess(c, fiber.StatusOK, "daftar mata pelajaran ditemukan", subjects)
 }
 
 func (h *MatpelHandler) Create(c *fiber.Ctx) error {
-	role := c.Locals("role")
-	if role == nil || role.(string) != "admin" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status_code": fiber.StatusForbidden,
-			"status":      "error",
-			"message":     "forbidden: hanya admin yang dapat menambahkan mata pelajaran",
-		})
-	}
-
-	type request struct {
-		FeatureID uint64  `json:"feature_id"`
-		Name      string  `json:"name"`
-		Deskripsi *string `json:"deskripsi"`
-		IsActive  *bool   `json:"is_active"`
-	}
-
-	var req request
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "invalid request body",
-		})
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
 	}
 
-	// Manual Validation + Trim
-	req.Name = strings.TrimSpace(req.Name)
-	if req.FeatureID == 0 || req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "feature_id dan name wajib diisi",
-		})
+	var req struct {
+		FeatureID uint64  `json:"feature_id" validate:"required"`
+		Name      string  `json:"name" validate:"required,min=3,max=100"`
+		Deskripsi *string `json:"deskripsi"`
+		IsActive  *bool   `json:"is_active"`
 	}
-
-	if len(req.Name) < 3 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "nama minimal 3 karakter",
-		})
+	if err := bindJSON(c, &req); err != nil {
+		return err
 	}
 
-	subject, err := h.usecase.Create(req.FeatureID, req.Name, req.Deskripsi, req.IsActive)
+	subject, err := h.usecase.Create(c.UserContext(), req.FeatureID, strings.TrimSpace(req.Name), req.Deskripsi, req.IsActive, principal.Role)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status_code": fiber.StatusInternalServerError,
-			"status":      "error",
-			"message":     err.Error(),
-		})
+		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"status_code": fiber.StatusCreated,
-		"status":      "success",
-		"data":        subject,
-	})
+	return jsonSuccess(c, fiber.StatusCreated, "mata pelajaran berhasil dibuat", subject)
 }
 
 func (h *MatpelHandler) Update(c *fiber.Ctx) error {
-	role := c.Locals("role").(string)
-	if role != "admin" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status_code": fiber.StatusForbidden,
-			"status":      "error",
-			"message":     "akses ditolak, hanya admin yang dapat mengedit mata pelajaran",
-		})
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
 	}
 
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "id tidak valid",
-		})
+		return domain.InvalidParam("id")
 	}
 
 	var req struct {
-		FeatureID uint64  `json:"feature_id"`
-		Name      string  `json:"name"`
+		FeatureID uint64  `json:"feature_id" validate:"required"`
+		Name      string  `json:"name" validate:"required,min=3,max=100"`
 		Deskripsi *string `json:"deskripsi"`
 		IsActive  bool    `json:"is_active"`
 	}
-
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "input tidak valid",
-		})
+	if err := bindJSON(c, &req); err != nil {
+		return err
 	}
 
-	// Validasi dasar
-	if req.FeatureID == 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "feature_id wajib diisi",
-		})
+	matpel, err := h.usecase.Update(c.UserContext(), id, req.FeatureID, req.Name, req.Deskripsi, req.IsActive, principal.Role)
+	if err != nil {
+		return err
 	}
 
-	if strings.TrimSpace(req.Name) == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "nama mata pelajaran wajib diisi",
-		})
+	return jsonSuccess(c, fiber.StatusOK, "mata pelajaran berhasil diperbarui", matpel)
+}
+
+func (h *MatpelHandler) Delete(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
 	}
 
-	matpel, err := h.usecase.Update(id, req.FeatureID, req.Name, req.Deskripsi, req.IsActive)
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     err.Error(),
-		})
+		return domain.InvalidParam("id")
 	}
 
-	return c.JSON(fiber.Map{
-		"status_code": fiber.StatusOK,
-		"status":      "success",
-		"message":     "mata pelajaran berhasil diperbarui",
-		"data":        matpel,
-	})
+	if err := h.usecase.Delete(c.UserContext(), id, principal.Role); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "mata pelajaran berhasil dihapus", nil)
 }
 
-func (h *MatpelHandler) Delete(c *fiber.Ctx) error {
-	role := c.Locals("role").(string)
-	if role != "admin" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status_code": fiber.StatusForbidden,
-			"status":      "error",
-			"message":     "akses ditolak, hanya admin yang dapat menghapus mata pelajaran",
-		})
+func (h *MatpelHandler) GetDetail(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
 	}
 
-	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	matpel, err := h.usecase.GetDetail(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "id tidak valid",
-		})
+		return err
 	}
 
-	if err := h.usecase.Delete(id, role); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     err.Error(),
-		})
+	return jsonSuccess(c, fiber.StatusOK, fmt.Sprintf("data detail dari id %d", id), matpel)
+}
+
+func (h *MatpelHandler) Trash(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	subjects, err := h.usecase.Trash(c.UserContext(), principal.Role)
+	if err != nil {
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status_code": fiber.StatusOK,
-		"status":      "success",
-		"message":     "mata pelajaran berhasil dihapus",
-	})
+	return jsonSuccess(c, fiber.StatusOK, "daftar mata pelajaran di trash", subjects)
 }
 
-func (h *MatpelHandler) GetDetail(c *fiber.Ctx) error {
+func (h *MatpelHandler) Restore(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     "id tidak valid",
-		})
+		return domain.InvalidParam("id")
 	}
 
-	matpel, err := h.usecase.GetDetail(id)
+	matpel, err := h.usecase.Restore(c.UserContext(), id, principal.Role)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status_code": fiber.StatusBadRequest,
-			"status":      "error",
-			"message":     err.Error(),
-		})
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"status_code": fiber.StatusOK,
-		"status":      "success",
-		"message":     fmt.Sprintf("data detail dari id %d", id),
-		"data":        matpel,
-	})
+	return jsonSuccess(c, fiber.StatusOK, "mata pelajaran berhasil dipulihkan", matpel)
 }
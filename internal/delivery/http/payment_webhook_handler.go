@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/middleware"
+	"main-service/internal/policy"
+	"main-service/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentWebhookHandler struct {
+	Usecase usecase.PaymentWebhookUsecase
+}
+
+func NewPaymentWebhookHandler(u usecase.PaymentWebhookUsecase) *PaymentWebhookHandler {
+	return &PaymentWebhookHandler{Usecase: u}
+}
+
+// ✅ Route publik untuk payment gateway (tanpa JWT, diverifikasi dengan HMAC)
+func (h *PaymentWebhookHandler) RegisterRoutes(api fiber.Router) {
+	api.Post("/webhooks/payments/:provider", h.Receive)
+}
+
+// ✅ Route admin untuk audit & replay notifikasi
+func (h *PaymentWebhookHandler) RegisterAdminRoutes(api fiber.Router) {
+	notifications := api.Group("/admin/payment-notifications")
+	notifications.Get("/", middleware.Authorize(policy.PaymentNotificationList), h.List)
+	notifications.Post("/:id/replay", middleware.Authorize(policy.PaymentNotificationReplay), h.Replay)
+}
+
+// ✅ TERIMA WEBHOOK PEMBAYARAN
+func (h *PaymentWebhookHandler) Receive(c *fiber.Ctx) error {
+	signature := c.Get("X-Signature")
+	if signature == "" {
+		signature = c.Get("X-Callback-Signature")
+	}
+
+	n, err := h.Usecase.Receive(c.UserContext(), c.Params("provider"), signature, c.Body())
+	switch {
+	case errors.Is(err, domain.ErrInvalidSignature):
+		return err
+	case err != nil && n != nil && n.Result == domain.NotificationResultRejected:
+		return domain.NewError(domain.KindBadRequest, "INVALID_WEBHOOK_PAYLOAD", err.Error())
+	case err != nil:
+		// Sengaja tidak di-wrap dengan %w: apa pun penyebabnya harus menjadi
+		// status 5xx agar gateway mengirim ulang notifikasi
+		return fmt.Errorf("gagal memproses notifikasi pembayaran: %v", err)
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Notifikasi diterima", fiber.Map{
+		"id":     n.ID,
+		"result": n.Result,
+	})
+}
+
+// ✅ LIST NOTIFIKASI (admin)
+func (h *PaymentWebhookHandler) List(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	data, err := h.Usecase.List(c.UserContext(), principal.Role, c.Query("result"), limit)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Daftar notifikasi pembayaran", data)
+}
+
+// ✅ REPLAY NOTIFIKASI (admin)
+func (h *PaymentWebhookHandler) Replay(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	n, err := h.Usecase.Replay(c.UserContext(), principal.Role, id)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Notifikasi diproses ulang", n)
+}
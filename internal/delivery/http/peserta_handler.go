@@ -0,0 +1,89 @@
+package http
+
+import (
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PesertaHandler struct {
+	Usecase usecase.PesertaUsecase
+}
+
+func NewPesertaHandler(u usecase.PesertaUsecase) *PesertaHandler {
+	return &PesertaHandler{Usecase: u}
+}
+
+// ✅ Daftar semua route handler
+func (h *PesertaHandler) RegisterRoutes(api fiber.Router) {
+	api.Get("/peserta-profile", h.MyProfile)
+	api.Put("/peserta-profile", h.UpdateProfile)
+	api.Get("/pesertas/:id", h.Detail)
+}
+
+// ✅ PROFIL PESERTA SENDIRI
+func (h *PesertaHandler) MyProfile(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.MyProfile(c.UserContext(), principal.Role, principal.PesertaID)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Profil peserta ditemukan", data)
+}
+
+// ✅ UPDATE PROFIL PESERTA
+func (h *PesertaHandler) UpdateProfile(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	var req usecase.PesertaProfileInput
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.UpdateProfile(c.UserContext(), principal.Role, principal.PesertaID, req)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Profil peserta berhasil diperbarui", data)
+}
+
+// ✅ DETAIL PESERTA (admin: lengkap, tutor: terbatas)
+func (h *PesertaHandler) Detail(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	var data any
+	switch {
+	case policy.Allowed(principal.Role, policy.PesertaView):
+		data, err = h.Usecase.Detail(c.UserContext(), principal.Role, id)
+	case policy.Allowed(principal.Role, policy.PesertaViewLimited):
+		data, err = h.Usecase.LimitedDetail(c.UserContext(), principal.Role, principal.TutorID, id)
+	default:
+		err = policy.ErrForbidden
+	}
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Profil peserta ditemukan", data)
+}
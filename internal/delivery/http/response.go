@@ -1,31 +1,93 @@
 package http
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"main-service/internal/domain"
 
-type SuccessResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-}
+	"github.com/gofiber/fiber/v2"
+)
 
-type ErrorResponse struct {
-	Success   bool   `json:"success"`
-	Message   string `json:"message"`
-	ErrorCode string `json:"error_code,omitempty"`
+// Envelope adalah satu-satunya bentuk response API, sukses maupun error
+type Envelope struct {
+	StatusCode int               `json:"status_code"`
+	Status     string            `json:"status"`
+	Message    string            `json:"message"`
+	ErrorCode  string            `json:"error_code,omitempty"`
+	Errors     map[string]string `json:"errors,omitempty"`
+	Data       interface{}       `json:"data"`
 }
 
-func JSONSuccess(c *fiber.Ctx, message string, data interface{}) error {
-	return c.Status(fiber.StatusOK).JSON(SuccessResponse{
-		Success: true,
-		Message: message,
-		Data:    data,
+func jsonSuccess(c *fiber.Ctx, code int, msg string, data any) error {
+	return c.Status(code).JSON(Envelope{
+		StatusCode: code,
+		Status:     "success",
+		Message:    msg,
+		Data:       data,
 	})
 }
 
-func JSONError(c *fiber.Ctx, statusCode int, message, code string) error {
-	return c.Status(statusCode).JSON(ErrorResponse{
-		Success:   false,
-		Message:   message,
-		ErrorCode: code,
-	})
+// kindStatus memetakan jenis error domain ke HTTP status code
+var kindStatus = map[domain.ErrorKind]int{
+	domain.KindBadRequest:    fiber.StatusBadRequest,
+	domain.KindValidation:    fiber.StatusUnprocessableEntity,
+	domain.KindUnauthorized:  fiber.StatusUnauthorized,
+	domain.KindForbidden:     fiber.StatusForbidden,
+	domain.KindNotFound:      fiber.StatusNotFound,
+	domain.KindConflict:      fiber.StatusConflict,
+	domain.KindUnprocessable: fiber.StatusUnprocessableEntity,
+}
+
+// fiberErrorCodes dipakai untuk error bawaan Fiber (route tidak ada, body terlalu besar, dst.)
+var fiberErrorCodes = map[int]string{
+	fiber.StatusBadRequest:            "BAD_REQUEST",
+	fiber.StatusUnauthorized:          domain.CodeUnauthorized,
+	fiber.StatusForbidden:             domain.CodeForbidden,
+	fiber.StatusNotFound:              domain.CodeNotFound,
+	fiber.StatusMethodNotAllowed:      "METHOD_NOT_ALLOWED",
+	fiber.StatusRequestEntityTooLarge: "PAYLOAD_TOO_LARGE",
+	fiber.StatusUnprocessableEntity:   domain.CodeValidation,
+}
+
+// ErrorHandler dipasang di fiber.Config sehingga handler dan middleware cukup
+// mengembalikan error. Error domain dipetakan berdasarkan Kind-nya, error lain
+// dianggap kesalahan server dan pesannya tidak dikirim ke client.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	env := Envelope{Status: "error"}
+
+	var fe *fiber.Error
+	if de, ok := domain.AsError(err); ok {
+		env.StatusCode = kindStatus[de.Kind]
+		env.ErrorCode = de.Code
+		env.Message = err.Error()
+		env.Errors = de.Fields
+	} else if errors.As(err, &fe) {
+		env.StatusCode = fe.Code
+		env.ErrorCode = fiberErrorCodes[fe.Code]
+		if env.ErrorCode == "" {
+			env.ErrorCode = "HTTP_ERROR"
+		}
+		env.Message = fe.Message
+	} else if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		// Deadline request habis (lihat middleware.Timeout) dan query dibatalkan
+		log.Printf("%s %s: %v", c.Method(), c.Path(), err)
+		env.StatusCode = fiber.StatusGatewayTimeout
+		env.ErrorCode = "REQUEST_TIMEOUT"
+		env.Message = "request melebihi batas waktu, silakan coba lagi"
+	} else if errors.Is(err, sql.ErrNoRows) {
+		env.StatusCode = fiber.StatusNotFound
+		env.ErrorCode = domain.CodeNotFound
+		env.Message = "data tidak ditemukan"
+	}
+
+	if env.StatusCode == 0 {
+		log.Printf("%s %s: %v", c.Method(), c.Path(), err)
+		env.StatusCode = fiber.StatusInternalServerError
+		env.ErrorCode = domain.CodeInternal
+		env.Message = "terjadi kesalahan pada server"
+	}
+
+	return c.Status(env.StatusCode).JSON(env)
 }
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReviewHandler struct {
+	Usecase usecase.ReviewUsecase
+}
+
+func NewReviewHandler(u usecase.ReviewUsecase) *ReviewHandler {
+	return &ReviewHandler{Usecase: u}
+}
+
+// ✅ Route publik (tanpa login)
+func (h *ReviewHandler) RegisterPublicRoutes(api fiber.Router) {
+	api.Get("/bimbels/:id/reviews", h.List)
+}
+
+// ✅ Daftar semua route handler
+func (h *ReviewHandler) RegisterRoutes(api fiber.Router) {
+	api.Post("/bimbels/:id/reviews", h.Create)
+
+	reviews := api.Group("/reviews")
+	reviews.Put("/:id", h.Update)
+	reviews.Post("/:id/reply", h.Reply)
+	reviews.Put("/:id/visibility", h.SetVisibility)
+}
+
+type reviewRequest struct {
+	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Comment string `json:"comment" validate:"max=1000"`
+}
+
+// ✅ LIST REVIEW BIMBEL (publik)
+func (h *ReviewHandler) List(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.List(c.UserContext(), principal.Role, principal.TutorID, bimbelID)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Daftar review ditemukan", data)
+}
+
+// ✅ BUAT REVIEW (peserta yang sudah menyelesaikan bimbel)
+func (h *ReviewHandler) Create(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	if principal.PesertaID == 0 {
+		return domain.ErrNoPesertaProfile
+	}
+
+	var req reviewRequest
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	review, err := h.Usecase.Create(c.UserContext(), principal.Role, principal.PesertaID, bimbelID, req.Rating, req.Comment)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusCreated, "Review berhasil dikirim", review)
+}
+
+// ✅ EDIT REVIEW MILIK SENDIRI
+func (h *ReviewHandler) Update(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	if principal.PesertaID == 0 {
+		return domain.ErrNoPesertaProfile
+	}
+
+	var req reviewRequest
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	review, err := h.Usecase.Update(c.UserContext(), principal.Role, principal.PesertaID, id, req.Rating, req.Comment)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Review berhasil diperbarui", review)
+}
+
+// ✅ BALAS REVIEW (tutor pemilik bimbel, sekali saja)
+func (h *ReviewHandler) Reply(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	var req struct {
+		Reply string `json:"reply" validate:"required,max=1000"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	review, err := h.Usecase.Reply(c.UserContext(), principal.Role, principal.TutorID, id, req.Reply)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Balasan review tersimpan", review)
+}
+
+// ✅ SEMBUNYIKAN / TAMPILKAN REVIEW (admin)
+func (h *ReviewHandler) SetVisibility(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	var req struct {
+		Hidden bool   `json:"hidden"`
+		Reason string `json:"reason" validate:"max=255"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	review, err := h.Usecase.SetHidden(c.UserContext(), principal.Role, id, req.Hidden, req.Reason)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Visibilitas review diperbarui", review)
+}
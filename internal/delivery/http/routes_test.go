@@ -0,0 +1,330 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/middleware"
+	"main-service/internal/policy"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+// gate menunjukkan di mana aksi sebuah endpoint dicek
+type gate int
+
+const (
+	gateLogin   gate = iota // cukup login, tanpa aksi policy
+	gateRoute               // middleware.Authorize di route
+	gateUsecase             // policy.Authorize di usecase (termasuk kepemilikan)
+)
+
+// endpoint adalah satu route terproteksi. roles adalah role yang boleh
+// mencapai handler (penuh atau ":own"); role lain harus ditolak.
+type endpoint struct {
+	method  string
+	path    string
+	gate    gate
+	actions []string
+	roles   []string
+}
+
+var (
+	adminOnly    = []string{domain.RoleAdmin}
+	tutorOnly    = []string{domain.RoleTutor}
+	pesertaOnly  = []string{domain.RolePeserta}
+	adminTutor   = []string{domain.RoleAdmin, domain.RoleTutor}
+	adminPeserta = []string{domain.RoleAdmin, domain.RolePeserta}
+	everyone     = []string{domain.RoleAdmin, domain.RoleTutor, domain.RolePeserta}
+)
+
+// endpoints wajib mencantumkan setiap route terproteksi; TestEndpointTableComplete
+// gagal jika ada route baru yang belum ditulis di sini
+var endpoints = []endpoint{
+	{"POST", "/auth/logout", gateLogin, nil, everyone},
+	{"POST", "/auth/logout-all", gateLogin, nil, everyone},
+
+	{"GET", "/features/", gateRoute, []string{policy.FeatureList}, everyone},
+	{"GET", "/features/show/:id", gateRoute, []string{policy.FeatureView}, everyone},
+	{"GET", "/features/trash", gateRoute, []string{policy.FeatureTrash}, adminOnly},
+	{"POST", "/features/", gateRoute, []string{policy.FeatureCreate}, adminOnly},
+	{"POST", "/features/:id/restore", gateRoute, []string{policy.FeatureTrash}, adminOnly},
+	{"PUT", "/features/:id", gateRoute, []string{policy.FeatureUpdate}, adminOnly},
+	{"DELETE", "/features/:id", gateRoute, []string{policy.FeatureDelete}, adminOnly},
+
+	{"GET", "/matpels/trash", gateRoute, []string{policy.MatpelTrash}, adminOnly},
+	{"GET", "/matpels/:feature_id", gateRoute, []string{policy.MatpelList}, everyone},
+	{"GET", "/matpels/show/:id", gateRoute, []string{policy.MatpelView}, everyone},
+	{"POST", "/matpels/", gateRoute, []string{policy.MatpelCreate}, adminOnly},
+	{"POST", "/matpels/:id/restore", gateRoute, []string{policy.MatpelTrash}, adminOnly},
+	{"PUT", "/matpels/:id", gateRoute, []string{policy.MatpelUpdate}, adminOnly},
+	{"DELETE", "/matpels/:id", gateRoute, []string{policy.MatpelDelete}, adminOnly},
+
+	{"GET", "/bimbels/show/:id", gateUsecase, []string{policy.BimbelView}, everyone},
+	{"GET", "/bimbels/trash", gateRoute, []string{policy.BimbelTrash}, adminOnly},
+	{"GET", "/bimbels/:id/history", gateUsecase, []string{policy.BimbelUpdate}, adminTutor},
+	{"POST", "/bimbels/", gateUsecase, []string{policy.BimbelCreate}, adminTutor},
+	{"POST", "/bimbels/:id/restore", gateRoute, []string{policy.BimbelTrash}, adminOnly},
+	{"POST", "/bimbels/:id/submit", gateUsecase, []string{policy.BimbelUpdate}, adminTutor},
+	{"POST", "/bimbels/:id/withdraw", gateUsecase, []string{policy.BimbelUpdate}, adminTutor},
+	{"POST", "/bimbels/:id/publish", gateUsecase, []string{policy.BimbelUpdate}, adminTutor},
+	{"POST", "/bimbels/:id/reject", gateRoute, []string{policy.BimbelModerate}, adminOnly},
+	{"POST", "/bimbels/:id/close", gateUsecase, []string{policy.BimbelUpdate}, adminTutor},
+	{"POST", "/bimbels/:id/archive", gateUsecase, []string{policy.BimbelUpdate}, adminTutor},
+	{"POST", "/bimbels/:id/unarchive", gateUsecase, []string{policy.BimbelUpdate}, adminTutor},
+	{"PUT", "/bimbels/:id", gateUsecase, []string{policy.BimbelUpdate}, adminTutor},
+	{"DELETE", "/bimbels/:id", gateUsecase, []string{policy.BimbelDelete}, adminTutor},
+
+	{"GET", "/enrollments", gateUsecase, []string{policy.EnrollmentList}, pesertaOnly},
+	{"GET", "/bimbels/:id/enrollments", gateUsecase, []string{policy.EnrollmentManage}, adminTutor},
+	{"POST", "/bimbels/:id/enroll", gateUsecase, []string{policy.EnrollmentCreate}, pesertaOnly},
+	{"POST", "/bimbels/:id/enrollments/:enrollment_id/complete", gateUsecase, []string{policy.EnrollmentManage}, adminTutor},
+	{"DELETE", "/bimbels/:id/enroll", gateUsecase, []string{policy.EnrollmentCancel}, pesertaOnly},
+
+	{"GET", "/bimbels/:id/sessions/", gateLogin, nil, everyone},
+	{"POST", "/bimbels/:id/sessions/", gateUsecase, []string{policy.SessionManage}, adminTutor},
+	{"POST", "/bimbels/:id/sessions/recurrences", gateUsecase, []string{policy.SessionManage}, adminTutor},
+	{"PUT", "/bimbels/:id/sessions/:session_id", gateUsecase, []string{policy.SessionManage}, adminTutor},
+	{"DELETE", "/bimbels/:id/sessions/:session_id", gateUsecase, []string{policy.SessionManage}, adminTutor},
+	{"DELETE", "/bimbels/:id/sessions/recurrences/:recurrence_id", gateUsecase, []string{policy.SessionManage}, adminTutor},
+
+	{"POST", "/bimbels/:id/sessions/:session_id/checkin-code", gateUsecase, []string{policy.AttendanceManage}, adminTutor},
+	{"POST", "/bimbels/:id/sessions/:session_id/checkin", gateUsecase, []string{policy.AttendanceCheckin}, pesertaOnly},
+	{"GET", "/bimbels/:id/sessions/:session_id/attendances", gateUsecase, []string{policy.AttendanceManage}, adminTutor},
+	{"PUT", "/bimbels/:id/sessions/:session_id/attendances", gateUsecase, []string{policy.AttendanceManage}, adminTutor},
+	{"GET", "/bimbels/:id/attendances/summary", gateUsecase, []string{policy.AttendanceManage}, adminTutor},
+	{"GET", "/bimbels/:id/attendances/pesertas/:peserta_id", gateUsecase, []string{policy.AttendanceManage}, adminTutor},
+
+	{"GET", "/invoices/", gateUsecase, []string{policy.InvoiceList}, adminPeserta},
+	{"GET", "/invoices/:id", gateUsecase, []string{policy.InvoiceView}, adminPeserta},
+	{"POST", "/invoices/:id/cancel", gateUsecase, []string{policy.InvoiceCancel}, adminPeserta},
+	{"POST", "/invoices/:id/refund", gateUsecase, []string{policy.InvoiceRefund}, adminOnly},
+	{"POST", "/invoices/:id/simulate-payment", gateUsecase, []string{policy.InvoiceSimulate}, adminPeserta},
+
+	{"GET", "/admin/payment-notifications/", gateRoute, []string{policy.PaymentNotificationList}, adminOnly},
+	{"POST", "/admin/payment-notifications/:id/replay", gateRoute, []string{policy.PaymentNotificationReplay}, adminOnly},
+
+	{"POST", "/bimbels/:id/reviews", gateUsecase, []string{policy.ReviewCreate}, pesertaOnly},
+	{"PUT", "/reviews/:id", gateUsecase, []string{policy.ReviewUpdate}, pesertaOnly},
+	{"POST", "/reviews/:id/reply", gateUsecase, []string{policy.ReviewReply}, tutorOnly},
+	{"PUT", "/reviews/:id/visibility", gateUsecase, []string{policy.ReviewModerate}, adminOnly},
+
+	{"GET", "/tutor-profile/", gateRoute, []string{policy.TutorProfileManage}, tutorOnly},
+	{"PUT", "/tutor-profile/", gateRoute, []string{policy.TutorProfileManage}, tutorOnly},
+	{"POST", "/tutor-profile/avatar", gateRoute, []string{policy.TutorProfileManage}, tutorOnly},
+	{"POST", "/tutor-profile/documents", gateRoute, []string{policy.TutorProfileManage}, tutorOnly},
+	{"GET", "/tutor-profile/documents/:doc_id", gateRoute, []string{policy.TutorProfileManage}, tutorOnly},
+	{"POST", "/tutor-profile/verification", gateRoute, []string{policy.TutorProfileManage}, tutorOnly},
+	{"GET", "/admin/tutors/", gateRoute, []string{policy.TutorVerify}, adminOnly},
+	{"GET", "/admin/tutors/:id", gateRoute, []string{policy.TutorVerify}, adminOnly},
+	{"GET", "/admin/tutors/:id/documents/:doc_id", gateRoute, []string{policy.TutorVerify}, adminOnly},
+	{"POST", "/admin/tutors/:id/approve", gateRoute, []string{policy.TutorVerify}, adminOnly},
+	{"POST", "/admin/tutors/:id/reject", gateRoute, []string{policy.TutorVerify}, adminOnly},
+
+	{"GET", "/peserta-profile", gateUsecase, []string{policy.PesertaProfileManage}, pesertaOnly},
+	{"PUT", "/peserta-profile", gateUsecase, []string{policy.PesertaProfileManage}, pesertaOnly},
+	{"GET", "/pesertas/:id", gateUsecase, []string{policy.PesertaView, policy.PesertaViewLimited}, adminTutor},
+
+	{"GET", "/admin/users/", gateRoute, []string{policy.UserManage}, adminOnly},
+	{"POST", "/admin/users/", gateRoute, []string{policy.UserManage}, adminOnly},
+	{"GET", "/admin/users/:id", gateRoute, []string{policy.UserManage}, adminOnly},
+	{"PUT", "/admin/users/:id/role", gateRoute, []string{policy.UserManage}, adminOnly},
+	{"PUT", "/admin/users/:id/status", gateRoute, []string{policy.UserManage}, adminOnly},
+	{"DELETE", "/admin/users/:id", gateRoute, []string{policy.UserManage}, adminOnly},
+	{"POST", "/admin/users/:id/restore", gateRoute, []string{policy.UserManage}, adminOnly},
+
+	{"GET", "/admin/system/db-stats", gateRoute, []string{policy.SystemMonitor}, adminOnly},
+}
+
+const testJWTSecret = "test-secret"
+
+// activeSessions menganggap semua sesi login masih aktif
+type activeSessions struct{}
+
+func (activeSessions) IsActive(ctx context.Context, sessionID uint64) (bool, error) {
+	return true, nil
+}
+
+// newProtectedApp mendaftarkan semua route terproteksi di belakang
+// AuthMiddleware seperti di cmd/main.go, dengan usecase nil. Handler yang
+// lolos middleware akan panic karena usecase nil dan menjadi 500.
+func newProtectedApp(t *testing.T) *fiber.App {
+	t.Helper()
+	t.Setenv("JWT_SECRET", testJWTSecret)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(recover.New())
+
+	protected := app.Group("/api/v1")
+	protected.Use(middleware.AuthMiddleware(activeSessions{}))
+
+	NewUserHandler(nil).RegisterSessionRoutes(protected)
+	NewFeatureHandler(nil).RegisterRoutes(protected)
+	NewMatpelHandler(nil).RegisterRoutes(protected)
+	NewBimbelHandler(nil, nil).RegisterRoutes(protected)
+	NewEnrollmentHandler(nil).RegisterRoutes(protected)
+	NewSessionHandler(nil).RegisterRoutes(protected)
+	NewAttendanceHandler(nil).RegisterRoutes(protected)
+	NewInvoiceHandler(nil).RegisterRoutes(protected)
+	NewPaymentWebhookHandler(nil).RegisterAdminRoutes(protected)
+	NewReviewHandler(nil).RegisterRoutes(protected)
+	NewTutorHandler(nil, nil).RegisterRoutes(protected)
+	NewPesertaHandler(nil).RegisterRoutes(protected)
+	NewUserAdminHandler(nil).RegisterRoutes(protected)
+	NewSystemHandler(nil).RegisterRoutes(protected)
+	return app
+}
+
+func TestEndpointTableComplete(t *testing.T) {
+	app := newProtectedApp(t)
+
+	registered := map[string]bool{}
+	for _, r := range app.GetRoutes(true) {
+		if r.Method == fiber.MethodHead {
+			continue
+		}
+		registered[r.Method+" "+strings.TrimPrefix(r.Path, "/api/v1")] = true
+	}
+
+	listed := map[string]bool{}
+	for _, e := range endpoints {
+		key := e.method + " " + e.path
+		if listed[key] {
+			t.Errorf("endpoint %s tercantum dua kali", key)
+		}
+		listed[key] = true
+		if !registered[key] {
+			t.Errorf("endpoint %s tidak terdaftar di router", key)
+		}
+	}
+
+	var missing []string
+	for key := range registered {
+		if !listed[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		t.Errorf("route %s belum ada di tabel endpoints", key)
+	}
+}
+
+// TestEndpointRoles mengecek setiap endpoint x role: role di luar daftar
+// ditolak (di middleware untuk gateRoute, di policy untuk gateUsecase)
+func TestEndpointRoles(t *testing.T) {
+	log.SetOutput(io.Discard) // error 500 dari handler tanpa usecase memang diharapkan
+	defer log.SetOutput(os.Stderr)
+
+	app := newProtectedApp(t)
+	roles := []string{domain.RoleAdmin, domain.RoleTutor, domain.RolePeserta, "guest"}
+
+	for _, e := range endpoints {
+		for _, role := range roles {
+			want := contains(e.roles, role)
+			t.Run(e.method+" "+e.path+"/"+role, func(t *testing.T) {
+				if e.gate != gateLogin && allowedAny(role, e.actions) != want {
+					t.Fatalf("policy.Allowed(%s, %v) = %v, want %v", role, e.actions, !want, want)
+				}
+				if e.gate != gateRoute {
+					return
+				}
+
+				status, body := doRequest(t, app, e.method, e.path, role)
+				blocked := status == fiber.StatusForbidden && strings.HasPrefix(body.Message, "forbidden: tidak memiliki akses")
+				if blocked == want {
+					t.Fatalf("status = %d (%s), want diizinkan = %v", status, body.Message, want)
+				}
+			})
+		}
+	}
+}
+
+// Tanpa token semua endpoint terproteksi harus 401
+func TestEndpointsRequireLogin(t *testing.T) {
+	app := newProtectedApp(t)
+	for _, e := range endpoints {
+		t.Run(e.method+" "+e.path, func(t *testing.T) {
+			status, body := doRequest(t, app, e.method, e.path, "")
+			if status != fiber.StatusUnauthorized {
+				t.Fatalf("status = %d (%s), want 401", status, body.Message)
+			}
+		})
+	}
+}
+
+func allowedAny(role string, actions []string) bool {
+	for _, a := range actions {
+		if policy.Allowed(role, a) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// concretePath mengganti parameter route (":id") dengan angka
+func concretePath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") {
+			parts[i] = "1"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// doRequest memanggil endpoint sebagai role tersebut; role kosong berarti
+// tanpa token
+func doRequest(t *testing.T, app *fiber.App, method, path, role string) (int, Envelope) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/api/v1"+concretePath(path), nil)
+	if role != "" {
+		req.Header.Set("Authorization", "Bearer "+testToken(t, role))
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body Envelope
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+// testToken menandatangani access token untuk role tersebut (tutor_id 10,
+// peserta_id 100)
+func testToken(t *testing.T, role string) string {
+	t.Helper()
+	token, err := auth.Sign(domain.AuthPrincipal{
+		UserID:    1,
+		Role:      role,
+		TutorID:   10,
+		PesertaID: 100,
+		SessionID: 1,
+	}, time.Now().Add(time.Minute), testJWTSecret)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
@@ -0,0 +1,276 @@
+package http
+
+import (
+	"errors"
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/usecase"
+	"main-service/internal/validation"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionHandler struct {
+	Usecase usecase.SessionUsecase
+}
+
+func NewSessionHandler(u usecase.SessionUsecase) *SessionHandler {
+	return &SessionHandler{Usecase: u}
+}
+
+// ✅ Daftar semua route handler
+func (h *SessionHandler) RegisterRoutes(api fiber.Router) {
+	sessions := api.Group("/bimbels/:id/sessions")
+	sessions.Get("/", h.List)
+	sessions.Post("/", h.Create)
+	sessions.Post("/recurrences", h.CreateRecurrence)
+	sessions.Delete("/recurrences/:recurrence_id", h.DeleteRecurrence)
+	sessions.Put("/:session_id", h.Update)
+	sessions.Delete("/:session_id", h.Delete)
+}
+
+type sessionRequest struct {
+	Title    string `json:"title" validate:"max=150"`
+	StartAt  string `json:"start_at" validate:"required"`
+	EndAt    string `json:"end_at" validate:"required"`
+	Timezone string `json:"timezone" validate:"max=64"`
+}
+
+// recurrenceRequest hanya memeriksa kelengkapan; format tanggal/jam dan
+// rentangnya divalidasi usecase saat aturan dipecah menjadi sesi
+type recurrenceRequest struct {
+	Title           string `json:"title" validate:"max=150"`
+	Weekdays        []int  `json:"weekdays" validate:"required,max=7"`
+	StartTime       string `json:"start_time" validate:"required"`
+	DurationMinutes int    `json:"duration_minutes" validate:"required,min=1,max=720"`
+	Timezone        string `json:"timezone" validate:"max=64"`
+	StartDate       string `json:"start_date" validate:"required"`
+	EndDate         string `json:"end_date" validate:"required"`
+}
+
+// parseSessionTime menerima RFC3339 atau waktu lokal "2006-01-02T15:04"
+// yang dibaca sesuai timezone sesi
+func parseSessionTime(value, timezone string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if timezone == "" {
+		timezone = "Asia/Jakarta"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, errors.New("timezone tidak valid")
+	}
+	return time.ParseInLocation("2006-01-02T15:04", value, loc)
+}
+
+func (r *sessionRequest) toSession(bimbelID uint64) (*domain.BimbelSession, error) {
+	errs := validation.Errors{}
+	start, err := parseSessionTime(r.StartAt, r.Timezone)
+	if err != nil {
+		errs.Add("start_at", "start_at tidak valid")
+	}
+	end, err := parseSessionTime(r.EndAt, r.Timezone)
+	if err != nil {
+		errs.Add("end_at", "end_at tidak valid")
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	return &domain.BimbelSession{
+		BimbelID: bimbelID,
+		Title:    r.Title,
+		StartAt:  start,
+		EndAt:    end,
+		Timezone: r.Timezone,
+	}, nil
+}
+
+// ✅ LIST SESI BIMBEL
+func (h *SessionHandler) List(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	var from, to *time.Time
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return domain.InvalidField("from", "from harus berformat RFC3339")
+		}
+		from = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return domain.InvalidField("to", "to harus berformat RFC3339")
+		}
+		to = &t
+	}
+
+	data, err := h.Usecase.List(c.UserContext(), principal.Role, principal.TutorID, bimbelID, from, to)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Jadwal bimbel ditemukan", data)
+}
+
+// ✅ CREATE SESI
+func (h *SessionHandler) Create(c *fiber.Ctx) error {
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	var req sessionRequest
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	session, err := req.toSession(bimbelID)
+	if err != nil {
+		return err
+	}
+
+	created, err := h.Usecase.Create(c.UserContext(), principal.Role, principal.TutorID, session)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusCreated, "Sesi bimbel berhasil dibuat", created)
+}
+
+// ✅ CREATE JADWAL BERULANG MINGGUAN
+func (h *SessionHandler) CreateRecurrence(c *fiber.Ctx) error {
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	var req recurrenceRequest
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+	rec := domain.SessionRecurrence{
+		BimbelID:        bimbelID,
+		Title:           req.Title,
+		Weekdays:        req.Weekdays,
+		StartTime:       req.StartTime,
+		DurationMinutes: req.DurationMinutes,
+		Timezone:        req.Timezone,
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+	}
+
+	sessions, err := h.Usecase.CreateRecurrence(c.UserContext(), principal.Role, principal.TutorID, &rec)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusCreated, "Jadwal berulang berhasil dibuat", fiber.Map{
+		"recurrence": rec,
+		"sessions":   sessions,
+	})
+}
+
+// ✅ UPDATE SESI
+func (h *SessionHandler) Update(c *fiber.Ctx) error {
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+	sessionID, err := strconv.ParseUint(c.Params("session_id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("session_id")
+	}
+
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	var req sessionRequest
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	session, err := req.toSession(bimbelID)
+	if err != nil {
+		return err
+	}
+	session.ID = sessionID
+
+	updated, err := h.Usecase.Update(c.UserContext(), principal.Role, principal.TutorID, session)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Sesi bimbel berhasil diperbarui", updated)
+}
+
+// ✅ DELETE SESI
+func (h *SessionHandler) Delete(c *fiber.Ctx) error {
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+	sessionID, err := strconv.ParseUint(c.Params("session_id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("session_id")
+	}
+
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Usecase.Delete(c.UserContext(), principal.Role, principal.TutorID, bimbelID, sessionID); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Sesi bimbel berhasil dihapus", nil)
+}
+
+// ✅ DELETE JADWAL BERULANG (sesi yang akan datang ikut dihapus)
+func (h *SessionHandler) DeleteRecurrence(c *fiber.Ctx) error {
+	bimbelID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+	recurrenceID, err := strconv.ParseUint(c.Params("recurrence_id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("recurrence_id")
+	}
+
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Usecase.DeleteRecurrence(c.UserContext(), principal.Role, principal.TutorID, bimbelID, recurrenceID); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Jadwal berulang berhasil dihapus", nil)
+}
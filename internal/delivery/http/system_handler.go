@@ -0,0 +1,58 @@
+package http
+
+import (
+	"database/sql"
+	"main-service/internal/middleware"
+	"main-service/internal/policy"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PoolStatter dipenuhi oleh *db.DB
+type PoolStatter interface {
+	Stats() sql.DBStats
+}
+
+type SystemHandler struct {
+	DB PoolStatter
+}
+
+func NewSystemHandler(db PoolStatter) *SystemHandler {
+	return &SystemHandler{DB: db}
+}
+
+// DBStatsResponse adalah sql.DBStats dengan durasi dalam milidetik
+type DBStatsResponse struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+}
+
+// ✅ Daftar semua route handler
+func (h *SystemHandler) RegisterRoutes(api fiber.Router) {
+	system := api.Group("/admin/system", middleware.Authorize(policy.SystemMonitor))
+	system.Get("/db-stats", h.DBStats)
+}
+
+// ✅ STATISTIK CONNECTION POOL (admin)
+// wait_count yang terus naik berarti DB_MAX_OPEN_CONNS terlalu kecil
+func (h *SystemHandler) DBStats(c *fiber.Ctx) error {
+	s := h.DB.Stats()
+	return jsonSuccess(c, fiber.StatusOK, "Statistik connection pool", DBStatsResponse{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDurationMs:     s.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	})
+}
@@ -0,0 +1,366 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/middleware"
+	"main-service/internal/policy"
+	"main-service/internal/storage"
+	"main-service/internal/usecase"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TutorHandler struct {
+	Usecase usecase.TutorUsecase
+	Store   storage.BlobStore
+}
+
+func NewTutorHandler(u usecase.TutorUsecase, store storage.BlobStore) *TutorHandler {
+	return &TutorHandler{Usecase: u, Store: store}
+}
+
+// ✅ Route publik (tanpa login)
+func (h *TutorHandler) RegisterPublicRoutes(api fiber.Router) {
+	api.Get("/tutors/:id", h.PublicProfile)
+}
+
+// ✅ Daftar semua route handler
+func (h *TutorHandler) RegisterRoutes(api fiber.Router) {
+	profile := api.Group("/tutor-profile", middleware.Authorize(policy.TutorProfileManage))
+	profile.Get("/", h.MyProfile)
+	profile.Put("/", h.UpdateProfile)
+	profile.Post("/avatar", h.UpdateAvatar)
+	profile.Post("/documents", h.UploadDocument)
+	profile.Get("/documents/:doc_id", h.MyDocument)
+	profile.Post("/verification", h.SubmitVerification)
+
+	admin := api.Group("/admin/tutors", middleware.Authorize(policy.TutorVerify))
+	admin.Get("/", h.ListVerifications)
+	admin.Get("/:id", h.AdminDetail)
+	admin.Get("/:id/documents/:doc_id", h.AdminDocument)
+	admin.Post("/:id/approve", h.Approve)
+	admin.Post("/:id/reject", h.Reject)
+}
+
+// ✅ PROFIL PUBLIK TUTOR
+func (h *TutorHandler) PublicProfile(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.PublicProfile(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	if err := resolveTutorProfile(c.UserContext(), h.Store, data); err != nil {
+		return err
+	}
+	return jsonSuccess(c, fiber.StatusOK, "Profil tutor ditemukan", data)
+}
+
+// ✅ PROFIL TUTOR SENDIRI
+func (h *TutorHandler) MyProfile(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.MyProfile(c.UserContext(), principal.Role, principal.TutorID)
+	if err != nil {
+		return err
+	}
+
+	if err := resolveTutorProfile(c.UserContext(), h.Store, data); err != nil {
+		return err
+	}
+	return jsonSuccess(c, fiber.StatusOK, "Profil tutor ditemukan", data)
+}
+
+// ✅ UPDATE PROFIL TUTOR
+func (h *TutorHandler) UpdateProfile(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	var req usecase.TutorProfileInput
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.UpdateProfile(c.UserContext(), principal.Role, principal.TutorID, req)
+	if err != nil {
+		return err
+	}
+
+	if err := resolveTutorProfile(c.UserContext(), h.Store, data); err != nil {
+		return err
+	}
+	return jsonSuccess(c, fiber.StatusOK, "Profil tutor berhasil diperbarui", data)
+}
+
+// ✅ UPLOAD AVATAR TUTOR
+func (h *TutorHandler) UpdateAvatar(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	avatarKey, err := h.saveAvatar(c)
+	if err != nil {
+		return err
+	}
+
+	oldKey, err := h.Usecase.UpdateAvatar(c.UserContext(), principal.Role, principal.TutorID, avatarKey)
+	if err != nil {
+		discardUpload(c.UserContext(), h.Store, avatarKey)
+		return err
+	}
+	discardUpload(c.UserContext(), h.Store, oldKey)
+
+	return jsonSuccess(c, fiber.StatusOK, "Avatar berhasil diperbarui", fiber.Map{"avatar_url": h.Store.URL(avatarKey)})
+}
+
+// ✅ UPLOAD DOKUMEN VERIFIKASI
+func (h *TutorHandler) UploadDocument(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		DocType  string                `form:"doc_type" validate:"required,max=50"`
+		Document *multipart.FileHeader `form:"document" validate:"required"`
+	}
+	if err := bindForm(c, &req); err != nil {
+		return err
+	}
+
+	doc, err := h.saveTutorDocument(c, principal.TutorID, req.DocType, req.Document)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Usecase.AddDocument(c.UserContext(), principal.Role, principal.TutorID, doc); err != nil {
+		discardUpload(c.UserContext(), h.Store, doc.FilePath)
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusCreated, "Dokumen berhasil diunggah", doc)
+}
+
+// ✅ UNDUH DOKUMEN SENDIRI
+func (h *TutorHandler) MyDocument(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	return h.sendDocument(c, principal.Role, principal.TutorID)
+}
+
+// ✅ AJUKAN VERIFIKASI
+func (h *TutorHandler) SubmitVerification(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.SubmitVerification(c.UserContext(), principal.Role, principal.TutorID)
+	if err != nil {
+		return err
+	}
+
+	if err := resolveTutorProfile(c.UserContext(), h.Store, data); err != nil {
+		return err
+	}
+	return jsonSuccess(c, fiber.StatusOK, "Pengajuan verifikasi terkirim", data)
+}
+
+// ✅ DAFTAR PENGAJUAN VERIFIKASI (admin)
+func (h *TutorHandler) ListVerifications(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.ListVerifications(c.UserContext(), principal.Role, c.Query("verification_status"))
+	if err != nil {
+		return err
+	}
+
+	if err := resolveTutorProfiles(c.UserContext(), h.Store, data); err != nil {
+		return err
+	}
+	return jsonSuccess(c, fiber.StatusOK, "Daftar tutor ditemukan", data)
+}
+
+// ✅ DETAIL TUTOR (admin)
+func (h *TutorHandler) AdminDetail(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.AdminDetail(c.UserContext(), principal.Role, id)
+	if err != nil {
+		return err
+	}
+
+	if err := resolveTutorProfile(c.UserContext(), h.Store, data); err != nil {
+		return err
+	}
+	return jsonSuccess(c, fiber.StatusOK, "Profil tutor ditemukan", data)
+}
+
+// ✅ UNDUH DOKUMEN TUTOR (admin)
+func (h *TutorHandler) AdminDocument(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	return h.sendDocument(c, principal.Role, id)
+}
+
+// ✅ SETUJUI VERIFIKASI (admin)
+func (h *TutorHandler) Approve(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.Approve(c.UserContext(), principal.Role, principal.UserID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := resolveTutorProfile(c.UserContext(), h.Store, data); err != nil {
+		return err
+	}
+	return jsonSuccess(c, fiber.StatusOK, "Tutor berhasil diverifikasi", data)
+}
+
+// ✅ TOLAK VERIFIKASI (admin, wajib dengan alasan)
+func (h *TutorHandler) Reject(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	var req struct {
+		Reason string `json:"reason" validate:"required,max=500"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.Reject(c.UserContext(), principal.Role, principal.UserID, id, req.Reason)
+	if err != nil {
+		return err
+	}
+
+	if err := resolveTutorProfile(c.UserContext(), h.Store, data); err != nil {
+		return err
+	}
+	return jsonSuccess(c, fiber.StatusOK, "Pengajuan verifikasi ditolak", data)
+}
+
+func (h *TutorHandler) sendDocument(c *fiber.Ctx, role string, tutorID uint64) error {
+	docID, err := strconv.ParseUint(c.Params("doc_id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("doc_id")
+	}
+
+	doc, err := h.Usecase.Document(c.UserContext(), role, tutorID, docID)
+	if err != nil {
+		return err
+	}
+
+	// Dibaca penuh di sini karena stream S3 terikat context request yang
+	// sudah dibatalkan saat Fiber menulis body; ukuran dokumen dibatasi BodyLimit
+	rc, _, err := h.Store.Get(c.UserContext(), doc.FilePath)
+	if errors.Is(err, storage.ErrNotFound) {
+		return domain.ErrTutorDocumentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+
+	c.Attachment(doc.FileName)
+	return c.Send(data)
+}
+
+// ✅ SAVE AVATAR
+func (h *TutorHandler) saveAvatar(c *fiber.Ctx) (string, error) {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		return "", domain.InvalidField("avatar", "avatar wajib diupload")
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return "", domain.InvalidField("avatar", "format avatar harus jpg, jpeg, atau png")
+	}
+
+	key := storage.NewKey("avatars", "tutor", ext)
+	if err := putUpload(c.UserContext(), h.Store, key, file); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// ✅ SAVE DOKUMEN TUTOR
+// Dokumen disimpan di luar storage.PublicPrefixes agar tidak tersaji secara publik
+func (h *TutorHandler) saveTutorDocument(c *fiber.Ctx, tutorID uint64, docType string, file *multipart.FileHeader) (*domain.TutorDocument, error) {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != ".pdf" && ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return nil, domain.InvalidField("document", "format dokumen harus pdf, jpg, jpeg, atau png")
+	}
+
+	key := storage.NewKey("tutor_documents/"+strconv.FormatUint(tutorID, 10), "doc", ext)
+	if err := putUpload(c.UserContext(), h.Store, key, file); err != nil {
+		return nil, err
+	}
+
+	return &domain.TutorDocument{
+		DocType:  docType,
+		FileName: filepath.Base(file.Filename),
+		FilePath: key,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"main-service/internal/domain"
+	"main-service/internal/imaging"
+	"main-service/internal/storage"
+	"mime/multipart"
+	"time"
+)
+
+// documentURLTTL adalah masa berlaku signed URL dokumen tutor
+const documentURLTTL = 15 * time.Minute
+
+// putUpload menyimpan file multipart ke storage dengan key yang diberikan
+func putUpload(ctx context.Context, store storage.BlobStore, key string, file *multipart.FileHeader) error {
+	f, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("gagal membaca file upload: %w", err)
+	}
+	defer f.Close()
+
+	if err := store.Put(ctx, key, f, file.Size, file.Header.Get("Content-Type")); err != nil {
+		return fmt.Errorf("gagal menyimpan file upload: %w", err)
+	}
+	return nil
+}
+
+// discardUpload menghapus object yang tidak jadi dipakai; kegagalan hanya
+// dicatat karena request utamanya sudah selesai (berhasil atau gagal)
+func discardUpload(ctx context.Context, store storage.BlobStore, key string) {
+	if key == "" {
+		return
+	}
+	if err := store.Delete(context.WithoutCancel(ctx), key); err != nil {
+		log.Printf("Delete upload %s failed: %v", key, err)
+	}
+}
+
+// putImageVariants menyimpan hasil imaging.Process dengan key
+// <base>_<lebar>w.<ext>. Jika salah satu gagal, yang sudah tersimpan dihapus.
+func putImageVariants(ctx context.Context, store storage.BlobStore, base string, processed []imaging.Variant) ([]domain.ImageVariant, error) {
+	variants := make([]domain.ImageVariant, 0, len(processed))
+	for _, p := range processed {
+		key := fmt.Sprintf("%s_%dw%s", base, p.Width, p.Ext)
+		if err := store.Put(ctx, key, bytes.NewReader(p.Data), int64(len(p.Data)), p.ContentType); err != nil {
+			discardThumbnail(ctx, store, "", variants)
+			return nil, fmt.Errorf("gagal menyimpan file upload: %w", err)
+		}
+		variants = append(variants, domain.ImageVariant{Key: key, Width: p.Width, Height: p.Height, Format: p.Format})
+	}
+	return variants, nil
+}
+
+// discardThumbnail menghapus thumbnail utama beserta semua variannya.
+// Thumbnail utama biasanya juga salah satu varian; Delete idempotent.
+func discardThumbnail(ctx context.Context, store storage.BlobStore, key string, variants []domain.ImageVariant) {
+	discardUpload(ctx, store, key)
+	for _, v := range variants {
+		if v.Key != key {
+			discardUpload(ctx, store, v.Key)
+		}
+	}
+}
+
+// isImageValidationError true untuk error imaging yang aman ditampilkan ke user
+func isImageValidationError(err error) bool {
+	return errors.Is(err, imaging.ErrUnsupportedFormat) || errors.Is(err, imaging.ErrInvalidImage) ||
+		errors.Is(err, imaging.ErrTooLarge) || errors.Is(err, imaging.ErrDimensionsTooBig)
+}
+
+// ===== Resolve object key -> URL saat response =====
+
+func resolveBimbel(store storage.BlobStore, b *domain.Bimbel) {
+	if b == nil {
+		return
+	}
+	if b.Thumbnail != "" {
+		b.ThumbnailURL = store.URL(b.Thumbnail)
+	}
+	for i := range b.ThumbnailVariants {
+		b.ThumbnailVariants[i].URL = store.URL(b.ThumbnailVariants[i].Key)
+	}
+}
+
+func resolveBimbels(store storage.BlobStore, items []domain.Bimbel) {
+	for i := range items {
+		resolveBimbel(store, &items[i])
+	}
+}
+
+func resolveTutorProfile(ctx context.Context, store storage.BlobStore, p *domain.TutorProfile) error {
+	if p == nil {
+		return nil
+	}
+	if p.Avatar != "" {
+		p.AvatarURL = store.URL(p.Avatar)
+	}
+	resolveBimbels(store, p.Bimbels)
+
+	// Dokumen hanya dimuat untuk pemilik/admin, jadi aman diberi signed URL
+	for i := range p.Documents {
+		url, err := store.SignedURL(ctx, p.Documents[i].FilePath, documentURLTTL)
+		if err != nil {
+			return err
+		}
+		p.Documents[i].URL = url
+	}
+	return nil
+}
+
+func resolveTutorProfiles(ctx context.Context, store storage.BlobStore, items []domain.TutorProfile) error {
+	for i := range items {
+		if err := resolveTutorProfile(ctx, store, &items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/middleware"
+	"main-service/internal/policy"
+	"main-service/internal/usecase"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserAdminHandler struct {
+	Usecase usecase.UserAdminUsecase
+}
+
+func NewUserAdminHandler(u usecase.UserAdminUsecase) *UserAdminHandler {
+	return &UserAdminHandler{Usecase: u}
+}
+
+// ✅ Daftar semua route handler
+func (h *UserAdminHandler) RegisterRoutes(api fiber.Router) {
+	users := api.Group("/admin/users", middleware.Authorize(policy.UserManage))
+	users.Get("/", h.List)
+	users.Post("/", h.Create)
+	users.Get("/:id", h.Detail)
+	users.Put("/:id/role", h.ChangeRole)
+	users.Put("/:id/status", h.SetStatus)
+	users.Delete("/:id", h.Delete)
+	users.Post("/:id/restore", h.Restore)
+}
+
+// ✅ LIST USER (admin)
+func (h *UserAdminHandler) List(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	includeDeleted := false
+	if v := c.Query("include_deleted"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return domain.InvalidField("include_deleted", "include_deleted harus true atau false")
+		}
+		includeDeleted = b
+	}
+
+	data, err := h.Usecase.List(c.UserContext(), principal.Role, c.Query("role"), includeDeleted)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Daftar user ditemukan", data)
+}
+
+// ✅ DETAIL USER (admin)
+func (h *UserAdminHandler) Detail(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.Detail(c.UserContext(), principal.Role, id)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "User ditemukan", data)
+}
+
+// ✅ BUAT USER (admin, termasuk akun admin baru)
+func (h *UserAdminHandler) Create(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	var req usecase.CreateUserInput
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.Create(c.UserContext(), principal.Role, req)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusCreated, "User berhasil dibuat", data)
+}
+
+// ✅ UBAH ROLE USER (admin)
+func (h *UserAdminHandler) ChangeRole(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	var req struct {
+		Role string `json:"role" validate:"required,oneof=admin tutor peserta"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.ChangeRole(c.UserContext(), principal.Role, principal.UserID, id, req.Role)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Role user berhasil diubah", data)
+}
+
+// ✅ AKTIFKAN / NONAKTIFKAN USER (admin)
+func (h *UserAdminHandler) SetStatus(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	var req struct {
+		IsActive *bool `json:"is_active" validate:"required"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	data, err := h.Usecase.SetActive(c.UserContext(), principal.Role, principal.UserID, id, *req.IsActive)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "Status user berhasil diubah", data)
+}
+
+// ✅ HAPUS USER / SOFT DELETE (admin)
+func (h *UserAdminHandler) Delete(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	if err := h.Usecase.Delete(c.UserContext(), principal.Role, principal.UserID, id); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "User berhasil dihapus", nil)
+}
+
+// ✅ PULIHKAN USER TERHAPUS (admin)
+func (h *UserAdminHandler) Restore(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domain.InvalidParam("id")
+	}
+
+	data, err := h.Usecase.Restore(c.UserContext(), principal.Role, id)
+	if err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "User berhasil dipulihkan", data)
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"main-service/internal/auth"
 	"main-service/internal/usecase"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,57 +18,159 @@ func NewUserHandler(uc usecase.UserUsecase) *UserHandler {
 func (h *UserHandler) RegisterRoutes(api fiber.Router) {
 	api.Post("/login", h.Login)
 	api.Post("/register", h.Register)
+	api.Post("/auth/refresh", h.Refresh)
+	api.Post("/auth/forgot-password", h.ForgotPassword)
+	api.Post("/auth/reset-password", h.ResetPassword)
+	api.Post("/auth/verify-email", h.VerifyEmail)
+	api.Post("/auth/resend-verification", h.ResendVerification)
 }
 
-// standardized response helper
-func response(c *fiber.Ctx, statusCode int, status string, message string, data interface{}) error {
-	res := fiber.Map{
-		"status_code": statusCode,
-		"status":      status,
-		"message":     message,
+// RegisterSessionRoutes mendaftarkan route yang butuh access token
+func (h *UserHandler) RegisterSessionRoutes(api fiber.Router) {
+	api.Post("/auth/logout", h.Logout)
+	api.Post("/auth/logout-all", h.LogoutAll)
+}
+
+func clientInfo(c *fiber.Ctx) usecase.ClientInfo {
+	return usecase.ClientInfo{UserAgent: c.Get(fiber.HeaderUserAgent), IPAddress: c.IP()}
+}
+
+func (h *UserHandler) Login(c *fiber.Ctx) error {
+	var req struct {
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
 	}
 
-	if data != nil {
-		res["data"] = data
+	result, err := h.usecase.Login(c.UserContext(), req.Email, req.Password, clientInfo(c))
+	if err != nil {
+		return err
 	}
 
-	return c.Status(statusCode).JSON(res)
+	return jsonSuccess(c, fiber.StatusOK, "login berhasil", result)
 }
 
-func (h *UserHandler) Login(c *fiber.Ctx) error {
+func (h *UserHandler) Register(c *fiber.Ctx) error {
 	var req struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Name     string `json:"name" validate:"required,max=100"`
+		Email    string `json:"email" validate:"required,email,max=150"`
+		Password string `json:"password" validate:"required,min=8,max=72"`
+		Role     string `json:"role" validate:"required"`
 	}
-
-	if err := c.BodyParser(&req); err != nil {
-		return response(c, fiber.StatusBadRequest, "error", "invalid request payload", nil)
+	if err := bindJSON(c, &req); err != nil {
+		return err
 	}
 
-	result, err := h.usecase.Login(req.Email, req.Password)
+	result, err := h.usecase.Register(c.UserContext(), req.Name, req.Email, req.Password, req.Role, clientInfo(c))
 	if err != nil {
-		return response(c, fiber.StatusUnauthorized, "error", err.Error(), nil)
+		return err
 	}
 
-	return response(c, fiber.StatusOK, "success", "login berhasil", result)
+	return jsonSuccess(c, fiber.StatusCreated, "registrasi berhasil", result)
 }
 
-func (h *UserHandler) Register(c *fiber.Ctx) error {
+func (h *UserHandler) Refresh(c *fiber.Ctx) error {
 	var req struct {
-		Name     string `json:"name"`
-		Email    string `json:"email"`
-		Password string `json:"password"`
-		Role     string `json:"role"`
+		RefreshToken string `json:"refresh_token" validate:"required"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
 	}
 
-	if err := c.BodyParser(&req); err != nil {
-		return response(c, fiber.StatusBadRequest, "error", "invalid request payload", nil)
+	result, err := h.usecase.Refresh(c.UserContext(), req.RefreshToken)
+	if err != nil {
+		return err
 	}
 
-	result, err := h.usecase.Register(req.Name, req.Email, req.Password, req.Role)
+	return jsonSuccess(c, fiber.StatusOK, "token diperbarui", result)
+}
+
+func (h *UserHandler) Logout(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.usecase.Logout(c.UserContext(), principal.UserID, principal.SessionID); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "logout berhasil", nil)
+}
+
+func (h *UserHandler) LogoutAll(c *fiber.Ctx) error {
+	principal, err := auth.PrincipalFrom(c)
+	if err != nil {
+		return err
+	}
+
+	n, err := h.usecase.LogoutAll(c.UserContext(), principal.UserID)
 	if err != nil {
-		return response(c, fiber.StatusBadRequest, "error", err.Error(), nil)
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "logout dari semua perangkat berhasil", fiber.Map{"revoked_sessions": n})
+}
+
+func (h *UserHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.usecase.ForgotPassword(c.UserContext(), req.Email); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "jika email terdaftar, link reset password sudah dikirim", nil)
+}
+
+func (h *UserHandler) ResetPassword(c *fiber.Ctx) error {
+	var req struct {
+		Token    string `json:"token" validate:"required"`
+		Password string `json:"password" validate:"required,min=8,max=72"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.usecase.ResetPassword(c.UserContext(), req.Token, req.Password); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "password berhasil diubah, silakan login ulang", nil)
+}
+
+func (h *UserHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req struct {
+		Token string `json:"token" validate:"required"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.usecase.VerifyEmail(c.UserContext(), req.Token); err != nil {
+		return err
+	}
+
+	return jsonSuccess(c, fiber.StatusOK, "email berhasil diverifikasi", nil)
+}
+
+func (h *UserHandler) ResendVerification(c *fiber.Ctx) error {
+	var req struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+	if err := bindJSON(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.usecase.ResendVerification(c.UserContext(), req.Email); err != nil {
+		return err
 	}
 
-	return response(c, fiber.StatusCreated, "success", "registrasi berhasil", result)
+	return jsonSuccess(c, fiber.StatusOK, "jika email terdaftar dan belum diverifikasi, link verifikasi sudah dikirim", nil)
 }
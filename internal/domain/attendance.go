@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	AttendancePresent = "present"
+	AttendanceAbsent  = "absent"
+	AttendanceExcused = "excused"
+	AttendanceLate    = "late"
+)
+
+var (
+	ErrInvalidAttendanceStatus = NewError(KindValidation, "INVALID_ATTENDANCE_STATUS", "status kehadiran harus present, absent, excused, atau late")
+	ErrCheckinCodeInvalid      = Unprocessable("CHECKIN_CODE_INVALID", "kode absensi salah atau sudah kedaluwarsa")
+	ErrCheckinLocked           = Forbidden("CHECKIN_LOCKED", "terlalu banyak kode absensi salah; minta tutor membuat kode baru atau mengabsen manual")
+	ErrNotEnrolled             = Forbidden("NOT_ENROLLED", "peserta tidak terdaftar di bimbel ini")
+)
+
+func IsValidAttendanceStatus(status string) bool {
+	switch status {
+	case AttendancePresent, AttendanceAbsent, AttendanceExcused, AttendanceLate:
+		return true
+	}
+	return false
+}
+
+type Attendance struct {
+	ID          uint64     `json:"id"`
+	SessionID   uint64     `json:"session_id"`
+	PesertaID   uint64     `json:"peserta_id"`
+	Status      string     `json:"status"`
+	Note        string     `json:"note"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	MarkedBy    *uint64    `json:"marked_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AttendanceRosterItem adalah status kehadiran satu peserta pada satu sesi.
+// Status kosong berarti belum diabsen.
+type AttendanceRosterItem struct {
+	PesertaID   uint64     `json:"peserta_id"`
+	PesertaName string     `json:"peserta_name"`
+	Status      string     `json:"status"`
+	Note        string     `json:"note"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+}
+
+// CheckinCode adalah kode absensi mandiri yang dibuat tutor untuk satu sesi
+type CheckinCode struct {
+	SessionID uint64    `json:"session_id"`
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AttendanceSummary merangkum kehadiran satu peserta. Sesi yang sudah
+// berjalan tanpa catatan kehadiran dihitung absent.
+type AttendanceSummary struct {
+	PesertaID     uint64  `json:"peserta_id"`
+	PesertaName   string  `json:"peserta_name"`
+	TotalSessions int     `json:"total_sessions"`
+	Present       int     `json:"present"`
+	Late          int     `json:"late"`
+	Excused       int     `json:"excused"`
+	Absent        int     `json:"absent"`
+	Rate          float64 `json:"attendance_rate"`
+}
+
+type BimbelAttendanceSummary struct {
+	BimbelID      uint64              `json:"bimbel_id"`
+	TotalSessions int                 `json:"total_sessions"`
+	Rate          float64             `json:"attendance_rate"`
+	Pesertas      []AttendanceSummary `json:"pesertas"`
+}
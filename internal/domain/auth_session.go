@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+)
+
+// Alasan pencabutan sesi login
+const (
+	SessionRevokedLogout        = "logout"
+	SessionRevokedLogoutAll     = "logout_all"
+	SessionRevokedReuse         = "refresh_token_reuse"
+	SessionRevokedInactive      = "user_inactive"
+	SessionRevokedPasswordReset = "password_reset"
+	SessionRevokedRoleChanged   = "role_changed"
+	SessionRevokedDeleted       = "user_deleted"
+)
+
+var (
+	ErrRefreshTokenInvalid = Unauthorized("REFRESH_TOKEN_INVALID", "refresh token tidak valid")
+	ErrRefreshTokenExpired = Unauthorized("REFRESH_TOKEN_EXPIRED", "refresh token kedaluwarsa")
+	ErrRefreshTokenReused  = Unauthorized("REFRESH_TOKEN_REUSED", "refresh token sudah pernah dipakai, semua sesi perangkat ini dicabut")
+	ErrSessionRevoked      = Unauthorized("SESSION_REVOKED", "sesi sudah dicabut, silakan login ulang")
+	ErrUnauthenticated     = Unauthorized("UNAUTHENTICATED", "unauthorized: identitas user tidak ditemukan")
+	ErrNoPesertaProfile    = Forbidden("NO_PESERTA_PROFILE", "user belum memiliki peserta_id")
+	ErrNoTutorProfile      = Forbidden("NO_TUTOR_PROFILE", "user belum memiliki tutor_id")
+)
+
+// AuthPrincipal adalah identitas user yang sedang login, diambil dari access
+// token. TutorID/PesertaID bernilai 0 jika user tidak memiliki profil tersebut.
+type AuthPrincipal struct {
+	UserID    uint64 `json:"user_id"`
+	Role      string `json:"role"`
+	TutorID   uint64 `json:"tutor_id,omitempty"`
+	PesertaID uint64 `json:"peserta_id,omitempty"`
+	SessionID uint64 `json:"sid"`
+}
+
+// AuthSession adalah satu sesi login (satu perangkat). Semua refresh token
+// hasil rotasi dari login yang sama berada di sesi ini, sehingga mencabut
+// sesi berarti mencabut seluruh keluarga token tersebut.
+type AuthSession struct {
+	ID           uint64     `json:"id"`
+	UserID       uint64     `json:"user_id"`
+	UserAgent    string     `json:"user_agent"`
+	IPAddress    string     `json:"ip_address"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastUsedAt   time.Time  `json:"last_used_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason string     `json:"revoke_reason,omitempty"`
+}
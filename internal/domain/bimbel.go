@@ -2,17 +2,183 @@ package domain
 
 import "time"
 
+var (
+	ErrBimbelDuplicate = Conflict("BIMBEL_DUPLICATE", "nama bimbel sudah digunakan untuk fitur dan mata pelajaran ini")
+	ErrBimbelNameTaken = Conflict("BIMBEL_NAME_TAKEN", "nama bimbel sudah digunakan")
+
+	ErrBimbelNotInTrash    = NotFound("BIMBEL_NOT_IN_TRASH", "bimbel tidak ditemukan di trash")
+	ErrBimbelParentInTrash = Conflict("BIMBEL_PARENT_IN_TRASH", "fitur atau mata pelajaran bimbel ini masih di trash; pulihkan terlebih dahulu")
+
+	ErrInvalidBimbelTransition = Conflict("INVALID_BIMBEL_TRANSITION", "perubahan status bimbel tidak diizinkan")
+	ErrBimbelNotEditable       = Conflict("BIMBEL_NOT_EDITABLE", "bimbel yang sedang direview atau diarsipkan tidak dapat diubah")
+	ErrBimbelReviewRequired    = Forbidden("BIMBEL_REVIEW_REQUIRED", "bimbel harus diajukan dan disetujui admin sebelum dipublikasikan")
+)
+
+// Status siklus hidup bimbel
+const (
+	BimbelStatusDraft         = "draft"          // bisa diubah, tidak tampil
+	BimbelStatusPendingReview = "pending_review" // menunggu persetujuan admin
+	BimbelStatusPublished     = "published"      // tampil di katalog & menerima pendaftaran
+	BimbelStatusClosed        = "closed"         // tampil, tidak menerima pendaftaran (penuh / selesai)
+	BimbelStatusArchived      = "archived"       // disembunyikan permanen, bisa dijadikan draft lagi
+)
+
+// bimbelTransitions mendaftar perubahan status bimbel yang sah
+var bimbelTransitions = map[string][]string{
+	BimbelStatusDraft:         {BimbelStatusPendingReview, BimbelStatusPublished, BimbelStatusArchived},
+	BimbelStatusPendingReview: {BimbelStatusPublished, BimbelStatusDraft},
+	BimbelStatusPublished:     {BimbelStatusClosed, BimbelStatusArchived},
+	BimbelStatusClosed:        {BimbelStatusPublished, BimbelStatusArchived},
+	BimbelStatusArchived:      {BimbelStatusDraft},
+}
+
+// CanTransitionBimbel mengecek apakah status bimbel boleh berubah dari from ke to
+func CanTransitionBimbel(from, to string) bool {
+	for _, next := range bimbelTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// BimbelAction adalah satu aksi siklus hidup bimbel (satu endpoint): status
+// asal yang diizinkan dan status tujuannya. Beberapa aksi menuju status yang
+// sama (withdraw, reject, unarchive -> draft), jadi status asal wajib dicek
+// supaya mis. reject tidak bisa meng-unarchive bimbel.
+type BimbelAction struct {
+	Name string
+	From []string
+	To   string
+}
+
+var (
+	BimbelActionSubmit    = BimbelAction{"submit", []string{BimbelStatusDraft}, BimbelStatusPendingReview}
+	BimbelActionWithdraw  = BimbelAction{"withdraw", []string{BimbelStatusPendingReview}, BimbelStatusDraft}
+	BimbelActionPublish   = BimbelAction{"publish", []string{BimbelStatusDraft, BimbelStatusPendingReview, BimbelStatusClosed}, BimbelStatusPublished}
+	BimbelActionReject    = BimbelAction{"reject", []string{BimbelStatusPendingReview}, BimbelStatusDraft}
+	BimbelActionClose     = BimbelAction{"close", []string{BimbelStatusPublished}, BimbelStatusClosed}
+	BimbelActionArchive   = BimbelAction{"archive", []string{BimbelStatusDraft, BimbelStatusPublished, BimbelStatusClosed}, BimbelStatusArchived}
+	BimbelActionUnarchive = BimbelAction{"unarchive", []string{BimbelStatusArchived}, BimbelStatusDraft}
+)
+
+// Allows true jika aksi boleh dijalankan pada bimbel berstatus from
+func (a BimbelAction) Allows(from string) bool {
+	if !CanTransitionBimbel(from, a.To) {
+		return false
+	}
+	for _, f := range a.From {
+		if f == from {
+			return true
+		}
+	}
+	return false
+}
+
+// BimbelVisibleStatuses adalah status yang boleh dilihat selain pemilik dan
+// admin: tampil di katalog maupun halaman detail
+var BimbelVisibleStatuses = []string{BimbelStatusPublished, BimbelStatusClosed}
+
+// BimbelVisible true untuk status yang boleh dilihat selain pemilik dan admin
+func BimbelVisible(status string) bool {
+	for _, s := range BimbelVisibleStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// BimbelEditable true untuk status yang isinya masih boleh diubah pemilik
+func BimbelEditable(status string) bool {
+	return status != BimbelStatusPendingReview && status != BimbelStatusArchived
+}
+
+// Perubahan status otomatis dicatat dengan actor_role system
+const (
+	BimbelActorSystem = "system"
+
+	BimbelReasonFull     = "kuota peserta penuh"
+	BimbelReasonFinished = "semua sesi sudah selesai"
+	BimbelReasonSeatFree = "kursi peserta tersedia kembali"
+)
+
+// ShouldReopenBimbel true jika bimbel closed yang terakhir ditutup sistem
+// karena kuota penuh kini punya kursi kosong lagi. taken adalah jumlah
+// enrollment active + pending; last adalah riwayat status terakhir (boleh nil).
+// Bimbel yang ditutup manual atau karena sesinya selesai tidak dibuka lagi.
+func ShouldReopenBimbel(status string, limit, taken int, last *BimbelStatusChange) bool {
+	return status == BimbelStatusClosed && limit > 0 && taken < limit &&
+		last != nil && last.ToStatus == BimbelStatusClosed &&
+		last.ActorRole == BimbelActorSystem && last.Reason == BimbelReasonFull
+}
+
+// BimbelStatusChange adalah satu baris riwayat perubahan status bimbel.
+// ActorUserID nil berarti perubahan dilakukan sistem.
+type BimbelStatusChange struct {
+	ID          uint64    `json:"id"`
+	BimbelID    uint64    `json:"bimbel_id"`
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	ActorUserID *uint64   `json:"actor_user_id"`
+	ActorRole   string    `json:"actor_role"`
+	Reason      string    `json:"reason,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 type Bimbel struct {
-	ID           uint64    `json:"id"`
-	TutorID      uint64    `json:"tutor_id"`
-	FeatureID    uint64    `json:"feature_id"`
-	SubjectID    uint64    `json:"subject_id"`
-	Name         string    `json:"name"`
-	LimitPeserta int       `json:"limit_peserta"`
-	IsActive     bool      `json:"is_active"`
-	Thumbnail    string    `json:"thumbnail"`
-	Deskripsi    string    `json:"deskripsi"`
-	Harga        float64   `json:"harga"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                uint64         `json:"id"`
+	TutorID           uint64         `json:"tutor_id"`
+	FeatureID         uint64         `json:"feature_id"`
+	SubjectID         uint64         `json:"subject_id"`
+	Name              string         `json:"name"`
+	LimitPeserta      int            `json:"limit_peserta"`
+	Status            string         `json:"status"`
+	Thumbnail         string         `json:"-"`                            // object key di storage
+	ThumbnailURL      string         `json:"thumbnail"`                    // diisi delivery layer saat response
+	ThumbnailVariants []ImageVariant `json:"thumbnail_variants,omitempty"` // hasil resize JPEG & WebP
+	Deskripsi         string         `json:"deskripsi"`
+	Harga             int64          `json:"harga"` // rupiah bulat
+	RatingAvg         float64        `json:"rating_avg"`
+	RatingCount       int            `json:"rating_count"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         *time.Time     `json:"deleted_at,omitempty"` // hanya terisi di trash
+}
+
+// ImageVariant adalah satu hasil resize gambar upload
+type ImageVariant struct {
+	Key    string `json:"-"` // object key di storage
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"` // jpeg | webp
+	URL    string `json:"url"`    // diisi delivery layer saat response
+}
+
+const (
+	BimbelSortNewest    = "newest"
+	BimbelSortPriceAsc  = "price_asc"
+	BimbelSortPriceDesc = "price_desc"
+	BimbelSortName      = "name"
+)
+
+// BimbelFilter berisi parameter pencarian katalog bimbel
+type BimbelFilter struct {
+	FeatureID uint64
+	SubjectID uint64
+	TutorID   uint64
+	MinHarga  *int64
+	MaxHarga  *int64
+	Sort      string
+	Page      int
+	Limit     int
+}
+
+// BimbelPage adalah hasil katalog bimbel beserta info pagination
+type BimbelPage struct {
+	Items      []Bimbel `json:"items"`
+	Page       int      `json:"page"`
+	Limit      int      `json:"limit"`
+	Total      int64    `json:"total"`
+	TotalPages int      `json:"total_pages"`
 }
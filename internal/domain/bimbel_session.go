@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+)
+
+var (
+	ErrSessionNotFound    = NotFound("SESSION_NOT_FOUND", "sesi bimbel tidak ditemukan")
+	ErrRecurrenceNotFound = NotFound("RECURRENCE_NOT_FOUND", "jadwal berulang tidak ditemukan")
+	ErrSessionConflict    = Conflict("SESSION_CONFLICT", "jadwal bentrok dengan sesi lain milik tutor")
+)
+
+// BimbelSession adalah satu pertemuan bimbel. StartAt/EndAt disimpan dalam UTC
+// dan ditampilkan sesuai Timezone sesi.
+type BimbelSession struct {
+	ID           uint64    `json:"id"`
+	BimbelID     uint64    `json:"bimbel_id"`
+	RecurrenceID *uint64   `json:"recurrence_id,omitempty"`
+	Title        string    `json:"title"`
+	StartAt      time.Time `json:"start_at"`
+	EndAt        time.Time `json:"end_at"`
+	Timezone     string    `json:"timezone"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// SessionRecurrence adalah aturan jadwal mingguan yang dipecah menjadi
+// BimbelSession konkret. Weekdays memakai 0 = Minggu s/d 6 = Sabtu.
+type SessionRecurrence struct {
+	ID              uint64    `json:"id"`
+	BimbelID        uint64    `json:"bimbel_id"`
+	Title           string    `json:"title"`
+	Weekdays        []int     `json:"weekdays"`
+	StartTime       string    `json:"start_time"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Timezone        string    `json:"timezone"`
+	StartDate       string    `json:"start_date"`
+	EndDate         string    `json:"end_date"`
+	CreatedAt       time.Time `json:"created_at"`
+}
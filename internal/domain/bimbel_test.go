@@ -0,0 +1,110 @@
+package domain
+
+import "testing"
+
+var allBimbelStatuses = []string{
+	BimbelStatusDraft,
+	BimbelStatusPendingReview,
+	BimbelStatusPublished,
+	BimbelStatusClosed,
+	BimbelStatusArchived,
+}
+
+func TestCanTransitionBimbel(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{BimbelStatusDraft, BimbelStatusPendingReview}:     true,
+		{BimbelStatusDraft, BimbelStatusPublished}:         true,
+		{BimbelStatusDraft, BimbelStatusArchived}:          true,
+		{BimbelStatusPendingReview, BimbelStatusPublished}: true,
+		{BimbelStatusPendingReview, BimbelStatusDraft}:     true,
+		{BimbelStatusPublished, BimbelStatusClosed}:        true,
+		{BimbelStatusPublished, BimbelStatusArchived}:      true,
+		{BimbelStatusClosed, BimbelStatusPublished}:        true,
+		{BimbelStatusClosed, BimbelStatusArchived}:         true,
+		{BimbelStatusArchived, BimbelStatusDraft}:          true,
+	}
+	for _, from := range allBimbelStatuses {
+		for _, to := range allBimbelStatuses {
+			want := allowed[[2]string{from, to}]
+			if got := CanTransitionBimbel(from, to); got != want {
+				t.Errorf("CanTransitionBimbel(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+	if CanTransitionBimbel("", BimbelStatusDraft) || CanTransitionBimbel(BimbelStatusDraft, "deleted") {
+		t.Error("status tidak dikenal diizinkan")
+	}
+}
+
+func TestBimbelActionAllows(t *testing.T) {
+	// Status asal yang diterima setiap endpoint; selain itu harus Conflict
+	allowed := map[string][]string{
+		BimbelActionSubmit.Name:    {BimbelStatusDraft},
+		BimbelActionWithdraw.Name:  {BimbelStatusPendingReview},
+		BimbelActionPublish.Name:   {BimbelStatusDraft, BimbelStatusPendingReview, BimbelStatusClosed},
+		BimbelActionReject.Name:    {BimbelStatusPendingReview},
+		BimbelActionClose.Name:     {BimbelStatusPublished},
+		BimbelActionArchive.Name:   {BimbelStatusDraft, BimbelStatusPublished, BimbelStatusClosed},
+		BimbelActionUnarchive.Name: {BimbelStatusArchived},
+	}
+	actions := []BimbelAction{
+		BimbelActionSubmit, BimbelActionWithdraw, BimbelActionPublish, BimbelActionReject,
+		BimbelActionClose, BimbelActionArchive, BimbelActionUnarchive,
+	}
+	for _, a := range actions {
+		for _, from := range allBimbelStatuses {
+			want := false
+			for _, s := range allowed[a.Name] {
+				want = want || s == from
+			}
+			if got := a.Allows(from); got != want {
+				t.Errorf("%s.Allows(%s) = %v, want %v", a.Name, from, got, want)
+			}
+		}
+	}
+}
+
+func TestShouldReopenBimbel(t *testing.T) {
+	full := &BimbelStatusChange{
+		FromStatus: BimbelStatusPublished,
+		ToStatus:   BimbelStatusClosed,
+		ActorRole:  BimbelActorSystem,
+		Reason:     BimbelReasonFull,
+	}
+	manual := &BimbelStatusChange{
+		FromStatus: BimbelStatusPublished,
+		ToStatus:   BimbelStatusClosed,
+		ActorRole:  RoleTutor,
+		Reason:     BimbelReasonFull,
+	}
+	reopened := &BimbelStatusChange{
+		FromStatus: BimbelStatusClosed,
+		ToStatus:   BimbelStatusPublished,
+		ActorRole:  BimbelActorSystem,
+		Reason:     BimbelReasonSeatFree,
+	}
+
+	tests := []struct {
+		name   string
+		status string
+		limit  int
+		taken  int
+		last   *BimbelStatusChange
+		want   bool
+	}{
+		{"kursi kosong setelah penuh", BimbelStatusClosed, 10, 9, full, true},
+		{"masih penuh", BimbelStatusClosed, 10, 10, full, false},
+		{"ditutup manual", BimbelStatusClosed, 10, 3, manual, false},
+		{"tanpa riwayat", BimbelStatusClosed, 10, 3, nil, false},
+		{"tanpa batas kuota", BimbelStatusClosed, 0, 0, full, false},
+		{"sudah published", BimbelStatusPublished, 10, 3, reopened, false},
+		{"diarsipkan", BimbelStatusArchived, 10, 3, full, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldReopenBimbel(tt.status, tt.limit, tt.taken, tt.last); got != tt.want {
+				t.Errorf("ShouldReopenBimbel = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	EnrollmentStatusPending   = "pending" // menunggu pembayaran, kursi sudah dipesan
+	EnrollmentStatusActive    = "active"
+	EnrollmentStatusCancelled = "cancelled"
+	EnrollmentStatusCompleted = "completed"
+)
+
+var (
+	ErrBimbelNotFound     = NotFound("BIMBEL_NOT_FOUND", "bimbel tidak ditemukan")
+	ErrBimbelInactive     = Unprocessable("BIMBEL_INACTIVE", "bimbel tidak sedang menerima pendaftaran")
+	ErrBimbelFull         = Conflict("BIMBEL_FULL", "kuota peserta bimbel sudah penuh")
+	ErrAlreadyEnrolled    = Conflict("ALREADY_ENROLLED", "peserta sudah terdaftar di bimbel ini")
+	ErrEnrollmentNotFound = NotFound("ENROLLMENT_NOT_FOUND", "enrollment tidak ditemukan")
+	ErrEnrollmentPaid     = Conflict("ENROLLMENT_PAID", "enrollment yang sudah dibayar tidak bisa dibatalkan, hubungi admin untuk refund")
+)
+
+type Enrollment struct {
+	ID          uint64     `json:"id"`
+	BimbelID    uint64     `json:"bimbel_id"`
+	PesertaID   uint64     `json:"peserta_id"`
+	Status      string     `json:"status"`
+	BimbelName  string     `json:"bimbel_name,omitempty"`
+	EnrolledAt  time.Time  `json:"enrolled_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Invoice     *Invoice   `json:"invoice,omitempty"`
+}
@@ -0,0 +1,96 @@
+package domain
+
+import "errors"
+
+// ErrorKind menentukan kategori error; delivery layer memetakannya ke HTTP status
+type ErrorKind string
+
+const (
+	KindBadRequest    ErrorKind = "bad_request"   // request tidak bisa dibaca (body/param rusak)
+	KindValidation    ErrorKind = "validation"    // request terbaca tapi isinya tidak valid
+	KindUnauthorized  ErrorKind = "unauthorized"  // belum login / kredensial salah
+	KindForbidden     ErrorKind = "forbidden"     // login tapi tidak berhak
+	KindNotFound      ErrorKind = "not_found"     // resource tidak ada
+	KindConflict      ErrorKind = "conflict"      // bentrok dengan state saat ini
+	KindUnprocessable ErrorKind = "unprocessable" // input valid tapi melanggar aturan bisnis
+)
+
+// Kode error umum; error spesifik memakai kode sendiri, mis. BIMBEL_NOT_FOUND
+const (
+	CodeValidation   = "VALIDATION_ERROR"
+	CodeInvalidBody  = "INVALID_BODY"
+	CodeInvalidParam = "INVALID_PARAM"
+	CodeForbidden    = "FORBIDDEN"
+	CodeUnauthorized = "UNAUTHORIZED"
+	CodeNotFound     = "NOT_FOUND"
+	CodeInternal     = "INTERNAL_ERROR"
+)
+
+// Error adalah error domain bertipe. Code bersifat stabil dan dikirim ke
+// client sebagai error_code, sedangkan Message boleh berubah.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Fields  map[string]string // detail per field untuk KindValidation
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func NotFound(code, message string) *Error {
+	return NewError(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return NewError(KindConflict, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return NewError(KindForbidden, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return NewError(KindUnauthorized, code, message)
+}
+
+func Unprocessable(code, message string) *Error {
+	return NewError(KindUnprocessable, code, message)
+}
+
+// Validation membuat error input dengan detail per field (boleh nil)
+func Validation(message string, fields map[string]string) *Error {
+	return &Error{Kind: KindValidation, Code: CodeValidation, Message: message, Fields: fields}
+}
+
+// InvalidField adalah Validation untuk satu field
+func InvalidField(field, message string) *Error {
+	return Validation(message, map[string]string{field: message})
+}
+
+// InvalidParam dipakai handler untuk path/query param yang tidak bisa di-parse
+func InvalidParam(name string) *Error {
+	return &Error{
+		Kind:    KindBadRequest,
+		Code:    CodeInvalidParam,
+		Message: name + " tidak valid",
+		Fields:  map[string]string{name: name + " tidak valid"},
+	}
+}
+
+// ErrInvalidBody dipakai handler saat body request gagal di-parse
+var ErrInvalidBody = NewError(KindBadRequest, CodeInvalidBody, "invalid request body")
+
+// AsError mengambil *Error dari rantai error (mis. hasil fmt.Errorf("%w"))
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
@@ -0,0 +1,15 @@
+package domain
+
+// Struct Feature dan Matpel masih berada di package repository
+var (
+	ErrFeatureNotFound  = NotFound("FEATURE_NOT_FOUND", "fitur tidak ditemukan")
+	ErrFeatureNameTaken = Conflict("FEATURE_NAME_TAKEN", "fitur dengan nama tersebut sudah ada")
+	ErrMatpelNotFound   = NotFound("MATPEL_NOT_FOUND", "mata pelajaran tidak ditemukan")
+	ErrMatpelNameTaken  = Conflict("MATPEL_NAME_TAKEN", "mata pelajaran dengan nama tersebut sudah ada pada feature ini")
+
+	ErrFeatureInUse      = Conflict("FEATURE_IN_USE", "fitur masih dipakai bimbel aktif; hapus atau pindahkan bimbelnya terlebih dahulu")
+	ErrMatpelInUse       = Conflict("MATPEL_IN_USE", "mata pelajaran masih dipakai bimbel aktif; hapus atau pindahkan bimbelnya terlebih dahulu")
+	ErrFeatureNotInTrash = NotFound("FEATURE_NOT_IN_TRASH", "fitur tidak ditemukan di trash")
+	ErrMatpelNotInTrash  = NotFound("MATPEL_NOT_IN_TRASH", "mata pelajaran tidak ditemukan di trash")
+	ErrFeatureInTrash    = Conflict("FEATURE_IN_TRASH", "fitur induk masih di trash; pulihkan fiturnya terlebih dahulu")
+)
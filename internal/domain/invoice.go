@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+const (
+	InvoiceStatusPending   = "pending"
+	InvoiceStatusPaid      = "paid"
+	InvoiceStatusExpired   = "expired"
+	InvoiceStatusCancelled = "cancelled"
+	InvoiceStatusRefunded  = "refunded"
+)
+
+var (
+	ErrInvoiceNotFound          = NotFound("INVOICE_NOT_FOUND", "invoice tidak ditemukan")
+	ErrInvalidInvoiceTransition = Conflict("INVALID_INVOICE_TRANSITION", "perubahan status invoice tidak diizinkan")
+)
+
+// invoiceTransitions mendaftar perubahan status invoice yang sah
+var invoiceTransitions = map[string][]string{
+	InvoiceStatusPending: {InvoiceStatusPaid, InvoiceStatusExpired, InvoiceStatusCancelled},
+	InvoiceStatusPaid:    {InvoiceStatusRefunded},
+}
+
+// CanTransitionInvoice mengecek apakah status invoice boleh berubah dari from ke to
+func CanTransitionInvoice(from, to string) bool {
+	for _, next := range invoiceTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Invoice adalah tagihan pendaftaran bimbel berbayar. Amount dalam rupiah bulat.
+type Invoice struct {
+	ID           uint64     `json:"id"`
+	Number       string     `json:"number"`
+	EnrollmentID uint64     `json:"enrollment_id"`
+	BimbelID     uint64     `json:"bimbel_id"`
+	PesertaID    uint64     `json:"peserta_id"`
+	Amount       int64      `json:"amount"`
+	Status       string     `json:"status"`
+	Provider     string     `json:"provider"`
+	ProviderRef  string     `json:"provider_ref"`
+	PaymentURL   string     `json:"payment_url"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	PaidAt       *time.Time `json:"paid_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
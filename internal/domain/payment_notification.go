@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	NotificationResultProcessed   = "processed"
+	NotificationResultDuplicate   = "duplicate"
+	NotificationResultIgnored     = "ignored"
+	NotificationResultNeedsReview = "needs_review"
+	NotificationResultRejected    = "rejected"
+	NotificationResultFailed      = "failed"
+)
+
+var (
+	ErrInvalidSignature          = Unauthorized("INVALID_SIGNATURE", "signature webhook tidak valid")
+	ErrNotificationNotFound      = NotFound("NOTIFICATION_NOT_FOUND", "notifikasi pembayaran tidak ditemukan")
+	ErrNotificationNotReplayable = Unprocessable("NOTIFICATION_NOT_REPLAYABLE", "notifikasi dengan signature tidak valid tidak dapat diproses ulang")
+)
+
+// PaymentNotification adalah catatan mentah setiap webhook dari payment
+// gateway, disimpan untuk audit dan replay manual
+type PaymentNotification struct {
+	ID             uint64     `json:"id"`
+	Provider       string     `json:"provider"`
+	EventID        string     `json:"event_id"`
+	InvoiceNumber  string     `json:"invoice_number"`
+	GatewayStatus  string     `json:"gateway_status"`
+	SignatureValid bool       `json:"signature_valid"`
+	Payload        string     `json:"payload"`
+	Result         string     `json:"result"`
+	ResultMessage  string     `json:"result_message"`
+	ProcessedAt    *time.Time `json:"processed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
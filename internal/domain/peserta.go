@@ -0,0 +1,63 @@
+package domain
+
+// Jenjang pendidikan peserta
+const (
+	JenjangSD     = "SD"
+	JenjangSMP    = "SMP"
+	JenjangSMA    = "SMA"
+	JenjangSMK    = "SMK"
+	JenjangKuliah = "KULIAH"
+	JenjangUmum   = "UMUM"
+)
+
+var jenjangMaxGrade = map[string]int{
+	JenjangSD:     6,
+	JenjangSMP:    3,
+	JenjangSMA:    3,
+	JenjangSMK:    3,
+	JenjangKuliah: 0,
+	JenjangUmum:   0,
+}
+
+// IsValidJenjang mengecek jenjang; max grade 0 berarti kelas tidak dipakai
+func IsValidJenjang(jenjang string) (maxGrade int, ok bool) {
+	maxGrade, ok = jenjangMaxGrade[jenjang]
+	return
+}
+
+var (
+	ErrPesertaNotFound = NotFound("PESERTA_NOT_FOUND", "peserta tidak ditemukan")
+)
+
+type PesertaProfile struct {
+	ID            uint64  `json:"id"`
+	UserID        uint64  `json:"user_id"`
+	Name          string  `json:"name"`
+	Email         string  `json:"email"`
+	School        string  `json:"school"`
+	Jenjang       string  `json:"jenjang"`
+	Grade         int     `json:"grade"`
+	BirthDate     *string `json:"birth_date"` // format 2006-01-02
+	GuardianName  string  `json:"guardian_name"`
+	GuardianPhone string  `json:"guardian_phone"`
+}
+
+// PesertaLimitedProfile adalah tampilan profil peserta untuk tutor:
+// tanpa email, tanggal lahir, dan kontak wali
+type PesertaLimitedProfile struct {
+	ID      uint64 `json:"id"`
+	Name    string `json:"name"`
+	School  string `json:"school"`
+	Jenjang string `json:"jenjang"`
+	Grade   int    `json:"grade"`
+}
+
+func (p *PesertaProfile) Limited() *PesertaLimitedProfile {
+	return &PesertaLimitedProfile{
+		ID:      p.ID,
+		Name:    p.Name,
+		School:  p.School,
+		Jenjang: p.Jenjang,
+		Grade:   p.Grade,
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+var (
+	ErrReviewNotFound       = NotFound("REVIEW_NOT_FOUND", "review tidak ditemukan")
+	ErrReviewExists         = Conflict("REVIEW_EXISTS", "peserta sudah memberikan review untuk bimbel ini")
+	ErrReviewNotAllowed     = Forbidden("REVIEW_NOT_ALLOWED", "review hanya bisa diberikan setelah bimbel selesai diikuti")
+	ErrReviewAlreadyReplied = Conflict("REVIEW_ALREADY_REPLIED", "review sudah dibalas oleh tutor")
+	ErrInvalidRating        = NewError(KindValidation, "INVALID_RATING", "rating harus antara 1 sampai 5")
+)
+
+type Review struct {
+	ID           uint64     `json:"id"`
+	BimbelID     uint64     `json:"bimbel_id"`
+	PesertaID    uint64     `json:"peserta_id"`
+	PesertaName  string     `json:"peserta_name"`
+	EnrollmentID uint64     `json:"enrollment_id"`
+	Rating       int        `json:"rating"`
+	Comment      string     `json:"comment"`
+	TutorReply   *string    `json:"tutor_reply,omitempty"`
+	RepliedAt    *time.Time `json:"replied_at,omitempty"`
+	IsHidden     bool       `json:"is_hidden"`
+	HiddenReason string     `json:"hidden_reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// RatingAverage menghitung rata-rata rating dari agregat yang disimpan,
+// dibulatkan 2 angka di belakang koma
+func RatingAverage(sum int64, count int) float64 {
+	if count <= 0 {
+		return 0
+	}
+	return math.Round(float64(sum)/float64(count)*100) / 100
+}
@@ -0,0 +1,33 @@
+package domain
+
+// Role yang dikenal sistem
+const (
+	RoleAdmin   = "admin"
+	RoleTutor   = "tutor"
+	RolePeserta = "peserta"
+)
+
+var (
+	ErrInvalidRole        = NewError(KindValidation, "INVALID_ROLE", "role harus salah satu dari admin, tutor, peserta")
+	ErrRoleNotRegistrable = Forbidden("ROLE_NOT_REGISTRABLE", "registrasi publik hanya untuk role tutor atau peserta")
+	ErrUserNotFound       = NotFound("USER_NOT_FOUND", "user tidak ditemukan")
+	ErrEmailTaken         = Conflict("EMAIL_TAKEN", "email sudah terdaftar")
+	ErrRoleChangeBlocked  = Conflict("ROLE_CHANGE_BLOCKED", "role tidak dapat diubah karena masih memiliki bimbel atau pendaftaran aktif")
+	ErrCannotModifySelf   = Forbidden("CANNOT_MODIFY_SELF", "admin tidak dapat mengubah role, status, atau menghapus akunnya sendiri")
+	ErrUserNotDeleted     = Conflict("USER_NOT_DELETED", "user tidak dalam keadaan terhapus")
+	ErrAccountInactive    = Unauthorized("ACCOUNT_INACTIVE", "akun tidak aktif")
+)
+
+// IsValidRole mengecek apakah role termasuk enum yang dikenal
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleTutor, RolePeserta:
+		return true
+	}
+	return false
+}
+
+// IsSelfRegistrableRole mengecek role yang boleh dipilih saat registrasi publik
+func IsSelfRegistrableRole(role string) bool {
+	return role == RoleTutor || role == RolePeserta
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// PurgeReport adalah jumlah baris trash yang dihapus permanen
+type PurgeReport struct {
+	Before   time.Time `json:"before"` // hanya baris yang dihapus sebelum ini
+	DryRun   bool      `json:"dry_run"`
+	Bimbels  int64     `json:"bimbels"`
+	Subjects int64     `json:"subjects"`
+	Features int64     `json:"features"`
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	TutorVerificationUnverified = "unverified"
+	TutorVerificationPending    = "pending"
+	TutorVerificationVerified   = "verified"
+	TutorVerificationRejected   = "rejected"
+)
+
+var (
+	ErrTutorNotFound              = NotFound("TUTOR_NOT_FOUND", "tutor tidak ditemukan")
+	ErrTutorNotVerified           = Unprocessable("TUTOR_NOT_VERIFIED", "tutor belum terverifikasi, bimbel belum bisa dipublikasikan")
+	ErrTutorDocumentNotFound      = NotFound("TUTOR_DOCUMENT_NOT_FOUND", "dokumen tutor tidak ditemukan")
+	ErrVerificationNotSubmittable = Conflict("VERIFICATION_NOT_SUBMITTABLE", "pengajuan verifikasi tidak dapat dikirim pada status ini")
+	ErrVerificationNotPending     = Conflict("VERIFICATION_NOT_PENDING", "tutor tidak sedang menunggu verifikasi")
+	ErrVerificationNoDocuments    = Unprocessable("VERIFICATION_NO_DOCUMENTS", "unggah minimal satu dokumen sebelum mengajukan verifikasi")
+)
+
+type TutorSubject struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+type TutorProfile struct {
+	ID                 uint64          `json:"id"`
+	UserID             uint64          `json:"user_id"`
+	Name               string          `json:"name"`
+	Bio                string          `json:"bio"`
+	Education          string          `json:"education"`
+	Experience         string          `json:"experience"`
+	City               string          `json:"city"`
+	Avatar             string          `json:"-"`          // object key di storage
+	AvatarURL          string          `json:"avatar_url"` // diisi delivery layer saat response
+	Subjects           []TutorSubject  `json:"subjects"`
+	VerificationStatus string          `json:"verification_status"`
+	RejectionReason    string          `json:"rejection_reason,omitempty"`
+	VerifiedAt         *time.Time      `json:"verified_at,omitempty"`
+	RatingAvg          float64         `json:"rating_avg"`
+	RatingCount        int             `json:"rating_count"`
+	Documents          []TutorDocument `json:"documents,omitempty"`
+	Bimbels            []Bimbel        `json:"bimbels,omitempty"`
+}
+
+// TutorDocument adalah berkas pendukung verifikasi (ijazah, KTP, sertifikat).
+// File disimpan di luar prefix publik storage; URL-nya berupa signed URL
+// berumur pendek yang hanya diberikan ke pemilik dan admin.
+type TutorDocument struct {
+	ID        uint64    `json:"id"`
+	TutorID   uint64    `json:"tutor_id"`
+	DocType   string    `json:"doc_type"`
+	FileName  string    `json:"file_name"`
+	FilePath  string    `json:"-"` // object key di storage
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// OrphanUpload adalah object di storage yang tidak lagi dirujuk database
+type OrphanUpload struct {
+	Key     string    `json:"key"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Deleted bool      `json:"deleted"`
+}
+
+// UploadGCReport adalah ringkasan satu kali jalan garbage collection upload
+type UploadGCReport struct {
+	Prefix        string         `json:"prefix"`
+	DryRun        bool           `json:"dry_run"`
+	Cutoff        time.Time      `json:"cutoff"` // object lebih baru dari ini tidak disentuh
+	Scanned       int            `json:"scanned"`
+	Referenced    int            `json:"referenced"`
+	InGracePeriod int            `json:"in_grace_period"`
+	Orphans       []OrphanUpload `json:"orphans"`
+	Deleted       int            `json:"deleted"`
+	DeletedBytes  int64          `json:"deleted_bytes"`
+	Failed        int            `json:"failed"`
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type User struct {
 	ID        uint64  `json:"id"`
 	TutorID   *uint64 `json:"tutor_id"`
@@ -8,5 +10,8 @@ type User struct {
 	Email     string  `json:"email"`
 	Password  string  `json:"-"`
 	Role      string  `json:"role"`
-	IsActive  int     `json:"is_active"`
+	IsActive  bool    `json:"is_active"`
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,12 @@
+package domain
+
+// Kegunaan token sekali pakai yang dikirim lewat email
+const (
+	UserTokenPasswordReset     = "password_reset"
+	UserTokenEmailVerification = "email_verification"
+)
+
+var (
+	ErrUserTokenInvalid = NewError(KindValidation, "USER_TOKEN_INVALID", "token tidak valid atau sudah kedaluwarsa")
+	ErrEmailNotVerified = Forbidden("EMAIL_NOT_VERIFIED", "email belum diverifikasi, silakan cek inbox anda")
+)
@@ -6,5 +6,5 @@ type User struct {
 	Email    string `json:"email"`
 	Password string `json:"-"`
 	Role     string `json:"role"`
-	IsActive int    `json:"is_active"`
+	IsActive bool   `json:"is_active"`
 }
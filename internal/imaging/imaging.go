@@ -0,0 +1,222 @@
+// Package imaging memvalidasi dan memproses gambar upload: format dicek dari
+// magic bytes, dimensi dicek dari header sebelum decode (mencegah
+// decompression bomb), lalu gambar di-encode ulang ke beberapa ukuran dalam
+// JPEG dan WebP. Encode ulang sekaligus membuang metadata EXIF.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sort"
+
+	"github.com/chai2010/webp"
+	"golang.org/x/image/draw"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("format gambar harus jpg atau png")
+	ErrInvalidImage      = errors.New("file gambar rusak atau tidak dapat dibaca")
+	ErrTooLarge          = errors.New("ukuran file gambar terlalu besar")
+	ErrDimensionsTooBig  = errors.New("dimensi gambar terlalu besar")
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatWebP = "webp"
+)
+
+type Options struct {
+	MaxBytes     int64   // ukuran file maksimal
+	MaxDimension int     // sisi terpanjang maksimal gambar asli
+	MaxPixels    int     // width*height maksimal, dicek sebelum decode
+	Widths       []int   // lebar varian; tidak pernah di-upscale
+	JPEGQuality  int     // 1-100
+	WebPQuality  float32 // 0-100
+}
+
+// ThumbnailOptions dipakai untuk thumbnail bimbel
+var ThumbnailOptions = Options{
+	MaxBytes:     4 << 20,
+	MaxDimension: 8000,
+	MaxPixels:    25_000_000, // ~100 MB RGBA setelah decode
+	Widths:       []int{320, 800, 1600},
+	JPEGQuality:  82,
+	WebPQuality:  80,
+}
+
+// Variant adalah satu hasil encode
+type Variant struct {
+	Width       int
+	Height      int
+	Format      string // jpeg | webp
+	Ext         string
+	ContentType string
+	Data        []byte
+}
+
+// Process membaca gambar dari r dan menghasilkan varian per lebar di
+// opts.Widths, masing-masing dalam JPEG dan WebP, urut dari yang terkecil.
+// Error validasi (ErrUnsupportedFormat, ErrTooLarge, ...) aman ditampilkan ke user.
+func Process(r io.Reader, opts Options) ([]Variant, error) {
+	data, err := io.ReadAll(io.LimitReader(r, opts.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > opts.MaxBytes {
+		return nil, fmt.Errorf("%w (maksimal %d MB)", ErrTooLarge, opts.MaxBytes>>20)
+	}
+
+	format := sniffFormat(data)
+	if format == "" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	// Cek dimensi dari header dulu; decode penuh baru dilakukan jika aman
+	cfg, err := decodeConfig(format, data)
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 ||
+		cfg.Width > opts.MaxDimension || cfg.Height > opts.MaxDimension ||
+		cfg.Width*cfg.Height > opts.MaxPixels {
+		return nil, fmt.Errorf("%w (maksimal %dx%d px)", ErrDimensionsTooBig, opts.MaxDimension, opts.MaxDimension)
+	}
+
+	src, err := decode(format, data)
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	// Orientasi EXIF diterapkan ke piksel karena EXIF-nya sendiri dibuang.
+	// Rotasi dilakukan setelah resize supaya murah.
+	orientation := 1
+	if format == FormatJPEG {
+		orientation = jpegOrientation(data)
+	}
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	outW, outH := srcW, srcH
+	if swapsAxes(orientation) {
+		outW, outH = srcH, srcW
+	}
+
+	opaque := isOpaque(src)
+	var variants []Variant
+	for _, w := range targetWidths(outW, opts.Widths) {
+		h := max(1, outH*w/outW)
+		if swapsAxes(orientation) {
+			w, h = h, w
+		}
+		img := orient(resize(src, w, h), orientation)
+
+		jpg, err := encodeJPEG(img, opaque, opts.JPEGQuality)
+		if err != nil {
+			return nil, err
+		}
+		wp, err := encodeWebP(img, opaque, opts.WebPQuality)
+		if err != nil {
+			return nil, err
+		}
+
+		b := img.Bounds()
+		variants = append(variants,
+			Variant{Width: b.Dx(), Height: b.Dy(), Format: FormatJPEG, Ext: ".jpg", ContentType: "image/jpeg", Data: jpg},
+			Variant{Width: b.Dx(), Height: b.Dy(), Format: FormatWebP, Ext: ".webp", ContentType: "image/webp", Data: wp},
+		)
+	}
+	return variants, nil
+}
+
+// sniffFormat mengenali format dari magic bytes, bukan dari ekstensi file
+func sniffFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return FormatJPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	default:
+		return ""
+	}
+}
+
+func decodeConfig(format string, data []byte) (image.Config, error) {
+	if format == FormatJPEG {
+		return jpeg.DecodeConfig(bytes.NewReader(data))
+	}
+	return png.DecodeConfig(bytes.NewReader(data))
+}
+
+func decode(format string, data []byte) (image.Image, error) {
+	if format == FormatJPEG {
+		return jpeg.Decode(bytes.NewReader(data))
+	}
+	return png.Decode(bytes.NewReader(data))
+}
+
+// targetWidths membatasi lebar varian ke lebar asli dan membuang duplikat,
+// mis. gambar 600px dengan Widths 320/800/1600 menghasilkan 320 dan 600
+func targetWidths(srcWidth int, widths []int) []int {
+	seen := map[int]bool{}
+	var result []int
+	for _, w := range widths {
+		if w > srcWidth {
+			w = srcWidth
+		}
+		if !seen[w] {
+			seen[w] = true
+			result = append(result, w)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// resize menghasilkan NRGBA (alpha tidak premultiplied) berukuran width x height
+func resize(src image.Image, width, height int) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if width == b.Dx() && height == b.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	} else {
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	}
+	return dst
+}
+
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// encodeJPEG meratakan gambar transparan ke latar putih karena JPEG tidak punya alpha
+func encodeJPEG(img *image.NRGBA, opaque bool, quality int) ([]byte, error) {
+	var src image.Image = img
+	if !opaque {
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, image.Point{}, draw.Over)
+		src = flat
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeWebP(img *image.NRGBA, opaque bool, quality float32) ([]byte, error) {
+	if opaque {
+		return webp.EncodeRGB(img, quality)
+	}
+	// libwebp mengharapkan RGBA tanpa premultiply, yaitu layout NRGBA;
+	// dibungkus sebagai *image.RGBA supaya library tidak mengonversinya lagi
+	return webp.EncodeRGBA(&image.RGBA{Pix: img.Pix, Stride: img.Stride, Rect: img.Rect}, quality)
+}
@@ -0,0 +1,234 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/chai2010/webp"
+)
+
+var testOptions = Options{
+	MaxBytes:     1 << 20,
+	MaxDimension: 2000,
+	MaxPixels:    2_000_000,
+	Widths:       []int{320, 800, 1600},
+	JPEGQuality:  80,
+	WebPQuality:  75,
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeTestJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func solid(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// pngHeaderOnly membuat PNG yang IHDR-nya mengklaim ukuran w x h tanpa data
+// piksel yang sesuai, seperti decompression bomb
+func pngHeaderOnly(t *testing.T, w, h uint32) []byte {
+	t.Helper()
+	data := encodePNG(t, solid(1, 1, color.White))
+	// signature (8) + length (4) + "IHDR" (4), lalu width dan height
+	binary.BigEndian.PutUint32(data[16:], w)
+	binary.BigEndian.PutUint32(data[20:], h)
+	crc := crc32.ChecksumIEEE(data[12:29])
+	binary.BigEndian.PutUint32(data[29:], crc)
+	return data
+}
+
+// withOrientation menyisipkan segmen APP1 Exif berisi tag Orientation
+func withOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1}
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], 0x0112)
+	binary.BigEndian.PutUint16(entry[2:], 3) // SHORT
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, segment...)
+	return append(out, jpg[2:]...)
+}
+
+func TestProcessRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		opts Options
+		want error
+	}{
+		{"executable diganti nama", append([]byte("MZ\x90\x00"), make([]byte, 64)...), testOptions, ErrUnsupportedFormat},
+		{"gif", []byte("GIF89a\x01\x00\x01\x00"), testOptions, ErrUnsupportedFormat},
+		{"kosong", nil, testOptions, ErrUnsupportedFormat},
+		{"jpeg rusak", []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x02, 0x00}, testOptions, ErrInvalidImage},
+		{"terlalu besar", encodePNG(t, solid(10, 10, color.White)), Options{MaxBytes: 16}, ErrTooLarge},
+		{"decompression bomb", pngHeaderOnly(t, 50000, 50000), testOptions, ErrDimensionsTooBig},
+		{"melebihi MaxDimension", pngHeaderOnly(t, 2001, 10), testOptions, ErrDimensionsTooBig},
+		{"melebihi MaxPixels", pngHeaderOnly(t, 1500, 1500), testOptions, ErrDimensionsTooBig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Process(bytes.NewReader(tt.data), tt.opts)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessVariants(t *testing.T) {
+	data := encodePNG(t, solid(1000, 500, color.NRGBA{R: 200, G: 10, B: 10, A: 255}))
+
+	variants, err := Process(bytes.NewReader(data), testOptions)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	// 1600 dibatasi ke lebar asli 1000; tidak ada upscale
+	wantWidths := []int{320, 320, 800, 800, 1000, 1000}
+	if len(variants) != len(wantWidths) {
+		t.Fatalf("jumlah varian = %d, want %d", len(variants), len(wantWidths))
+	}
+	for i, v := range variants {
+		if v.Width != wantWidths[i] || v.Height != wantWidths[i]/2 {
+			t.Errorf("varian %d = %dx%d, want %dx%d", i, v.Width, v.Height, wantWidths[i], wantWidths[i]/2)
+		}
+
+		var cfg image.Config
+		switch v.Format {
+		case FormatJPEG:
+			if v.ContentType != "image/jpeg" || v.Ext != ".jpg" {
+				t.Errorf("varian %d: %s %s", i, v.ContentType, v.Ext)
+			}
+			cfg, err = jpeg.DecodeConfig(bytes.NewReader(v.Data))
+		case FormatWebP:
+			if v.ContentType != "image/webp" || v.Ext != ".webp" {
+				t.Errorf("varian %d: %s %s", i, v.ContentType, v.Ext)
+			}
+			cfg, err = webp.DecodeConfig(bytes.NewReader(v.Data))
+		default:
+			t.Fatalf("format tidak dikenal: %s", v.Format)
+		}
+		if err != nil {
+			t.Fatalf("varian %d (%s) tidak bisa dibaca: %v", i, v.Format, err)
+		}
+		if cfg.Width != v.Width || cfg.Height != v.Height {
+			t.Errorf("varian %d header %dx%d, want %dx%d", i, cfg.Width, cfg.Height, v.Width, v.Height)
+		}
+	}
+}
+
+func TestProcessAppliesAndStripsExif(t *testing.T) {
+	jpg := withOrientation(encodeTestJPEG(t, solid(400, 200, color.White)), 6)
+	if jpegOrientation(jpg) != 6 {
+		t.Fatal("fixture EXIF tidak terbaca")
+	}
+
+	variants, err := Process(bytes.NewReader(jpg), Options{
+		MaxBytes: 1 << 20, MaxDimension: 1000, MaxPixels: 1_000_000,
+		Widths: []int{100}, JPEGQuality: 80, WebPQuality: 75,
+	})
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	for _, v := range variants {
+		// Orientasi 6 = putar 90 derajat: gambar landscape menjadi portrait
+		if v.Width != 100 || v.Height != 200 {
+			t.Errorf("%s = %dx%d, want 100x200", v.Format, v.Width, v.Height)
+		}
+		if bytes.Contains(v.Data, []byte("Exif\x00\x00")) {
+			t.Errorf("%s masih memuat EXIF", v.Format)
+		}
+	}
+}
+
+func TestTargetWidths(t *testing.T) {
+	tests := []struct {
+		src    int
+		widths []int
+		want   []int
+	}{
+		{1000, []int{320, 800, 1600}, []int{320, 800, 1000}},
+		{600, []int{1600, 320, 800}, []int{320, 600}},
+		{200, []int{320, 800}, []int{200}},
+	}
+	for _, tt := range tests {
+		got := targetWidths(tt.src, tt.widths)
+		if len(got) != len(tt.want) {
+			t.Errorf("targetWidths(%d, %v) = %v, want %v", tt.src, tt.widths, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("targetWidths(%d, %v) = %v, want %v", tt.src, tt.widths, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// 2x1: kiri merah, kanan biru
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, red)
+	src.SetNRGBA(1, 0, blue)
+
+	tests := []struct {
+		orientation   int
+		w, h          int
+		first, second image.Point // posisi merah dan biru setelah orientasi
+	}{
+		{1, 2, 1, image.Pt(0, 0), image.Pt(1, 0)},
+		{2, 2, 1, image.Pt(1, 0), image.Pt(0, 0)},
+		{3, 2, 1, image.Pt(1, 0), image.Pt(0, 0)},
+		{6, 1, 2, image.Pt(0, 0), image.Pt(0, 1)},
+		{8, 1, 2, image.Pt(0, 1), image.Pt(0, 0)},
+	}
+	for _, tt := range tests {
+		dst := orient(src, tt.orientation)
+		if dst.Rect.Dx() != tt.w || dst.Rect.Dy() != tt.h {
+			t.Errorf("orientasi %d: ukuran %v, want %dx%d", tt.orientation, dst.Rect, tt.w, tt.h)
+			continue
+		}
+		if dst.NRGBAAt(tt.first.X, tt.first.Y) != red || dst.NRGBAAt(tt.second.X, tt.second.Y) != blue {
+			t.Errorf("orientasi %d: piksel tidak sesuai", tt.orientation)
+		}
+	}
+}
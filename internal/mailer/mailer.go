@@ -0,0 +1,42 @@
+package mailer
+
+import "fmt"
+
+// Message adalah email teks sederhana
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer mengirim email. Implementasi dipilih lewat MAIL_DRIVER.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+type Config struct {
+	Driver   string // smtp | file | log
+	From     string
+	SMTPHost string
+	SMTPPort string
+	SMTPUser string
+	SMTPPass string
+	FileDir  string
+}
+
+// New membuat Mailer sesuai driver; driver kosong memakai log
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Driver {
+	case "", "log":
+		return NewLogMailer(), nil
+	case "file":
+		return NewFileMailer(cfg.FileDir, cfg.From), nil
+	case "smtp":
+		if cfg.SMTPHost == "" || cfg.SMTPPort == "" {
+			return nil, fmt.Errorf("SMTP_HOST dan SMTP_PORT wajib diisi untuk MAIL_DRIVER=smtp")
+		}
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("mail driver %q tidak dikenal", cfg.Driver)
+	}
+}
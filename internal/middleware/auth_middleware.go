@@ -1,16 +1,28 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"main-service/internal/auth"
+	"main-service/internal/domain"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 )
 
+var (
+	errMissingToken = domain.Unauthorized("TOKEN_MISSING", "unauthorized: missing or invalid token")
+	errInvalidToken = domain.Unauthorized("TOKEN_INVALID", "unauthorized: invalid or expired token")
+)
+
+// SessionChecker dipakai untuk memastikan sesi login pemilik token belum dicabut
+type SessionChecker interface {
+	IsActive(ctx context.Context, sessionID uint64) (bool, error)
+}
+
 // AuthMiddleware memeriksa validitas JWT dan menambahkan user info ke context
-func AuthMiddleware() fiber.Handler {
+func AuthMiddleware(sessions SessionChecker) fiber.Handler {
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		panic("JWT_SECRET tidak ditemukan di .env")
@@ -19,55 +31,28 @@ func AuthMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status_code": fiber.StatusUnauthorized,
-				"status":      "error",
-				"message":     "unauthorized: missing or invalid token",
-				"data":        nil,
-			})
+			return errMissingToken
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid signing method")
-			}
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status_code": fiber.StatusUnauthorized,
-				"status":      "error",
-				"message":     "unauthorized: invalid or expired token",
-				"data":        nil,
-			})
+		// Tanda tangan, exp, dan kelengkapan claim (termasuk sid) dicek di auth.Parse
+		principal, err := auth.Parse(tokenString, jwtSecret)
+		if err != nil {
+			return errInvalidToken
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status_code": fiber.StatusUnauthorized,
-				"status":      "error",
-				"message":     "unauthorized: invalid claims",
-				"data":        nil,
-			})
+		// Token milik sesi yang sudah logout ditolak
+		active, err := sessions.IsActive(c.UserContext(), principal.SessionID)
+		if err != nil {
+			return fmt.Errorf("gagal memeriksa sesi login: %w", err)
 		}
-
-		// Validasi waktu kedaluwarsa
-		if exp, ok := claims["exp"].(float64); ok && time.Now().Unix() > int64(exp) {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status_code": fiber.StatusUnauthorized,
-				"status":      "error",
-				"message":     "token expired",
-				"data":        nil,
-			})
+		if !active {
+			return domain.ErrSessionRevoked
 		}
 
-		// Simpan user info ke context
-		c.Locals("user_id", claims["user_id"])
-		c.Locals("role", claims["role"])
+		// Simpan identitas user ke context; handler membacanya lewat auth.PrincipalFrom
+		auth.SetPrincipal(c, principal)
 
 		return c.Next()
 	}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"main-service/internal/auth"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Authorize menolak request jika role user sama sekali tidak punya permission
+// untuk aksi tersebut. Kepemilikan resource (":own") tetap dicek di usecase.
+func Authorize(action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, err := auth.PrincipalFrom(c)
+		if err != nil {
+			return err
+		}
+		if !policy.Allowed(principal.Role, action) {
+			return domain.Forbidden(domain.CodeForbidden, "forbidden: tidak memiliki akses "+action)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Timeout memberi deadline pada context setiap request. Handler meneruskan
+// c.UserContext() sampai ke query, sehingga query yang melewati deadline
+// dibatalkan driver. Fasthttp tidak membatalkan context saat client
+// memutus koneksi, jadi deadline inilah batas atas lama sebuah request.
+func Timeout(d time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), d)
+		defer cancel()
+
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package payment
+
+import (
+	"fmt"
+	"main-service/internal/domain"
+	"strings"
+)
+
+const FakeProviderName = "fake"
+
+// FakeProvider adalah payment gateway lokal tanpa jaringan. Pembayaran
+// disimulasikan lewat endpoint /invoices/:id/simulate-payment sehingga alur
+// enroll -> invoice -> paid bisa diuji end-to-end di mesin developer.
+type FakeProvider struct {
+	baseURL string
+}
+
+func NewFakeProvider(baseURL string) *FakeProvider {
+	return &FakeProvider{baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (p *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (p *FakeProvider) CreateCharge(inv *domain.Invoice) (*Charge, error) {
+	return &Charge{
+		Reference:  "FAKE-" + inv.Number,
+		PaymentURL: fmt.Sprintf("%s/api/v1/invoices/%d/simulate-payment", p.baseURL, inv.ID),
+		ExpiresAt:  inv.ExpiresAt,
+	}, nil
+}
+
+func (p *FakeProvider) Refund(inv *domain.Invoice) error {
+	return nil
+}
@@ -0,0 +1,32 @@
+package payment
+
+import (
+	"fmt"
+	"main-service/internal/domain"
+	"time"
+)
+
+// Charge adalah hasil pembuatan tagihan di payment gateway
+type Charge struct {
+	Reference  string
+	PaymentURL string
+	ExpiresAt  time.Time
+}
+
+// Provider membungkus payment gateway sehingga alur invoice tidak bergantung
+// pada vendor tertentu (Midtrans, Xendit, atau fake lokal).
+type Provider interface {
+	Name() string
+	CreateCharge(inv *domain.Invoice) (*Charge, error)
+	Refund(inv *domain.Invoice) error
+}
+
+// NewProvider memilih implementasi provider berdasarkan nama di konfigurasi
+func NewProvider(name, baseURL string) (Provider, error) {
+	switch name {
+	case "", "fake":
+		return NewFakeProvider(baseURL), nil
+	default:
+		return nil, fmt.Errorf("payment provider %q belum didukung", name)
+	}
+}
@@ -0,0 +1,123 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"main-service/internal/domain"
+)
+
+// Notification adalah isi webhook yang sudah dinormalisasi dari format
+// Midtrans (order_id, transaction_status, gross_amount) maupun Xendit
+// (external_id, status, amount)
+type Notification struct {
+	EventID       string
+	InvoiceNumber string
+	GatewayStatus string
+	InvoiceStatus string // status invoice hasil mapping, kosong jika tidak relevan
+	Amount        int64
+}
+
+// VerifySignature membandingkan header signature dengan HMAC-SHA256 body mentah
+func VerifySignature(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(strings.TrimSpace(signature))))
+}
+
+// ParseNotification membaca payload webhook. Jika gateway tidak mengirim id
+// event, sha256 dari body dipakai sebagai kunci idempotensi.
+func ParseNotification(body []byte) (*Notification, error) {
+	var raw struct {
+		EventID           string          `json:"event_id"`
+		TransactionID     string          `json:"transaction_id"`
+		ID                string          `json:"id"`
+		OrderID           string          `json:"order_id"`
+		ExternalID        string          `json:"external_id"`
+		TransactionStatus string          `json:"transaction_status"`
+		Status            string          `json:"status"`
+		GrossAmount       json.RawMessage `json:"gross_amount"`
+		Amount            json.RawMessage `json:"amount"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, errors.New("payload webhook bukan JSON yang valid")
+	}
+
+	n := &Notification{
+		EventID:       firstNonEmpty(raw.EventID, raw.TransactionID, raw.ID),
+		InvoiceNumber: firstNonEmpty(raw.OrderID, raw.ExternalID),
+		GatewayStatus: strings.ToLower(firstNonEmpty(raw.TransactionStatus, raw.Status)),
+	}
+	if n.InvoiceNumber == "" {
+		return nil, errors.New("payload webhook tidak memiliki nomor invoice")
+	}
+
+	if n.EventID == "" {
+		sum := sha256.Sum256(body)
+		n.EventID = hex.EncodeToString(sum[:])
+	} else {
+		// event yang sama bisa dikirim ulang dengan status berbeda
+		n.EventID = n.EventID + ":" + n.GatewayStatus
+	}
+
+	amount := raw.GrossAmount
+	if len(amount) == 0 {
+		amount = raw.Amount
+	}
+	if len(amount) > 0 {
+		value, err := parseAmount(amount)
+		if err != nil {
+			return nil, err
+		}
+		n.Amount = value
+	}
+
+	n.InvoiceStatus = mapGatewayStatus(n.GatewayStatus)
+	return n, nil
+}
+
+func mapGatewayStatus(status string) string {
+	switch status {
+	case "settlement", "capture", "paid", "succeeded", "settled":
+		return domain.InvoiceStatusPaid
+	case "expire", "expired":
+		return domain.InvoiceStatusExpired
+	case "cancel", "deny", "failure", "failed", "voided":
+		return domain.InvoiceStatusCancelled
+	case "refund", "partial_refund", "refunded":
+		return domain.InvoiceStatusRefunded
+	default:
+		return ""
+	}
+}
+
+// parseAmount menerima angka JSON ataupun string seperti "150000.00"
+func parseAmount(raw json.RawMessage) (int64, error) {
+	text := strings.Trim(string(raw), `"`)
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, errors.New("nominal pembayaran tidak valid")
+	}
+	return int64(math.Round(value)), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package policy
+
+// Daftar aksi dengan format "<resource>:<aksi>"
+const (
+	FeatureList   = "feature:list"
+	FeatureView   = "feature:view"
+	FeatureCreate = "feature:create"
+	FeatureUpdate = "feature:update"
+	FeatureDelete = "feature:delete"
+	FeatureTrash  = "feature:trash" // lihat & pulihkan dari trash
+
+	MatpelList   = "matpel:list"
+	MatpelView   = "matpel:view"
+	MatpelCreate = "matpel:create"
+	MatpelUpdate = "matpel:update"
+	MatpelDelete = "matpel:delete"
+	MatpelTrash  = "matpel:trash"
+
+	BimbelView     = "bimbel:view"
+	BimbelCreate   = "bimbel:create"
+	BimbelUpdate   = "bimbel:update"
+	BimbelDelete   = "bimbel:delete"
+	BimbelTrash    = "bimbel:trash"
+	BimbelModerate = "bimbel:moderate" // setujui / tolak bimbel yang diajukan untuk review
+
+	EnrollmentCreate = "enrollment:create"
+	EnrollmentCancel = "enrollment:cancel"
+	EnrollmentList   = "enrollment:list"
+	EnrollmentManage = "enrollment:manage" // daftar peserta per bimbel & menandai selesai
+
+	SessionManage = "session:manage"
+
+	AttendanceCheckin = "attendance:checkin"
+	AttendanceManage  = "attendance:manage" // kode check-in, roster, rekap
+
+	InvoiceList     = "invoice:list"
+	InvoiceView     = "invoice:view"
+	InvoiceCancel   = "invoice:cancel"
+	InvoiceRefund   = "invoice:refund"
+	InvoiceSimulate = "invoice:simulate"
+
+	PaymentNotificationList   = "payment_notification:list"
+	PaymentNotificationReplay = "payment_notification:replay"
+
+	ReviewCreate     = "review:create"
+	ReviewUpdate     = "review:update"
+	ReviewReply      = "review:reply"
+	ReviewModerate   = "review:moderate"
+	ReviewViewHidden = "review:view_hidden"
+
+	TutorProfileManage = "tutor_profile:manage"
+	TutorVerify        = "tutor:verify"
+
+	PesertaProfileManage = "peserta_profile:manage"
+	PesertaView          = "peserta:view"
+	PesertaViewLimited   = "peserta:view_limited"
+
+	UserManage = "user:manage"
+
+	SystemMonitor = "system:monitor" // statistik connection pool, dsb.
+)
+
+// Actions adalah seluruh aksi yang dikenal; grant untuk aksi di luar daftar ini akan panic saat start
+var Actions = []string{
+	FeatureList, FeatureView, FeatureCreate, FeatureUpdate, FeatureDelete, FeatureTrash,
+	MatpelList, MatpelView, MatpelCreate, MatpelUpdate, MatpelDelete, MatpelTrash,
+	BimbelView, BimbelCreate, BimbelUpdate, BimbelDelete, BimbelTrash, BimbelModerate,
+	EnrollmentCreate, EnrollmentCancel, EnrollmentList, EnrollmentManage,
+	SessionManage,
+	AttendanceCheckin, AttendanceManage,
+	InvoiceList, InvoiceView, InvoiceCancel, InvoiceRefund, InvoiceSimulate,
+	PaymentNotificationList, PaymentNotificationReplay,
+	ReviewCreate, ReviewUpdate, ReviewReply, ReviewModerate, ReviewViewHidden,
+	TutorProfileManage, TutorVerify,
+	PesertaProfileManage, PesertaView, PesertaViewLimited,
+	UserManage,
+	SystemMonitor,
+}
@@ -0,0 +1,139 @@
+// Package policy adalah satu-satunya tempat aturan otorisasi: role apa boleh
+// melakukan aksi apa. Semua yang tidak tercantum di tabel grants ditolak.
+package policy
+
+import (
+	"main-service/internal/domain"
+	"strings"
+)
+
+var ErrForbidden = domain.Forbidden(domain.CodeForbidden, "forbidden")
+
+// Actor adalah user yang sedang melakukan aksi
+type Actor struct {
+	UserID    uint64
+	Role      string
+	TutorID   uint64
+	PesertaID uint64
+}
+
+// Resource menyebut pemilik data yang diakses. Dipakai untuk permission
+// berakhiran ":own"; field bernilai 0 berarti tidak dimiliki siapa pun.
+type Resource struct {
+	UserID    uint64
+	TutorID   uint64
+	PesertaID uint64
+}
+
+// OwnedBy mengecek apakah resource dimiliki actor
+func (r *Resource) OwnedBy(a Actor) bool {
+	if r == nil {
+		return false
+	}
+	return (r.UserID != 0 && r.UserID == a.UserID) ||
+		(r.TutorID != 0 && r.TutorID == a.TutorID) ||
+		(r.PesertaID != 0 && r.PesertaID == a.PesertaID)
+}
+
+// ownSuffix menandai permission yang hanya berlaku untuk resource milik sendiri
+const ownSuffix = ":own"
+
+func own(action string) string {
+	return action + ownSuffix
+}
+
+// Authorize mengizinkan aksi jika role punya permission penuh, atau punya
+// permission ":own" dan resource dimiliki actor. Selain itu ErrForbidden.
+func Authorize(actor Actor, action string, res *Resource) error {
+	perms, ok := grants[actor.Role]
+	if !ok {
+		return ErrForbidden
+	}
+	if perms[action] {
+		return nil
+	}
+	if perms[own(action)] && res.OwnedBy(actor) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// Allowed dipakai untuk pengecekan di level route: apakah role ini mungkin
+// boleh melakukan aksi (penuh atau ":own"). Kepemilikan dicek di usecase.
+func Allowed(role string, action string) bool {
+	perms := grants[role]
+	return perms[action] || perms[own(action)]
+}
+
+// Permissions mengembalikan daftar permission sebuah role, mis. untuk ditampilkan ke client
+func Permissions(role string) []string {
+	result := []string{}
+	for _, action := range Actions {
+		if grants[role][action] {
+			result = append(result, action)
+		} else if grants[role][own(action)] {
+			result = append(result, own(action))
+		}
+	}
+	return result
+}
+
+func grant(actions ...string) map[string]bool {
+	m := make(map[string]bool, len(actions))
+	for _, a := range actions {
+		base := strings.TrimSuffix(a, ownSuffix)
+		if !isKnownAction(base) {
+			panic("policy: aksi tidak dikenal " + a)
+		}
+		m[a] = true
+	}
+	return m
+}
+
+func isKnownAction(action string) bool {
+	for _, a := range Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+var grants = map[string]map[string]bool{
+	domain.RoleAdmin: grant(
+		FeatureList, FeatureView, FeatureCreate, FeatureUpdate, FeatureDelete, FeatureTrash,
+		MatpelList, MatpelView, MatpelCreate, MatpelUpdate, MatpelDelete, MatpelTrash,
+		BimbelView, BimbelCreate, BimbelUpdate, BimbelDelete, BimbelTrash, BimbelModerate,
+		EnrollmentManage,
+		SessionManage,
+		AttendanceManage,
+		InvoiceList, InvoiceView, InvoiceCancel, InvoiceRefund, InvoiceSimulate,
+		PaymentNotificationList, PaymentNotificationReplay,
+		ReviewModerate, ReviewViewHidden,
+		TutorVerify,
+		PesertaView,
+		UserManage,
+		SystemMonitor,
+	),
+	domain.RoleTutor: grant(
+		FeatureList, FeatureView,
+		MatpelList, MatpelView,
+		own(BimbelView), own(BimbelCreate), own(BimbelUpdate), own(BimbelDelete),
+		own(EnrollmentManage),
+		own(SessionManage),
+		own(AttendanceManage),
+		own(ReviewReply),
+		own(TutorProfileManage),
+		own(PesertaViewLimited),
+	),
+	domain.RolePeserta: grant(
+		FeatureList, FeatureView,
+		MatpelList, MatpelView,
+		BimbelView,
+		EnrollmentCreate, own(EnrollmentCancel), own(EnrollmentList),
+		AttendanceCheckin,
+		own(InvoiceList), own(InvoiceView), own(InvoiceCancel), own(InvoiceSimulate),
+		ReviewCreate, own(ReviewUpdate),
+		own(PesertaProfileManage),
+	),
+}
@@ -0,0 +1,202 @@
+package policy
+
+import (
+	"errors"
+	"main-service/internal/domain"
+	"strings"
+	"testing"
+)
+
+// access adalah hak sebuah role atas satu aksi menurut spesifikasi
+type access int
+
+const (
+	none access = iota
+	ownOnly
+	full
+)
+
+var allRoles = []string{domain.RoleAdmin, domain.RoleTutor, domain.RolePeserta}
+
+// spec adalah tabel hak akses yang diharapkan, ditulis terpisah dari grants
+// supaya perubahan permission selalu disengaja. Role yang tidak disebut = none.
+var spec = map[string]map[string]access{
+	FeatureList:   {domain.RoleAdmin: full, domain.RoleTutor: full, domain.RolePeserta: full},
+	FeatureView:   {domain.RoleAdmin: full, domain.RoleTutor: full, domain.RolePeserta: full},
+	FeatureCreate: {domain.RoleAdmin: full},
+	FeatureUpdate: {domain.RoleAdmin: full},
+	FeatureDelete: {domain.RoleAdmin: full},
+	FeatureTrash:  {domain.RoleAdmin: full},
+
+	MatpelList:   {domain.RoleAdmin: full, domain.RoleTutor: full, domain.RolePeserta: full},
+	MatpelView:   {domain.RoleAdmin: full, domain.RoleTutor: full, domain.RolePeserta: full},
+	MatpelCreate: {domain.RoleAdmin: full},
+	MatpelUpdate: {domain.RoleAdmin: full},
+	MatpelDelete: {domain.RoleAdmin: full},
+	MatpelTrash:  {domain.RoleAdmin: full},
+
+	BimbelView:     {domain.RoleAdmin: full, domain.RoleTutor: ownOnly, domain.RolePeserta: full},
+	BimbelCreate:   {domain.RoleAdmin: full, domain.RoleTutor: ownOnly},
+	BimbelUpdate:   {domain.RoleAdmin: full, domain.RoleTutor: ownOnly},
+	BimbelDelete:   {domain.RoleAdmin: full, domain.RoleTutor: ownOnly},
+	BimbelTrash:    {domain.RoleAdmin: full},
+	BimbelModerate: {domain.RoleAdmin: full},
+
+	EnrollmentCreate: {domain.RolePeserta: full},
+	EnrollmentCancel: {domain.RolePeserta: ownOnly},
+	EnrollmentList:   {domain.RolePeserta: ownOnly},
+	EnrollmentManage: {domain.RoleAdmin: full, domain.RoleTutor: ownOnly},
+
+	SessionManage: {domain.RoleAdmin: full, domain.RoleTutor: ownOnly},
+
+	AttendanceCheckin: {domain.RolePeserta: full},
+	AttendanceManage:  {domain.RoleAdmin: full, domain.RoleTutor: ownOnly},
+
+	InvoiceList:     {domain.RoleAdmin: full, domain.RolePeserta: ownOnly},
+	InvoiceView:     {domain.RoleAdmin: full, domain.RolePeserta: ownOnly},
+	InvoiceCancel:   {domain.RoleAdmin: full, domain.RolePeserta: ownOnly},
+	InvoiceRefund:   {domain.RoleAdmin: full},
+	InvoiceSimulate: {domain.RoleAdmin: full, domain.RolePeserta: ownOnly},
+
+	PaymentNotificationList:   {domain.RoleAdmin: full},
+	PaymentNotificationReplay: {domain.RoleAdmin: full},
+
+	ReviewCreate:     {domain.RolePeserta: full},
+	ReviewUpdate:     {domain.RolePeserta: ownOnly},
+	ReviewReply:      {domain.RoleTutor: ownOnly},
+	ReviewModerate:   {domain.RoleAdmin: full},
+	ReviewViewHidden: {domain.RoleAdmin: full},
+
+	TutorProfileManage: {domain.RoleTutor: ownOnly},
+	TutorVerify:        {domain.RoleAdmin: full},
+
+	PesertaProfileManage: {domain.RolePeserta: ownOnly},
+	PesertaView:          {domain.RoleAdmin: full},
+	PesertaViewLimited:   {domain.RoleTutor: ownOnly},
+
+	UserManage: {domain.RoleAdmin: full},
+
+	SystemMonitor: {domain.RoleAdmin: full},
+}
+
+func TestSpecCoversEveryAction(t *testing.T) {
+	for _, action := range Actions {
+		if _, ok := spec[action]; !ok {
+			t.Errorf("aksi %s belum ada di spec", action)
+		}
+	}
+	if len(spec) != len(Actions) {
+		t.Errorf("spec punya %d aksi, Actions punya %d", len(spec), len(Actions))
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	// Actor memiliki ketiga jenis id; resource milik sendiri dicoba lewat
+	// masing-masing field agar OwnedBy teruji untuk user, tutor, dan peserta
+	owned := map[string]*Resource{
+		"own user":    {UserID: 1},
+		"own tutor":   {TutorID: 10},
+		"own peserta": {PesertaID: 100},
+	}
+	notOwned := map[string]*Resource{
+		"other":        {UserID: 2, TutorID: 20, PesertaID: 200},
+		"unowned":      {},
+		"nil resource": nil,
+	}
+
+	for _, action := range Actions {
+		for _, role := range allRoles {
+			want := spec[action][role]
+			actor := Actor{UserID: 1, Role: role, TutorID: 10, PesertaID: 100}
+
+			for name, res := range owned {
+				t.Run(action+"/"+role+"/"+name, func(t *testing.T) {
+					checkAuthorize(t, actor, action, res, want != none)
+				})
+			}
+			for name, res := range notOwned {
+				t.Run(action+"/"+role+"/"+name, func(t *testing.T) {
+					checkAuthorize(t, actor, action, res, want == full)
+				})
+			}
+		}
+	}
+}
+
+func checkAuthorize(t *testing.T, actor Actor, action string, res *Resource, wantAllowed bool) {
+	t.Helper()
+	err := Authorize(actor, action, res)
+	if wantAllowed && err != nil {
+		t.Fatalf("Authorize = %v, want nil", err)
+	}
+	if !wantAllowed && !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Authorize = %v, want ErrForbidden", err)
+	}
+}
+
+func TestAuthorizeDeniesByDefault(t *testing.T) {
+	for _, role := range []string{"", "guest", "ADMIN", "superadmin"} {
+		for _, action := range Actions {
+			if err := Authorize(Actor{UserID: 1, Role: role}, action, &Resource{UserID: 1}); !errors.Is(err, ErrForbidden) {
+				t.Errorf("role %q aksi %s: err = %v, want ErrForbidden", role, action, err)
+			}
+		}
+	}
+
+	if err := Authorize(Actor{Role: domain.RoleAdmin}, "bimbel:fly", nil); !errors.Is(err, ErrForbidden) {
+		t.Errorf("aksi tidak dikenal: err = %v, want ErrForbidden", err)
+	}
+}
+
+// Actor tanpa profil tutor (TutorID 0) tidak boleh dianggap pemilik
+// resource yang juga tidak punya tutor
+func TestOwnershipIgnoresZeroIDs(t *testing.T) {
+	actor := Actor{UserID: 1, Role: domain.RoleTutor}
+	if err := Authorize(actor, BimbelUpdate, &Resource{TutorID: 0}); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("err = %v, want ErrForbidden", err)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	for _, action := range Actions {
+		for _, role := range allRoles {
+			want := spec[action][role] != none
+			if got := Allowed(role, action); got != want {
+				t.Errorf("Allowed(%s, %s) = %v, want %v", role, action, got, want)
+			}
+		}
+	}
+	if Allowed("guest", FeatureList) {
+		t.Error("role tidak dikenal tidak boleh punya akses")
+	}
+}
+
+func TestPermissions(t *testing.T) {
+	for _, role := range allRoles {
+		var want []string
+		for _, action := range Actions {
+			switch spec[action][role] {
+			case full:
+				want = append(want, action)
+			case ownOnly:
+				want = append(want, own(action))
+			}
+		}
+		got := Permissions(role)
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("Permissions(%s) =\n  %v\nwant\n  %v", role, got, want)
+		}
+	}
+	if got := Permissions("guest"); len(got) != 0 {
+		t.Errorf("Permissions(guest) = %v, want kosong", got)
+	}
+}
+
+func TestGrantPanicsOnUnknownAction(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("grant aksi tidak dikenal harus panic")
+		}
+	}()
+	grant("bimbel:fly")
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"time"
+)
+
+type AttendanceRepository interface {
+	SaveCheckinCode(ctx context.Context, sessionID uint64, codeHash string, expiresAt time.Time) error
+	FindCheckinCode(ctx context.Context, sessionID uint64) (codeHash string, expiresAt time.Time, err error)
+	LockCheckinCode(ctx context.Context, sessionID uint64) (codeHash string, expiresAt time.Time, err error)
+	CheckinFailures(ctx context.Context, sessionID, pesertaID uint64) (int, error)
+	RecordCheckinFailure(ctx context.Context, sessionID, pesertaID uint64) (int, error)
+	Upsert(ctx context.Context, a *domain.Attendance) error
+	FindRoster(ctx context.Context, bimbelID, sessionID uint64) ([]domain.AttendanceRosterItem, error)
+	FindByPeserta(ctx context.Context, bimbelID, pesertaID uint64) ([]domain.Attendance, error)
+	Summarize(ctx context.Context, bimbelID uint64, until time.Time) ([]domain.AttendanceSummary, error)
+	CountSessions(ctx context.Context, bimbelID uint64, until time.Time) (int, error)
+}
+
+type attendanceRepository struct {
+	db db.Conn
+}
+
+func NewAttendanceRepository(db db.Conn) AttendanceRepository {
+	return &attendanceRepository{db}
+}
+
+// SaveCheckinCode mengganti kode absensi sesi; kode lama otomatis tidak
+// berlaku dan kunci percobaan salah milik peserta ikut direset
+func (r *attendanceRepository) SaveCheckinCode(ctx context.Context, sessionID uint64, codeHash string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM session_checkin_codes WHERE session_id = ?`, sessionID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM session_checkin_attempts WHERE session_id = ?`, sessionID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO session_checkin_codes (session_id, code_hash, expires_at, created_at)
+		VALUES (?, ?, ?, NOW())
+	`, sessionID, codeHash, expiresAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *attendanceRepository) FindCheckinCode(ctx context.Context, sessionID uint64) (string, time.Time, error) {
+	var (
+		codeHash  string
+		expiresAt time.Time
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT code_hash, expires_at FROM session_checkin_codes WHERE session_id = ?
+	`, sessionID).Scan(&codeHash, &expiresAt)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, domain.ErrCheckinCodeInvalid
+	}
+	return codeHash, expiresAt, err
+}
+
+// LockCheckinCode sama seperti FindCheckinCode tetapi mengunci baris kode
+// (FOR UPDATE) sehingga check-in ke sesi yang sama diproses bergiliran
+func (r *attendanceRepository) LockCheckinCode(ctx context.Context, sessionID uint64) (string, time.Time, error) {
+	var (
+		codeHash  string
+		expiresAt time.Time
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT code_hash, expires_at FROM session_checkin_codes WHERE session_id = ? FOR UPDATE
+	`, sessionID).Scan(&codeHash, &expiresAt)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, domain.ErrCheckinCodeInvalid
+	}
+	return codeHash, expiresAt, err
+}
+
+func (r *attendanceRepository) CheckinFailures(ctx context.Context, sessionID, pesertaID uint64) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT failed_count FROM session_checkin_attempts WHERE session_id = ? AND peserta_id = ?
+	`, sessionID, pesertaID).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return count, err
+}
+
+// RecordCheckinFailure menambah hitungan kode salah dan mengembalikan
+// jumlah terbarunya. Dipanggil saat baris kode sesi sudah dikunci.
+func (r *attendanceRepository) RecordCheckinFailure(ctx context.Context, sessionID, pesertaID uint64) (int, error) {
+	count, err := r.CheckinFailures(ctx, sessionID, pesertaID)
+	if err != nil {
+		return 0, err
+	}
+
+	if count == 0 {
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO session_checkin_attempts (session_id, peserta_id, failed_count, updated_at)
+			VALUES (?, ?, 1, NOW())
+		`, sessionID, pesertaID)
+	} else {
+		_, err = r.db.ExecContext(ctx, `
+			UPDATE session_checkin_attempts SET failed_count = failed_count + 1, updated_at = NOW()
+			WHERE session_id = ? AND peserta_id = ?
+		`, sessionID, pesertaID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return count + 1, nil
+}
+
+func (r *attendanceRepository) Upsert(ctx context.Context, a *domain.Attendance) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id uint64
+	err = tx.QueryRowContext(ctx, `
+		SELECT id FROM attendances WHERE session_id = ? AND peserta_id = ? FOR UPDATE
+	`, a.SessionID, a.PesertaID).Scan(&id)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if err == sql.ErrNoRows {
+		a.ID, err = tx.InsertIDContext(ctx, `
+			INSERT INTO attendances (session_id, peserta_id, status, note, checked_in_at, marked_by, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())
+		`, a.SessionID, a.PesertaID, a.Status, a.Note, a.CheckedInAt, a.MarkedBy)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE attendances
+			SET status = ?, note = ?, checked_in_at = COALESCE(?, checked_in_at), marked_by = ?, updated_at = NOW()
+			WHERE id = ?
+		`, a.Status, a.Note, a.CheckedInAt, a.MarkedBy, id)
+		if err != nil {
+			return err
+		}
+		a.ID = id
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// FindRoster mengembalikan semua peserta aktif bimbel beserta status
+// kehadirannya pada sesi tertentu
+func (r *attendanceRepository) FindRoster(ctx context.Context, bimbelID, sessionID uint64) ([]domain.AttendanceRosterItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT e.peserta_id, COALESCE(u.name, ''), COALESCE(a.status, ''), COALESCE(a.note, ''), a.checked_in_at
+		FROM enrollments e
+		LEFT JOIN users u ON u.peserta_id = e.peserta_id
+		LEFT JOIN attendances a ON a.session_id = ? AND a.peserta_id = e.peserta_id
+		WHERE e.bimbel_id = ? AND e.status = ?
+		ORDER BY u.name ASC
+	`, sessionID, bimbelID, domain.EnrollmentStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.AttendanceRosterItem{}
+	for rows.Next() {
+		var (
+			item        domain.AttendanceRosterItem
+			checkedInAt sql.NullTime
+		)
+		if err := rows.Scan(&item.PesertaID, &item.PesertaName, &item.Status, &item.Note, &checkedInAt); err != nil {
+			return nil, err
+		}
+		if checkedInAt.Valid {
+			item.CheckedInAt = &checkedInAt.Time
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
+
+func (r *attendanceRepository) FindByPeserta(ctx context.Context, bimbelID, pesertaID uint64) ([]domain.Attendance, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, a.session_id, a.peserta_id, a.status, a.note, a.checked_in_at, a.marked_by, a.created_at, a.updated_at
+		FROM attendances a
+		JOIN bimbel_sessions s ON s.id = a.session_id
+		WHERE s.bimbel_id = ? AND a.peserta_id = ? AND s.deleted_at IS NULL
+		ORDER BY s.start_at ASC
+	`, bimbelID, pesertaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.Attendance{}
+	for rows.Next() {
+		var (
+			a           domain.Attendance
+			checkedInAt sql.NullTime
+			markedBy    sql.NullInt64
+		)
+		if err := rows.Scan(&a.ID, &a.SessionID, &a.PesertaID, &a.Status, &a.Note,
+			&checkedInAt, &markedBy, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if checkedInAt.Valid {
+			a.CheckedInAt = &checkedInAt.Time
+		}
+		if markedBy.Valid {
+			id := uint64(markedBy.Int64)
+			a.MarkedBy = &id
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+// Summarize menghitung rekap kehadiran per peserta aktif untuk sesi yang
+// sudah dimulai sebelum until dan setelah peserta terdaftar
+func (r *attendanceRepository) Summarize(ctx context.Context, bimbelID uint64, until time.Time) ([]domain.AttendanceSummary, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT e.peserta_id, COALESCE(MAX(u.name), ''),
+			COUNT(s.id),
+			COALESCE(SUM(CASE WHEN a.status = ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN a.status = ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN a.status = ? THEN 1 ELSE 0 END), 0)
+		FROM enrollments e
+		LEFT JOIN users u ON u.peserta_id = e.peserta_id
+		LEFT JOIN bimbel_sessions s ON s.bimbel_id = e.bimbel_id AND s.deleted_at IS NULL
+			AND s.start_at <= ? AND s.end_at >= e.enrolled_at
+		LEFT JOIN attendances a ON a.session_id = s.id AND a.peserta_id = e.peserta_id
+		WHERE e.bimbel_id = ? AND e.status = ?
+		GROUP BY e.peserta_id
+		ORDER BY e.peserta_id ASC
+	`, domain.AttendancePresent, domain.AttendanceLate, domain.AttendanceExcused,
+		until.UTC(), bimbelID, domain.EnrollmentStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.AttendanceSummary{}
+	for rows.Next() {
+		var s domain.AttendanceSummary
+		if err := rows.Scan(&s.PesertaID, &s.PesertaName, &s.TotalSessions, &s.Present, &s.Late, &s.Excused); err != nil {
+			return nil, err
+		}
+		s.Absent = s.TotalSessions - s.Present - s.Late - s.Excused
+		if s.TotalSessions > 0 {
+			s.Rate = float64(s.Present+s.Late) / float64(s.TotalSessions)
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+func (r *attendanceRepository) CountSessions(ctx context.Context, bimbelID uint64, until time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM bimbel_sessions
+		WHERE bimbel_id = ? AND deleted_at IS NULL AND start_at <= ?
+	`, bimbelID, until.UTC()).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"time"
+)
+
+type AuthSessionRepository interface {
+	Create(ctx context.Context, s *domain.AuthSession, tokenHash string, expiresAt time.Time) error
+	Rotate(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (*domain.AuthSession, error)
+	Revoke(ctx context.Context, id uint64, userID uint64, reason string) error
+	RevokeAllByUser(ctx context.Context, userID uint64, reason string) (int64, error)
+	IsActive(ctx context.Context, id uint64) (bool, error)
+}
+
+type authSessionRepository struct {
+	db db.Conn
+}
+
+func NewAuthSessionRepository(db db.Conn) AuthSessionRepository {
+	return &authSessionRepository{db}
+}
+
+// Create membuat sesi login baru beserta refresh token pertamanya
+func (r *authSessionRepository) Create(ctx context.Context, s *domain.AuthSession, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	id, err := tx.InsertIDContext(ctx, `
+		INSERT INTO auth_sessions (user_id, user_agent, ip_address, created_at, last_used_at)
+		VALUES (?, ?, ?, NOW(), NOW())
+	`, s.UserID, s.UserAgent, s.IPAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (session_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, NOW())
+	`, id, tokenHash, expiresAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.ID = uint64(id)
+	s.CreatedAt = time.Now()
+	s.LastUsedAt = s.CreatedAt
+	return nil
+}
+
+// Rotate menukar refresh token lama dengan yang baru. Token yang sudah pernah
+// ditukar dianggap bocor: seluruh sesi (keluarga token) langsung dicabut.
+func (r *authSessionRepository) Rotate(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (*domain.AuthSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		tokenID   uint64
+		tokenExp  time.Time
+		usedAt    sql.NullTime
+		s         domain.AuthSession
+		revokedAt sql.NullTime
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT rt.id, rt.expires_at, rt.used_at, s.id, s.user_id, s.user_agent, s.ip_address,
+			s.created_at, s.last_used_at, s.revoked_at
+		FROM refresh_tokens rt
+		JOIN auth_sessions s ON s.id = rt.session_id
+		WHERE rt.token_hash = ?
+		FOR UPDATE
+	`, oldHash).Scan(&tokenID, &tokenExp, &usedAt, &s.ID, &s.UserID, &s.UserAgent, &s.IPAddress,
+		&s.CreatedAt, &s.LastUsedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		return nil, domain.ErrSessionRevoked
+	}
+
+	if usedAt.Valid {
+		if err := revokeSession(ctx, tx, s.ID, domain.SessionRevokedReuse); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(tokenExp) {
+		return nil, domain.ErrRefreshTokenExpired
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE refresh_tokens SET used_at = NOW() WHERE id = ?`, tokenID); err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (session_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, NOW())
+	`, s.ID, newHash, expiresAt.UTC())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE auth_sessions SET last_used_at = NOW() WHERE id = ?`, s.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.LastUsedAt = time.Now()
+	return &s, nil
+}
+
+func revokeSession(ctx context.Context, tx *db.Tx, id uint64, reason string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE auth_sessions SET revoked_at = NOW(), revoke_reason = ?
+		WHERE id = ? AND revoked_at IS NULL
+	`, reason, id)
+	return err
+}
+
+func (r *authSessionRepository) Revoke(ctx context.Context, id uint64, userID uint64, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE auth_sessions SET revoked_at = NOW(), revoke_reason = ?
+		WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+	`, reason, id, userID)
+	return err
+}
+
+func (r *authSessionRepository) RevokeAllByUser(ctx context.Context, userID uint64, reason string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE auth_sessions SET revoked_at = NOW(), revoke_reason = ?
+		WHERE user_id = ? AND revoked_at IS NULL
+	`, reason, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *authSessionRepository) IsActive(ctx context.Context, id uint64) (bool, error) {
+	var active bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM auth_sessions WHERE id = ? AND revoked_at IS NULL)
+	`, id).Scan(&active)
+	return active, err
+}
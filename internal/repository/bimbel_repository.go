@@ -1,31 +1,109 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"errors"
+	"encoding/json"
+	"fmt"
+	"main-service/internal/db"
 	"main-service/internal/domain"
+	"strings"
 	"time"
 )
 
 type BimbelRepository interface {
-	Create(b *domain.Bimbel) error
-	Update(b *domain.Bimbel) error
-	Delete(id uint64) error
-	FindByID(id uint64) (*domain.Bimbel, error)
-	ExistsDuplicate(name string, featureID, subjectID uint64, excludeID *uint64) (bool, error)
-	FindByTutor(id uint64) ([]domain.Bimbel, error)
-	ExistsByNameAndTutor(name string, tutorID uint64) (bool, error)
+	Create(ctx context.Context, b *domain.Bimbel) error
+	Update(ctx context.Context, b *domain.Bimbel) error
+	Delete(ctx context.Context, id uint64) error
+	FindByID(ctx context.Context, id uint64) (*domain.Bimbel, error)
+	ExistsDuplicate(ctx context.Context, name string, featureID, subjectID uint64, excludeID *uint64) (bool, error)
+	FindByTutor(ctx context.Context, id uint64) ([]domain.Bimbel, error)
+	ExistsByNameAndTutor(ctx context.Context, name string, tutorID uint64) (bool, error)
+	List(ctx context.Context, filter domain.BimbelFilter) ([]domain.Bimbel, int64, error)
+	ThumbnailKeysInUse(ctx context.Context) (map[string]bool, error)
+	ExistsActiveByFeature(ctx context.Context, featureID uint64) (bool, error)
+	ExistsActiveBySubject(ctx context.Context, subjectID uint64) (bool, error)
+	ListDeleted(ctx context.Context) ([]domain.Bimbel, error)
+	LockDeletedByID(ctx context.Context, id uint64) (*domain.Bimbel, error)
+	Restore(ctx context.Context, id uint64) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	LockByID(ctx context.Context, id uint64) (*domain.Bimbel, error)
+	UpdateStatus(ctx context.Context, id uint64, from, to string) error
+	RecordStatusChange(ctx context.Context, change *domain.BimbelStatusChange) error
+	StatusHistory(ctx context.Context, id uint64) ([]domain.BimbelStatusChange, error)
+	LastStatusChange(ctx context.Context, id uint64) (*domain.BimbelStatusChange, error)
+	FindFinishedPublished(ctx context.Context, now time.Time) ([]uint64, error)
 }
 
 type bimbelRepository struct {
-	db *sql.DB
+	db db.Conn
 }
 
-func NewBimbelRepository(db *sql.DB) BimbelRepository {
+func NewBimbelRepository(db db.Conn) BimbelRepository {
 	return &bimbelRepository{db}
 }
 
-func (r *bimbelRepository) ExistsDuplicate(name string, featureID, subjectID uint64, excludeID *uint64) (bool, error) {
+const bimbelColumns = `id, tutor_id, feature_id, subject_id, name, limit_peserta, status, thumbnail, thumbnail_variants, deskripsi, harga,
+		rating_sum, rating_count, created_at, updated_at, deleted_at`
+
+func scanBimbel(row rowScanner) (*domain.Bimbel, error) {
+	var (
+		b         domain.Bimbel
+		ratingSum int64
+		variants  sql.NullString
+	)
+	err := row.Scan(&b.ID, &b.TutorID, &b.FeatureID, &b.SubjectID, &b.Name, &b.LimitPeserta,
+		&b.Status, &b.Thumbnail, &variants, &b.Deskripsi, &b.Harga, &ratingSum, &b.RatingCount, &b.CreatedAt, &b.UpdatedAt, &b.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	if b.ThumbnailVariants, err = decodeImageVariants(variants); err != nil {
+		return nil, err
+	}
+	b.RatingAvg = domain.RatingAverage(ratingSum, b.RatingCount)
+	return &b, nil
+}
+
+// imageVariantRow adalah bentuk JSON varian di kolom thumbnail_variants.
+// Dipisah dari domain.ImageVariant karena key tidak ikut di response API.
+type imageVariantRow struct {
+	Key    string `json:"key"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"`
+}
+
+func encodeImageVariants(variants []domain.ImageVariant) (sql.NullString, error) {
+	if len(variants) == 0 {
+		return sql.NullString{}, nil
+	}
+	rows := make([]imageVariantRow, len(variants))
+	for i, v := range variants {
+		rows[i] = imageVariantRow{Key: v.Key, Width: v.Width, Height: v.Height, Format: v.Format}
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodeImageVariants(raw sql.NullString) ([]domain.ImageVariant, error) {
+	if !raw.Valid || raw.String == "" {
+		return nil, nil
+	}
+	var rows []imageVariantRow
+	if err := json.Unmarshal([]byte(raw.String), &rows); err != nil {
+		return nil, fmt.Errorf("thumbnail_variants tidak valid: %w", err)
+	}
+	variants := make([]domain.ImageVariant, len(rows))
+	for i, r := range rows {
+		variants[i] = domain.ImageVariant{Key: r.Key, Width: r.Width, Height: r.Height, Format: r.Format}
+	}
+	return variants, nil
+}
+
+func (r *bimbelRepository) ExistsDuplicate(ctx context.Context, name string, featureID, subjectID uint64, excludeID *uint64) (bool, error) {
 	query := `
 		SELECT COUNT(*) FROM bimbels 
 		WHERE name = ? AND feature_id = ? AND subject_id = ? AND deleted_at IS NULL
@@ -37,7 +115,7 @@ func (r *bimbelRepository) ExistsDuplicate(name string, featureID, subjectID uin
 	}
 
 	var count int
-	err := r.db.QueryRow(query, args...).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return false, err
 	}
@@ -45,67 +123,78 @@ func (r *bimbelRepository) ExistsDuplicate(name string, featureID, subjectID uin
 	return count > 0, nil
 }
 
-func (r *bimbelRepository) Create(b *domain.Bimbel) error {
+func (r *bimbelRepository) Create(ctx context.Context, b *domain.Bimbel) error {
+	variants, err := encodeImageVariants(b.ThumbnailVariants)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO bimbels (tutor_id, feature_id, subject_id, name, limit_peserta, is_active, thumbnail, deskripsi, harga, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+		INSERT INTO bimbels (tutor_id, feature_id, subject_id, name, limit_peserta, status, thumbnail, thumbnail_variants, deskripsi, harga, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
 	`
 
-	res, err := r.db.Exec(query,
+	id, err := r.db.InsertIDContext(ctx, query,
 		b.TutorID, b.FeatureID, b.SubjectID,
-		b.Name, b.LimitPeserta, b.IsActive,
-		b.Thumbnail, b.Deskripsi, b.Harga,
+		b.Name, b.LimitPeserta, b.Status,
+		b.Thumbnail, variants, b.Deskripsi, b.Harga,
 	)
+	if db.IsDuplicateKey(err) {
+		return domain.ErrBimbelNameTaken
+	}
 	if err != nil {
 		return err
 	}
 
-	id, _ := res.LastInsertId()
-	b.ID = uint64(id)
+	b.ID = id
 	b.CreatedAt = time.Now()
 	b.UpdatedAt = time.Now()
 	return nil
 }
 
-func (r *bimbelRepository) Update(b *domain.Bimbel) error {
+func (r *bimbelRepository) Update(ctx context.Context, b *domain.Bimbel) error {
+	variants, err := encodeImageVariants(b.ThumbnailVariants)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		UPDATE bimbels SET feature_id=?, subject_id=?, name=?, limit_peserta=?, is_active=?, thumbnail=?, deskripsi=?, harga=?, updated_at=NOW()
+		UPDATE bimbels SET feature_id=?, subject_id=?, name=?, limit_peserta=?, thumbnail=?, thumbnail_variants=?, deskripsi=?, harga=?, updated_at=NOW()
 		WHERE id=? AND deleted_at IS NULL
 	`
-	_, err := r.db.Exec(query, b.FeatureID, b.SubjectID, b.Name, b.LimitPeserta, b.IsActive, b.Thumbnail, b.Deskripsi, b.Harga, b.ID)
+	_, err = r.db.ExecContext(ctx, query, b.FeatureID, b.SubjectID, b.Name, b.LimitPeserta, b.Thumbnail, variants, b.Deskripsi, b.Harga, b.ID)
+	if db.IsDuplicateKey(err) {
+		return domain.ErrBimbelNameTaken
+	}
 	return err
 }
 
-func (r *bimbelRepository) Delete(id uint64) error {
-	_, err := r.db.Exec(`UPDATE bimbels SET deleted_at = NOW() WHERE id = ?`, id)
+func (r *bimbelRepository) Delete(ctx context.Context, id uint64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE bimbels SET deleted_at = NOW() WHERE id = ?`, id)
 	return err
 }
 
-func (r *bimbelRepository) FindByID(id uint64) (*domain.Bimbel, error) {
+func (r *bimbelRepository) FindByID(ctx context.Context, id uint64) (*domain.Bimbel, error) {
 	query := `
-		SELECT id, tutor_id, feature_id, subject_id, name, limit_peserta, is_active, thumbnail, deskripsi, harga, created_at, updated_at
+		SELECT ` + bimbelColumns + `
 		FROM bimbels WHERE id = ? AND deleted_at IS NULL
 	`
-	var b domain.Bimbel
-	err := r.db.QueryRow(query, id).Scan(
-		&b.ID, &b.TutorID, &b.FeatureID, &b.SubjectID, &b.Name, &b.LimitPeserta,
-		&b.IsActive, &b.Thumbnail, &b.Deskripsi, &b.Harga, &b.CreatedAt, &b.UpdatedAt,
-	)
+	b, err := scanBimbel(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("bimbel not found")
+			return nil, domain.ErrBimbelNotFound
 		}
 		return nil, err
 	}
-	return &b, nil
+	return b, nil
 }
 
-func (r *bimbelRepository) FindByTutor(tutorID uint64) ([]domain.Bimbel, error) {
+func (r *bimbelRepository) FindByTutor(ctx context.Context, tutorID uint64) ([]domain.Bimbel, error) {
 	query := `
-		SELECT id, tutor_id, feature_id, subject_id, name, limit_peserta, is_active, thumbnail, deskripsi, harga, created_at, updated_at
+		SELECT ` + bimbelColumns + `
 		FROM bimbels WHERE tutor_id = ? AND deleted_at IS NULL
 	`
-	rows, err := r.db.Query(query, tutorID)
+	rows, err := r.db.QueryContext(ctx, query, tutorID)
 	if err != nil {
 		return nil, err
 	}
@@ -113,15 +202,16 @@ func (r *bimbelRepository) FindByTutor(tutorID uint64) ([]domain.Bimbel, error)
 
 	var result []domain.Bimbel
 	for rows.Next() {
-		var b domain.Bimbel
-		rows.Scan(&b.ID, &b.TutorID, &b.FeatureID, &b.SubjectID, &b.Name, &b.LimitPeserta,
-			&b.IsActive, &b.Thumbnail, &b.Deskripsi, &b.Harga, &b.CreatedAt, &b.UpdatedAt)
-		result = append(result, b)
+		b, err := scanBimbel(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *b)
 	}
-	return result, nil
+	return result, rows.Err()
 }
 
-func (r *bimbelRepository) ExistsByNameAndTutor(name string, tutorID uint64) (bool, error) {
+func (r *bimbelRepository) ExistsByNameAndTutor(ctx context.Context, name string, tutorID uint64) (bool, error) {
 	query := `
 		SELECT COUNT(*) 
 		FROM bimbels 
@@ -129,10 +219,348 @@ func (r *bimbelRepository) ExistsByNameAndTutor(name string, tutorID uint64) (bo
 	`
 
 	var count int
-	err := r.db.QueryRow(query, name, tutorID).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, name, tutorID).Scan(&count)
 	if err != nil {
 		return false, err
 	}
 
 	return count > 0, nil
 }
+
+var bimbelSortColumns = map[string]string{
+	domain.BimbelSortNewest:    "created_at DESC, id DESC",
+	domain.BimbelSortPriceAsc:  "harga ASC, id ASC",
+	domain.BimbelSortPriceDesc: "harga DESC, id ASC",
+	domain.BimbelSortName:      "name ASC, id ASC",
+}
+
+// List mengembalikan bimbel yang tampil di katalog (published & closed,
+// sama dengan domain.BimbelVisible) sesuai filter beserta total datanya
+func (r *bimbelRepository) List(ctx context.Context, f domain.BimbelFilter) ([]domain.Bimbel, int64, error) {
+	statuses := strings.TrimSuffix(strings.Repeat("?, ", len(domain.BimbelVisibleStatuses)), ", ")
+	conditions := []string{"deleted_at IS NULL", "status IN (" + statuses + ")"}
+	args := []interface{}{}
+	for _, s := range domain.BimbelVisibleStatuses {
+		args = append(args, s)
+	}
+
+	if f.FeatureID != 0 {
+		conditions = append(conditions, "feature_id = ?")
+		args = append(args, f.FeatureID)
+	}
+	if f.SubjectID != 0 {
+		conditions = append(conditions, "subject_id = ?")
+		args = append(args, f.SubjectID)
+	}
+	if f.TutorID != 0 {
+		conditions = append(conditions, "tutor_id = ?")
+		args = append(args, f.TutorID)
+	}
+	if f.MinHarga != nil {
+		conditions = append(conditions, "harga >= ?")
+		args = append(args, *f.MinHarga)
+	}
+	if f.MaxHarga != nil {
+		conditions = append(conditions, "harga <= ?")
+		args = append(args, *f.MaxHarga)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM bimbels"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	orderBy, ok := bimbelSortColumns[f.Sort]
+	if !ok {
+		orderBy = bimbelSortColumns[domain.BimbelSortNewest]
+	}
+
+	query := `
+		SELECT ` + bimbelColumns + `
+		FROM bimbels` + where + " ORDER BY " + orderBy + " LIMIT ? OFFSET ?"
+	rows, err := r.db.QueryContext(ctx, query, append(args, f.Limit, (f.Page-1)*f.Limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	result := []domain.Bimbel{}
+	for rows.Next() {
+		b, err := scanBimbel(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, *b)
+	}
+	return result, total, rows.Err()
+}
+
+// ThumbnailKeysInUse mengembalikan semua object key thumbnail (termasuk
+// varian) milik bimbel, termasuk yang masih di trash supaya tetap bisa
+// dipulihkan. Thumbnail bimbel di trash baru dibuang setelah barisnya di-purge.
+func (r *bimbelRepository) ThumbnailKeysInUse(ctx context.Context) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT thumbnail, thumbnail_variants FROM bimbels`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := map[string]bool{}
+	for rows.Next() {
+		var (
+			thumbnail string
+			raw       sql.NullString
+		)
+		if err := rows.Scan(&thumbnail, &raw); err != nil {
+			return nil, err
+		}
+		if thumbnail != "" {
+			keys[thumbnail] = true
+		}
+		variants, err := decodeImageVariants(raw)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range variants {
+			keys[v.Key] = true
+		}
+	}
+	return keys, rows.Err()
+}
+
+// ExistsActiveByFeature mengecek bimbel yang belum dihapus di bawah feature,
+// baik langsung maupun lewat subject milik feature tersebut
+func (r *bimbelRepository) ExistsActiveByFeature(ctx context.Context, featureID uint64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM bimbels
+			WHERE deleted_at IS NULL
+			  AND (feature_id = ? OR subject_id IN (SELECT id FROM subjects WHERE feature_id = ?))
+		)
+	`, featureID, featureID).Scan(&exists)
+	return exists, err
+}
+
+func (r *bimbelRepository) ExistsActiveBySubject(ctx context.Context, subjectID uint64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM bimbels WHERE subject_id = ? AND deleted_at IS NULL)
+	`, subjectID).Scan(&exists)
+	return exists, err
+}
+
+func (r *bimbelRepository) ListDeleted(ctx context.Context) ([]domain.Bimbel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+bimbelColumns+`
+		FROM bimbels WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.Bimbel{}
+	for rows.Next() {
+		b, err := scanBimbel(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *b)
+	}
+	return result, rows.Err()
+}
+
+// LockDeletedByID mengambil dan mengunci bimbel yang ada di trash
+func (r *bimbelRepository) LockDeletedByID(ctx context.Context, id uint64) (*domain.Bimbel, error) {
+	query := `
+		SELECT ` + bimbelColumns + `
+		FROM bimbels WHERE id = ? AND deleted_at IS NOT NULL FOR UPDATE
+	`
+	b, err := scanBimbel(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrBimbelNotInTrash
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r *bimbelRepository) Restore(ctx context.Context, id uint64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE bimbels SET deleted_at = NULL, updated_at = NOW() WHERE id = ?`, id)
+	if db.IsDuplicateKey(err) {
+		return domain.ErrBimbelNameTaken
+	}
+	return err
+}
+
+// PurgeDeleted menghapus permanen bimbel yang di-trash sebelum before.
+// Bimbel yang punya riwayat (enrollment, invoice, sesi, review) tetap
+// disimpan karena datanya masih dirujuk laporan dan pembayaran.
+func (r *bimbelRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM bimbels
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		  AND NOT EXISTS (SELECT 1 FROM enrollments e WHERE e.bimbel_id = bimbels.id)
+		  AND NOT EXISTS (SELECT 1 FROM invoices i WHERE i.bimbel_id = bimbels.id)
+		  AND NOT EXISTS (SELECT 1 FROM bimbel_sessions s WHERE s.bimbel_id = bimbels.id)
+		  AND NOT EXISTS (SELECT 1 FROM bimbel_session_recurrences sr WHERE sr.bimbel_id = bimbels.id)
+		  AND NOT EXISTS (SELECT 1 FROM reviews rv WHERE rv.bimbel_id = bimbels.id)
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// LockByID mengambil dan mengunci bimbel (FOR UPDATE) untuk perubahan status
+func (r *bimbelRepository) LockByID(ctx context.Context, id uint64) (*domain.Bimbel, error) {
+	query := `
+		SELECT ` + bimbelColumns + `
+		FROM bimbels WHERE id = ? AND deleted_at IS NULL FOR UPDATE
+	`
+	b, err := scanBimbel(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrBimbelNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// UpdateStatus hanya berhasil jika status saat ini masih from, sehingga
+// perubahan yang balapan tidak saling menimpa
+func (r *bimbelRepository) UpdateStatus(ctx context.Context, id uint64, from, to string) error {
+	if !domain.CanTransitionBimbel(from, to) {
+		return domain.ErrInvalidBimbelTransition
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE bimbels SET status = ?, updated_at = NOW()
+		WHERE id = ? AND status = ? AND deleted_at IS NULL
+	`, to, id, from)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrInvalidBimbelTransition
+	}
+	return nil
+}
+
+func (r *bimbelRepository) RecordStatusChange(ctx context.Context, change *domain.BimbelStatusChange) error {
+	var from, reason sql.NullString
+	if change.FromStatus != "" {
+		from = sql.NullString{String: change.FromStatus, Valid: true}
+	}
+	if change.Reason != "" {
+		reason = sql.NullString{String: change.Reason, Valid: true}
+	}
+
+	id, err := r.db.InsertIDContext(ctx, `
+		INSERT INTO bimbel_status_history (bimbel_id, from_status, to_status, actor_user_id, actor_role, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, NOW())
+	`, change.BimbelID, from, change.ToStatus, change.ActorUserID, change.ActorRole, reason)
+	if err != nil {
+		return err
+	}
+
+	change.ID = id
+	change.CreatedAt = time.Now()
+	return nil
+}
+
+func (r *bimbelRepository) StatusHistory(ctx context.Context, id uint64) ([]domain.BimbelStatusChange, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, bimbel_id, from_status, to_status, actor_user_id, actor_role, reason, created_at
+		FROM bimbel_status_history
+		WHERE bimbel_id = ?
+		ORDER BY id ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []domain.BimbelStatusChange{}
+	for rows.Next() {
+		var (
+			c            domain.BimbelStatusChange
+			from, reason sql.NullString
+			actorID      sql.NullInt64
+		)
+		if err := rows.Scan(&c.ID, &c.BimbelID, &from, &c.ToStatus, &actorID, &c.ActorRole, &reason, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.FromStatus = from.String
+		c.Reason = reason.String
+		if actorID.Valid {
+			uid := uint64(actorID.Int64)
+			c.ActorUserID = &uid
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
+
+// LastStatusChange mengembalikan riwayat status terbaru, atau nil jika belum ada
+func (r *bimbelRepository) LastStatusChange(ctx context.Context, id uint64) (*domain.BimbelStatusChange, error) {
+	var (
+		c            domain.BimbelStatusChange
+		from, reason sql.NullString
+		actorID      sql.NullInt64
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, bimbel_id, from_status, to_status, actor_user_id, actor_role, reason, created_at
+		FROM bimbel_status_history
+		WHERE bimbel_id = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`, id).Scan(&c.ID, &c.BimbelID, &from, &c.ToStatus, &actorID, &c.ActorRole, &reason, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.FromStatus = from.String
+	c.Reason = reason.String
+	if actorID.Valid {
+		uid := uint64(actorID.Int64)
+		c.ActorUserID = &uid
+	}
+	return &c, nil
+}
+
+// FindFinishedPublished mengembalikan bimbel published yang punya jadwal
+// sesi dan semua sesinya sudah berakhir sebelum now
+func (r *bimbelRepository) FindFinishedPublished(ctx context.Context, now time.Time) ([]uint64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT b.id FROM bimbels b
+		WHERE b.status = ? AND b.deleted_at IS NULL
+		  AND EXISTS (SELECT 1 FROM bimbel_sessions s WHERE s.bimbel_id = b.id AND s.deleted_at IS NULL)
+		  AND NOT EXISTS (SELECT 1 FROM bimbel_sessions s WHERE s.bimbel_id = b.id AND s.deleted_at IS NULL AND s.end_at > ?)
+	`, domain.BimbelStatusPublished, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"strings"
+	"testing"
+)
+
+func TestBimbelListShowsVisibleStatuses(t *testing.T) {
+	for _, dc := range dialectCases {
+		t.Run(dc.dialect, func(t *testing.T) {
+			conn, script := newFakeDB(t, dc.dialect,
+				fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(0)}}},
+				fakeResult{columns: []string{"id"}}, // katalog kosong
+			)
+			filter := domain.BimbelFilter{Page: 1, Limit: 10, Sort: domain.BimbelSortNewest}
+			if _, _, err := NewBimbelRepository(conn).List(context.Background(), filter); err != nil {
+				t.Fatalf("List: %v", err)
+			}
+
+			want := "status IN (?, ?)"
+			if dc.dialect == db.DialectPostgres {
+				want = "status IN ($1, $2)"
+			}
+			for _, q := range script.queries {
+				if !strings.Contains(q, want) {
+					t.Errorf("query tidak memfilter %v:\n%s", domain.BimbelVisibleStatuses, q)
+				}
+			}
+		})
+	}
+}
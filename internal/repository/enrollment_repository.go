@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+)
+
+type EnrollmentRepository interface {
+	Enroll(ctx context.Context, bimbelID, pesertaID uint64, status string) (*domain.Enrollment, error)
+	Cancel(ctx context.Context, bimbelID, pesertaID uint64) error
+	FindByID(ctx context.Context, id uint64) (*domain.Enrollment, error)
+	FindByPeserta(ctx context.Context, pesertaID uint64) ([]domain.Enrollment, error)
+	ExistsActive(ctx context.Context, bimbelID, pesertaID uint64) (bool, error)
+	FindByBimbel(ctx context.Context, bimbelID uint64) ([]domain.Enrollment, error)
+	FindByBimbelAndPeserta(ctx context.Context, bimbelID, pesertaID uint64) (*domain.Enrollment, error)
+	Complete(ctx context.Context, bimbelID, id uint64) error
+	CountTakenSeats(ctx context.Context, bimbelID uint64) (int, error)
+}
+
+type enrollmentRepository struct {
+	db db.Conn
+}
+
+func NewEnrollmentRepository(db db.Conn) EnrollmentRepository {
+	return &enrollmentRepository{db}
+}
+
+// Enroll mendaftarkan peserta ke bimbel dengan status awal active (gratis)
+// atau pending (menunggu pembayaran). Baris bimbel dikunci (FOR UPDATE)
+// selama transaksi sehingga pengecekan kuota dan insert berjalan berurutan
+// walaupun banyak request datang bersamaan.
+func (r *enrollmentRepository) Enroll(ctx context.Context, bimbelID, pesertaID uint64, status string) (*domain.Enrollment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// ==== 1️⃣ Kunci bimbel & validasi status ====
+	var (
+		limit        int
+		bimbelStatus string
+		deletedAt    sql.NullTime
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT limit_peserta, status, deleted_at
+		FROM bimbels WHERE id = ? FOR UPDATE
+	`, bimbelID).Scan(&limit, &bimbelStatus, &deletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrBimbelNotFound
+		}
+		return nil, err
+	}
+	if deletedAt.Valid {
+		return nil, domain.ErrBimbelNotFound
+	}
+	// Hanya bimbel published yang menerima pendaftaran
+	if bimbelStatus != domain.BimbelStatusPublished {
+		return nil, domain.ErrBimbelInactive
+	}
+
+	// ==== 2️⃣ Cek enrollment lama milik peserta ====
+	var (
+		existingID     uint64
+		existingStatus string
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, status FROM enrollments
+		WHERE bimbel_id = ? AND peserta_id = ? FOR UPDATE
+	`, bimbelID, pesertaID).Scan(&existingID, &existingStatus)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil && existingStatus != domain.EnrollmentStatusCancelled {
+		return nil, domain.ErrAlreadyEnrolled
+	}
+
+	// ==== 3️⃣ Cek kuota (limit_peserta <= 0 berarti tanpa batas) ====
+	// Enrollment pending ikut dihitung karena kursinya sedang dipesan
+	var count int
+	if limit > 0 {
+		count, err = countTakenSeats(ctx, tx, bimbelID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= limit {
+			return nil, domain.ErrBimbelFull
+		}
+	}
+
+	// ==== 4️⃣ Insert baru atau aktifkan kembali ====
+	id := existingID
+	if existingID != 0 {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE enrollments
+			SET status = ?, enrolled_at = NOW(), cancelled_at = NULL, updated_at = NOW()
+			WHERE id = ?
+		`, status, existingID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		id, err = tx.InsertIDContext(ctx, `
+			INSERT INTO enrollments (bimbel_id, peserta_id, status, enrolled_at, created_at, updated_at)
+			VALUES (?, ?, ?, NOW(), NOW(), NOW())
+		`, bimbelID, pesertaID, status)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// ==== 5️⃣ Kursi terakhir terisi: tutup bimbel otomatis ====
+	if limit > 0 && count+1 >= limit {
+		bimbels := NewBimbelRepository(tx)
+		if err := bimbels.UpdateStatus(ctx, bimbelID, domain.BimbelStatusPublished, domain.BimbelStatusClosed); err != nil {
+			return nil, err
+		}
+		err := bimbels.RecordStatusChange(ctx, &domain.BimbelStatusChange{
+			BimbelID:   bimbelID,
+			FromStatus: domain.BimbelStatusPublished,
+			ToStatus:   domain.BimbelStatusClosed,
+			ActorRole:  domain.BimbelActorSystem,
+			Reason:     domain.BimbelReasonFull,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, id)
+}
+
+// Cancel membatalkan enrollment aktif/pending. Invoice yang masih pending
+// ikut dibatalkan, dan bimbel yang tadinya ditutup karena penuh dibuka
+// kembali, semuanya dalam transaksi yang sama. Enrollment yang invoice-nya
+// sudah dibayar ditolak karena belum ada alur refund.
+func (r *enrollmentRepository) Cancel(ctx context.Context, bimbelID, pesertaID uint64) error {
+	return r.db.WithTx(ctx, func(tx *db.Tx) error {
+		// Bimbel dikunci lebih dulu, urutannya sama dengan Enroll
+		if err := lockBimbelRow(ctx, tx, bimbelID); err != nil {
+			if err == sql.ErrNoRows {
+				return domain.ErrEnrollmentNotFound
+			}
+			return err
+		}
+
+		var id uint64
+		err := tx.QueryRowContext(ctx, `
+			SELECT id FROM enrollments
+			WHERE bimbel_id = ? AND peserta_id = ? AND status IN (?, ?) FOR UPDATE
+		`, bimbelID, pesertaID, domain.EnrollmentStatusActive, domain.EnrollmentStatusPending).Scan(&id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return domain.ErrEnrollmentNotFound
+			}
+			return err
+		}
+
+		// Webhook pembayaran juga mengunci bimbel lebih dulu, jadi status
+		// invoice tidak bisa berubah menjadi paid setelah pengecekan ini
+		var paid bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS(SELECT 1 FROM invoices WHERE enrollment_id = ? AND status = ?)
+		`, id, domain.InvoiceStatusPaid).Scan(&paid)
+		if err != nil {
+			return err
+		}
+		if paid {
+			return domain.ErrEnrollmentPaid
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE enrollments
+			SET status = ?, cancelled_at = NOW(), updated_at = NOW()
+			WHERE id = ?
+		`, domain.EnrollmentStatusCancelled, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE invoices SET status = ?, updated_at = NOW()
+			WHERE enrollment_id = ? AND status = ?
+		`, domain.InvoiceStatusCancelled, id, domain.InvoiceStatusPending)
+		if err != nil {
+			return err
+		}
+
+		return reopenIfSeatFreed(ctx, tx, bimbelID)
+	})
+}
+
+// lockBimbelRow mengunci baris bimbel (termasuk yang sudah di-soft-delete).
+// Semua transaksi yang mengubah enrollment mengunci bimbel lebih dulu supaya
+// urutan lock-nya sama dan tidak saling deadlock.
+func lockBimbelRow(ctx context.Context, conn db.Conn, bimbelID uint64) error {
+	var id uint64
+	return conn.QueryRowContext(ctx, `SELECT id FROM bimbels WHERE id = ? FOR UPDATE`, bimbelID).Scan(&id)
+}
+
+// countTakenSeats menghitung kursi terpakai: enrollment active dan pending
+// (kursinya sedang dipesan)
+func countTakenSeats(ctx context.Context, conn db.Conn, bimbelID uint64) (int, error) {
+	var count int
+	err := conn.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM enrollments
+		WHERE bimbel_id = ? AND status IN (?, ?)
+	`, bimbelID, domain.EnrollmentStatusActive, domain.EnrollmentStatusPending).Scan(&count)
+	return count, err
+}
+
+// CountTakenSeats menghitung enrollment active + pending; panggil di dalam
+// transaksi setelah bimbel dikunci agar hasilnya tidak basi
+func (r *enrollmentRepository) CountTakenSeats(ctx context.Context, bimbelID uint64) (int, error) {
+	return countTakenSeats(ctx, r.db, bimbelID)
+}
+
+// reopenIfSeatFreed membuka kembali (closed → published) bimbel yang ditutup
+// sistem karena penuh setelah ada kursi yang kosong lagi. Dipanggil di dalam
+// transaksi yang membatalkan enrollment; bimbel yang ditutup manual tidak
+// disentuh.
+func reopenIfSeatFreed(ctx context.Context, conn db.Conn, bimbelID uint64) error {
+	bimbels := NewBimbelRepository(conn)
+	b, err := bimbels.LockByID(ctx, bimbelID)
+	if err != nil {
+		if errors.Is(err, domain.ErrBimbelNotFound) {
+			return nil
+		}
+		return err
+	}
+	if b.Status != domain.BimbelStatusClosed || b.LimitPeserta <= 0 {
+		return nil
+	}
+
+	last, err := bimbels.LastStatusChange(ctx, bimbelID)
+	if err != nil {
+		return err
+	}
+	taken, err := countTakenSeats(ctx, conn, bimbelID)
+	if err != nil {
+		return err
+	}
+	if !domain.ShouldReopenBimbel(b.Status, b.LimitPeserta, taken, last) {
+		return nil
+	}
+
+	if err := bimbels.UpdateStatus(ctx, bimbelID, domain.BimbelStatusClosed, domain.BimbelStatusPublished); err != nil {
+		return err
+	}
+	return bimbels.RecordStatusChange(ctx, &domain.BimbelStatusChange{
+		BimbelID:   bimbelID,
+		FromStatus: domain.BimbelStatusClosed,
+		ToStatus:   domain.BimbelStatusPublished,
+		ActorRole:  domain.BimbelActorSystem,
+		Reason:     domain.BimbelReasonSeatFree,
+	})
+}
+
+func (r *enrollmentRepository) FindByID(ctx context.Context, id uint64) (*domain.Enrollment, error) {
+	query := `
+		SELECT e.id, e.bimbel_id, e.peserta_id, e.status, b.name, e.enrolled_at, e.cancelled_at, e.created_at, e.updated_at
+		FROM enrollments e
+		JOIN bimbels b ON b.id = e.bimbel_id
+		WHERE e.id = ?
+	`
+	e, err := scanEnrollment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrEnrollmentNotFound
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *enrollmentRepository) FindByPeserta(ctx context.Context, pesertaID uint64) ([]domain.Enrollment, error) {
+	query := `
+		SELECT e.id, e.bimbel_id, e.peserta_id, e.status, b.name, e.enrolled_at, e.cancelled_at, e.created_at, e.updated_at
+		FROM enrollments e
+		JOIN bimbels b ON b.id = e.bimbel_id
+		WHERE e.peserta_id = ?
+		ORDER BY e.enrolled_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, pesertaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.Enrollment{}
+	for rows.Next() {
+		e, err := scanEnrollment(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *e)
+	}
+	return result, rows.Err()
+}
+
+func (r *enrollmentRepository) FindByBimbel(ctx context.Context, bimbelID uint64) ([]domain.Enrollment, error) {
+	query := `
+		SELECT e.id, e.bimbel_id, e.peserta_id, e.status, b.name, e.enrolled_at, e.cancelled_at, e.created_at, e.updated_at
+		FROM enrollments e
+		JOIN bimbels b ON b.id = e.bimbel_id
+		WHERE e.bimbel_id = ?
+		ORDER BY e.enrolled_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, bimbelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.Enrollment{}
+	for rows.Next() {
+		e, err := scanEnrollment(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *e)
+	}
+	return result, rows.Err()
+}
+
+func (r *enrollmentRepository) FindByBimbelAndPeserta(ctx context.Context, bimbelID, pesertaID uint64) (*domain.Enrollment, error) {
+	query := `
+		SELECT e.id, e.bimbel_id, e.peserta_id, e.status, b.name, e.enrolled_at, e.cancelled_at, e.created_at, e.updated_at
+		FROM enrollments e
+		JOIN bimbels b ON b.id = e.bimbel_id
+		WHERE e.bimbel_id = ? AND e.peserta_id = ?
+	`
+	e, err := scanEnrollment(r.db.QueryRowContext(ctx, query, bimbelID, pesertaID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrEnrollmentNotFound
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+// Complete menandai enrollment aktif sebagai selesai diikuti
+func (r *enrollmentRepository) Complete(ctx context.Context, bimbelID, id uint64) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE enrollments SET status = ?, updated_at = NOW()
+		WHERE id = ? AND bimbel_id = ? AND status = ?
+	`, domain.EnrollmentStatusCompleted, id, bimbelID, domain.EnrollmentStatusActive)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrEnrollmentNotFound
+	}
+	return nil
+}
+
+func (r *enrollmentRepository) ExistsActive(ctx context.Context, bimbelID, pesertaID uint64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM enrollments WHERE bimbel_id = ? AND peserta_id = ? AND status = ?
+		)
+	`, bimbelID, pesertaID, domain.EnrollmentStatusActive).Scan(&exists)
+	return exists, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEnrollment(row rowScanner) (*domain.Enrollment, error) {
+	var (
+		e           domain.Enrollment
+		cancelledAt sql.NullTime
+	)
+	err := row.Scan(&e.ID, &e.BimbelID, &e.PesertaID, &e.Status, &e.BimbelName,
+		&e.EnrolledAt, &cancelledAt, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if cancelledAt.Valid {
+		e.CancelledAt = &cancelledAt.Time
+	}
+	return &e, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"main-service/internal/domain"
+	"testing"
+)
+
+func TestEnrollmentCancelRejectsPaidInvoice(t *testing.T) {
+	for _, dc := range dialectCases {
+		t.Run(dc.dialect, func(t *testing.T) {
+			conn, script := newFakeDB(t, dc.dialect,
+				fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}}}, // lock bimbel
+				fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(7)}}}, // enrollment aktif
+				fakeResult{columns: []string{"exists"}, rows: [][]driver.Value{{true}}}, // invoice paid
+			)
+
+			// fakesql tidak mendukung exec, jadi error lain berarti enrollment sempat diubah
+			err := NewEnrollmentRepository(conn).Cancel(context.Background(), 1, 100)
+			if !errors.Is(err, domain.ErrEnrollmentPaid) {
+				t.Fatalf("Cancel: err = %v, want ErrEnrollmentPaid", err)
+			}
+			if len(script.queries) != 3 {
+				t.Errorf("queries = %d, want 3", len(script.queries))
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"main-service/internal/db"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakesql adalah driver database/sql minimal untuk menguji scan repository
+// tanpa server database. Setiap test mendaftarkan hasil query-nya sendiri
+// dengan nilai driver persis seperti yang dikirim driver aslinya: go-sql-driver
+// mengirim TINYINT(1) sebagai int64, pgx mengirim BOOLEAN sebagai bool.
+
+type fakeResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+type fakeScript struct {
+	mu      sync.Mutex
+	results []fakeResult // dikonsumsi berurutan per query
+	queries []string
+}
+
+var (
+	fakeScriptsMu sync.Mutex
+	fakeScripts   = map[string]*fakeScript{}
+)
+
+func init() {
+	sql.Register("fakesql", fakeDriver{})
+}
+
+// newFakeDB membuka *db.DB dengan dialect tertentu di atas driver fakesql
+func newFakeDB(t *testing.T, dialect string, results ...fakeResult) (*db.DB, *fakeScript) {
+	t.Helper()
+	d, err := db.NewDialect(dialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script := &fakeScript{results: results}
+	fakeScriptsMu.Lock()
+	fakeScripts[t.Name()] = script
+	fakeScriptsMu.Unlock()
+
+	conn, err := sql.Open("fakesql", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		fakeScriptsMu.Lock()
+		delete(fakeScripts, t.Name())
+		fakeScriptsMu.Unlock()
+	})
+	return db.New(conn, d), script
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeScriptsMu.Lock()
+	defer fakeScriptsMu.Unlock()
+	script, ok := fakeScripts[name]
+	if !ok {
+		return nil, fmt.Errorf("fakesql: script %q tidak terdaftar", name)
+	}
+	return &fakeConn{script: script}, nil
+}
+
+type fakeConn struct {
+	script *fakeScript
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	s := c.script
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = append(s.queries, query)
+	if len(s.results) == 0 {
+		return nil, fmt.Errorf("fakesql: tidak ada hasil untuk query %q", strings.TrimSpace(query))
+	}
+	res := s.results[0]
+	s.results = s.results[1:]
+	return &fakeRows{columns: res.columns, rows: res.rows}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("fakesql: exec tidak didukung")
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, nil)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
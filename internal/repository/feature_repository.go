@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 	"fmt"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"time"
 )
 
 type Feature struct {
@@ -13,32 +16,39 @@ type Feature struct {
 	Roles     string  `json:"roles"`
 	CreatedAt *string `json:"created_at,omitempty"`
 	UpdatedAt *string `json:"updated_at,omitempty"`
+	DeletedAt *string `json:"deleted_at,omitempty"` // hanya terisi di trash
 }
 
 type FeatureRepository interface {
-	GetByRole(role string) ([]Feature, error)
-	ExistsByID(id uint64) (bool, error)
-	ExistsByName(name string) (bool, error)
-	Create(name string, roles string, isActive bool) (*Feature, error)
-	ExistsByNameExceptID(id uint64, name string) (bool, error)
-	Update(id uint64, name string, roles string, isActive bool) (*Feature, error)
-	GetByID(id uint64) (*Feature, error)
-	Delete(id uint64) error
+	GetByRole(ctx context.Context, role string) ([]Feature, error)
+	ExistsByID(ctx context.Context, id uint64) (bool, error)
+	LockActiveByID(ctx context.Context, id uint64) (bool, error)
+	ExistsByName(ctx context.Context, name string) (bool, error)
+	Create(ctx context.Context, name string, roles string, isActive bool) (*Feature, error)
+	ExistsByNameExceptID(ctx context.Context, id uint64, name string) (bool, error)
+	Update(ctx context.Context, id uint64, name string, roles string, isActive bool) (*Feature, error)
+	GetByID(ctx context.Context, id uint64) (*Feature, error)
+	LockByID(ctx context.Context, id uint64) error
+	Delete(ctx context.Context, id uint64, deletedAt time.Time) error
+	ListDeleted(ctx context.Context) ([]Feature, error)
+	LockDeletedByID(ctx context.Context, id uint64) (*Feature, error)
+	Restore(ctx context.Context, id uint64) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 type featureRepository struct {
-	db *sql.DB
+	db db.Conn
 }
 
-func NewFeatureRepository(db *sql.DB) FeatureRepository {
+func NewFeatureRepository(db db.Conn) FeatureRepository {
 	return &featureRepository{db: db}
 }
 
-func (r *featureRepository) GetByRole(role string) ([]Feature, error) {
+func (r *featureRepository) GetByRole(ctx context.Context, role string) ([]Feature, error) {
 	query := `
 		SELECT id, name, is_active, roles, created_at, updated_at
 		FROM features
-		WHERE is_active = 1
+		WHERE is_active = TRUE AND deleted_at IS NULL
 		  AND (
 			roles LIKE ? OR
 			roles LIKE ? OR
@@ -50,7 +60,7 @@ func (r *featureRepository) GetByRole(role string) ([]Feature, error) {
 	rolePatternStart := fmt.Sprintf("%s,%%", role)
 	rolePatternEnd := fmt.Sprintf("%%,%s", role)
 
-	rows, err := r.db.Query(query, rolePattern, rolePatternStart, rolePatternEnd, role)
+	rows, err := r.db.QueryContext(ctx, query, rolePattern, rolePatternStart, rolePatternEnd, role)
 	if err != nil {
 		return nil, err
 	}
@@ -67,76 +77,83 @@ func (r *featureRepository) GetByRole(role string) ([]Feature, error) {
 	return features, nil
 }
 
-func (r *featureRepository) ExistsByID(id uint64) (bool, error) {
+func (r *featureRepository) ExistsByID(ctx context.Context, id uint64) (bool, error) {
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM features WHERE id = ? AND is_active = 1)`
-	err := r.db.QueryRow(query, id).Scan(&exists)
+	query := `SELECT EXISTS(SELECT 1 FROM features WHERE id = ? AND is_active = TRUE AND deleted_at IS NULL)`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&exists)
 	return exists, err
 }
 
-func (r *featureRepository) ExistsByName(name string) (bool, error) {
+// LockActiveByID mengunci baris feature (FOR UPDATE) sampai transaksi selesai,
+// sehingga pengecekan duplikat subject di bawah feature ini tidak balapan.
+// Hanya berarti jika dipanggil di dalam TxManager.WithinTx.
+func (r *featureRepository) LockActiveByID(ctx context.Context, id uint64) (bool, error) {
+	var active bool
+	err := r.db.QueryRowContext(ctx, `SELECT is_active FROM features WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&active)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return active, err
+}
+
+func (r *featureRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
 	var exists bool
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM features 
-			WHERE LOWER(TRIM(name)) = LOWER(TRIM(?))
+			WHERE LOWER(TRIM(name)) = LOWER(TRIM(?)) AND deleted_at IS NULL
 		)
 	`
-	err := r.db.QueryRow(query, name).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query, name).Scan(&exists)
 	return exists, err
 }
 
-func (r *featureRepository) Create(name string, roles string, isActive bool) (*Feature, error) {
+func (r *featureRepository) Create(ctx context.Context, name string, roles string, isActive bool) (*Feature, error) {
 	query := `
 		INSERT INTO features (name, is_active, roles, created_at, updated_at)
 		VALUES (?, ?, ?, NOW(), NOW())
 	`
 
-	res, err := r.db.Exec(query, name, isActive, roles)
-	if err != nil {
-		return nil, err
-	}
-
-	id, err := res.LastInsertId()
+	id, err := r.db.InsertIDContext(ctx, query, name, isActive, roles)
 	if err != nil {
 		return nil, err
 	}
 
 	feature := &Feature{
-		ID:   uint64(id),
+		ID:   id,
 		Name: name,
 	}
 	return feature, nil
 }
 
-func (r *featureRepository) ExistsByNameExceptID(id uint64, name string) (bool, error) {
+func (r *featureRepository) ExistsByNameExceptID(ctx context.Context, id uint64, name string) (bool, error) {
 	var count int
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM features 
-		WHERE name = ? AND id <> ?`, name, id).Scan(&count)
+		WHERE name = ? AND id <> ? AND deleted_at IS NULL`, name, id).Scan(&count)
 	return count > 0, err
 }
 
-func (r *featureRepository) Update(id uint64, name string, roles string, isActive bool) (*Feature, error) {
-	_, err := r.db.Exec(`
+func (r *featureRepository) Update(ctx context.Context, id uint64, name string, roles string, isActive bool) (*Feature, error) {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE features
 		SET name = ?, is_active = ?, roles = ?, updated_at = NOW()
-		WHERE id = ?`, name, isActive, roles, id)
+		WHERE id = ? AND deleted_at IS NULL`, name, isActive, roles, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return r.GetByID(id)
+	return r.GetByID(ctx, id)
 }
 
-func (r *featureRepository) GetByID(id uint64) (*Feature, error) {
+func (r *featureRepository) GetByID(ctx context.Context, id uint64) (*Feature, error) {
 	var f Feature
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT id, name, is_active, roles, created_at, updated_at
-		FROM features WHERE id = ?`, id).
+		FROM features WHERE id = ? AND deleted_at IS NULL`, id).
 		Scan(&f.ID, &f.Name, &f.IsActive, &f.Roles, &f.CreatedAt, &f.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("feature not found")
+		return nil, domain.ErrFeatureNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -144,9 +161,20 @@ func (r *featureRepository) GetByID(id uint64) (*Feature, error) {
 	return &f, nil
 }
 
-func (r *featureRepository) Delete(id uint64) error {
-	query := `DELETE FROM features WHERE id = ?`
-	result, err := r.db.Exec(query, id)
+// LockByID mengunci baris feature yang belum dihapus sampai transaksi selesai
+func (r *featureRepository) LockByID(ctx context.Context, id uint64) error {
+	var lockedID uint64
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM features WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return domain.ErrFeatureNotFound
+	}
+	return err
+}
+
+// Delete memindahkan feature ke trash (soft delete)
+func (r *featureRepository) Delete(ctx context.Context, id uint64, deletedAt time.Time) error {
+	query := `UPDATE features SET deleted_at = ?, updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, deletedAt, id)
 	if err != nil {
 		return err
 	}
@@ -156,8 +184,67 @@ func (r *featureRepository) Delete(id uint64) error {
 		return err
 	}
 	if rows == 0 {
-		return errors.New("data tidak ditemukan")
+		return domain.ErrFeatureNotFound
 	}
 
 	return nil
 }
+
+func (r *featureRepository) ListDeleted(ctx context.Context) ([]Feature, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, is_active, roles, created_at, updated_at, deleted_at
+		FROM features
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	features := []Feature{}
+	for rows.Next() {
+		var f Feature
+		if err := rows.Scan(&f.ID, &f.Name, &f.IsActive, &f.Roles, &f.CreatedAt, &f.UpdatedAt, &f.DeletedAt); err != nil {
+			return nil, err
+		}
+		features = append(features, f)
+	}
+	return features, rows.Err()
+}
+
+// LockDeletedByID mengambil dan mengunci feature yang ada di trash
+func (r *featureRepository) LockDeletedByID(ctx context.Context, id uint64) (*Feature, error) {
+	var f Feature
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, name, is_active, roles, created_at, updated_at, deleted_at
+		FROM features WHERE id = ? AND deleted_at IS NOT NULL FOR UPDATE`, id).
+		Scan(&f.ID, &f.Name, &f.IsActive, &f.Roles, &f.CreatedAt, &f.UpdatedAt, &f.DeletedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrFeatureNotInTrash
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *featureRepository) Restore(ctx context.Context, id uint64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE features SET deleted_at = NULL, updated_at = NOW() WHERE id = ?`, id)
+	return err
+}
+
+// PurgeDeleted menghapus permanen feature yang di-trash sebelum before dan
+// tidak lagi dirujuk subject maupun bimbel (termasuk yang masih di trash)
+func (r *featureRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM features
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		  AND NOT EXISTS (SELECT 1 FROM subjects s WHERE s.feature_id = features.id)
+		  AND NOT EXISTS (SELECT 1 FROM bimbels b WHERE b.feature_id = features.id)
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
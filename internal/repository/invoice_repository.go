@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"time"
+)
+
+type InvoiceRepository interface {
+	Create(ctx context.Context, inv *domain.Invoice) error
+	SetCharge(ctx context.Context, id uint64, provider, ref, paymentURL string) error
+	FindByID(ctx context.Context, id uint64) (*domain.Invoice, error)
+	FindByNumber(ctx context.Context, number string) (*domain.Invoice, error)
+	FindByPeserta(ctx context.Context, pesertaID uint64) ([]domain.Invoice, error)
+	FindAll(ctx context.Context, status string) ([]domain.Invoice, error)
+	FindExpiredPending(ctx context.Context, now time.Time) ([]uint64, error)
+	Transition(ctx context.Context, id uint64, to string) (*domain.Invoice, error)
+}
+
+type invoiceRepository struct {
+	db db.Conn
+}
+
+func NewInvoiceRepository(db db.Conn) InvoiceRepository {
+	return &invoiceRepository{db}
+}
+
+func (r *invoiceRepository) Create(ctx context.Context, inv *domain.Invoice) error {
+	id, err := r.db.InsertIDContext(ctx, `
+		INSERT INTO invoices (number, enrollment_id, bimbel_id, peserta_id, amount, status, provider, provider_ref, payment_url, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, '', NULL, '', ?, NOW(), NOW())
+	`, inv.Number, inv.EnrollmentID, inv.BimbelID, inv.PesertaID, inv.Amount, domain.InvoiceStatusPending, inv.ExpiresAt.UTC())
+	if err != nil {
+		return err
+	}
+	inv.ID = id
+	inv.Status = domain.InvoiceStatusPending
+	inv.CreatedAt = time.Now()
+	inv.UpdatedAt = inv.CreatedAt
+	return nil
+}
+
+func (r *invoiceRepository) SetCharge(ctx context.Context, id uint64, provider, ref, paymentURL string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE invoices SET provider = ?, provider_ref = ?, payment_url = ?, updated_at = NOW()
+		WHERE id = ?
+	`, provider, ref, paymentURL, id)
+	return err
+}
+
+const invoiceColumns = `id, number, enrollment_id, bimbel_id, peserta_id, amount, status, provider,
+	COALESCE(provider_ref, ''), payment_url, expires_at, paid_at, created_at, updated_at`
+
+func scanInvoice(row rowScanner) (*domain.Invoice, error) {
+	var (
+		inv    domain.Invoice
+		paidAt sql.NullTime
+	)
+	err := row.Scan(&inv.ID, &inv.Number, &inv.EnrollmentID, &inv.BimbelID, &inv.PesertaID, &inv.Amount,
+		&inv.Status, &inv.Provider, &inv.ProviderRef, &inv.PaymentURL, &inv.ExpiresAt, &paidAt,
+		&inv.CreatedAt, &inv.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if paidAt.Valid {
+		inv.PaidAt = &paidAt.Time
+	}
+	return &inv, nil
+}
+
+func (r *invoiceRepository) FindByID(ctx context.Context, id uint64) (*domain.Invoice, error) {
+	inv, err := scanInvoice(r.db.QueryRowContext(ctx, `SELECT `+invoiceColumns+` FROM invoices WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	return inv, err
+}
+
+func (r *invoiceRepository) FindByNumber(ctx context.Context, number string) (*domain.Invoice, error) {
+	inv, err := scanInvoice(r.db.QueryRowContext(ctx, `SELECT `+invoiceColumns+` FROM invoices WHERE number = ?`, number))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvoiceNotFound
+	}
+	return inv, err
+}
+
+func (r *invoiceRepository) queryInvoices(ctx context.Context, query string, args ...interface{}) ([]domain.Invoice, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.Invoice{}
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *inv)
+	}
+	return result, rows.Err()
+}
+
+func (r *invoiceRepository) FindByPeserta(ctx context.Context, pesertaID uint64) ([]domain.Invoice, error) {
+	return r.queryInvoices(ctx, `
+		SELECT `+invoiceColumns+` FROM invoices WHERE peserta_id = ? ORDER BY created_at DESC
+	`, pesertaID)
+}
+
+func (r *invoiceRepository) FindAll(ctx context.Context, status string) ([]domain.Invoice, error) {
+	if status == "" {
+		return r.queryInvoices(ctx, `SELECT `+invoiceColumns+` FROM invoices ORDER BY created_at DESC`)
+	}
+	return r.queryInvoices(ctx, `
+		SELECT `+invoiceColumns+` FROM invoices WHERE status = ? ORDER BY created_at DESC
+	`, status)
+}
+
+func (r *invoiceRepository) FindExpiredPending(ctx context.Context, now time.Time) ([]uint64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM invoices WHERE status = ? AND expires_at < ?
+	`, domain.InvoiceStatusPending, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Transition mengubah status invoice dan menyesuaikan enrollment terkait
+// dalam satu transaksi. Invoice dikunci agar perubahan status yang datang
+// bersamaan (mis. expire vs paid) diproses berurutan. Jika enrollment ikut
+// batal, bimbel yang ditutup karena penuh dibuka kembali.
+func (r *invoiceRepository) Transition(ctx context.Context, id uint64, to string) (*domain.Invoice, error) {
+	// bimbel_id tidak pernah berubah, jadi aman dibaca sebelum mengunci
+	var bimbelID uint64
+	err := r.db.QueryRowContext(ctx, `SELECT bimbel_id FROM invoices WHERE id = ?`, id).Scan(&bimbelID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+
+	err = r.db.WithTx(ctx, func(tx *db.Tx) error {
+		// Bimbel dikunci lebih dulu, urutannya sama dengan Enroll dan Cancel
+		if err := lockBimbelRow(ctx, tx, bimbelID); err != nil {
+			return err
+		}
+
+		var (
+			status       string
+			enrollmentID uint64
+		)
+		err := tx.QueryRowContext(ctx, `SELECT status, enrollment_id FROM invoices WHERE id = ? FOR UPDATE`, id).
+			Scan(&status, &enrollmentID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return domain.ErrInvoiceNotFound
+			}
+			return err
+		}
+		if !domain.CanTransitionInvoice(status, to) {
+			return domain.ErrInvalidInvoiceTransition
+		}
+
+		if to == domain.InvoiceStatusPaid {
+			_, err = tx.ExecContext(ctx, `UPDATE invoices SET status = ?, paid_at = NOW(), updated_at = NOW() WHERE id = ?`, to, id)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE invoices SET status = ?, updated_at = NOW() WHERE id = ?`, to, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		// ==== Sinkronkan status enrollment ====
+		switch to {
+		case domain.InvoiceStatusPaid:
+			_, err = tx.ExecContext(ctx, `
+				UPDATE enrollments SET status = ?, updated_at = NOW()
+				WHERE id = ? AND status = ?
+			`, domain.EnrollmentStatusActive, enrollmentID, domain.EnrollmentStatusPending)
+			return err
+		case domain.InvoiceStatusExpired, domain.InvoiceStatusCancelled, domain.InvoiceStatusRefunded:
+			_, err = tx.ExecContext(ctx, `
+				UPDATE enrollments SET status = ?, cancelled_at = NOW(), updated_at = NOW()
+				WHERE id = ? AND status IN (?, ?)
+			`, domain.EnrollmentStatusCancelled, enrollmentID, domain.EnrollmentStatusPending, domain.EnrollmentStatusActive)
+			if err != nil {
+				return err
+			}
+			// Kursi yang dilepas bisa membuka kembali bimbel yang penuh
+			return reopenIfSeatFreed(ctx, tx, bimbelID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
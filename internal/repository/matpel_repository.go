@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"errors"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"time"
 )
 
 type Matpel struct {
@@ -13,35 +16,44 @@ type Matpel struct {
 	IsActive  bool    `json:"is_active"`
 	CreatedAt *string `json:"created_at,omitempty"`
 	UpdatedAt *string `json:"updated_at,omitempty"`
+	DeletedAt *string `json:"deleted_at,omitempty"` // hanya terisi di trash
 }
 
 type MatpelRepository interface {
-	GetByFeature(featureId uint64) ([]Matpel, error)
-	Create(featureID uint64, name string, deskripsi *string, isActive bool) (*Matpel, error)
-	ExistsByNameAndFeatureID(name string, featureID uint64) (bool, error)
-	Update(id uint64, featureID uint64, name string, deskripsi *string, isActive bool) (*Matpel, error)
-	ExistsByNameAndFeatureIDExceptID(id uint64, featureID uint64, name string) (bool, error)
-	Delete(id uint64) error
-	GetByID(id uint64) (*Matpel, error)
+	GetByFeature(ctx context.Context, featureId uint64) ([]Matpel, error)
+	Create(ctx context.Context, featureID uint64, name string, deskripsi *string, isActive bool) (*Matpel, error)
+	ExistsByNameAndFeatureID(ctx context.Context, name string, featureID uint64) (bool, error)
+	Update(ctx context.Context, id uint64, featureID uint64, name string, deskripsi *string, isActive bool) (*Matpel, error)
+	ExistsByNameAndFeatureIDExceptID(ctx context.Context, id uint64, featureID uint64, name string) (bool, error)
+	Delete(ctx context.Context, id uint64, deletedAt time.Time) error
+	GetByID(ctx context.Context, id uint64) (*Matpel, error)
+	LockByID(ctx context.Context, id uint64) (bool, error)
+	LockByFeature(ctx context.Context, featureID uint64) error
+	DeleteByFeature(ctx context.Context, featureID uint64, deletedAt time.Time) error
+	ListDeleted(ctx context.Context) ([]Matpel, error)
+	LockDeletedByID(ctx context.Context, id uint64) (*Matpel, error)
+	Restore(ctx context.Context, id uint64) error
+	RestoreByFeature(ctx context.Context, featureID uint64) (int64, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 type matpelRepository struct {
-	db *sql.DB
+	db db.Conn
 }
 
-func NewMatpelRepository(db *sql.DB) MatpelRepository {
+func NewMatpelRepository(db db.Conn) MatpelRepository {
 	return &matpelRepository{db: db}
 }
 
-func (r *matpelRepository) GetByFeature(featureId uint64) ([]Matpel, error) {
+func (r *matpelRepository) GetByFeature(ctx context.Context, featureId uint64) ([]Matpel, error) {
 	query := `
 		SELECT id, feature_id, name, deskripsi, is_active, created_at, updated_at
 		FROM subjects
-		WHERE is_active = 1
+		WHERE is_active = TRUE AND deleted_at IS NULL
 		  AND feature_id = ?
 	`
 
-	rows, err := r.db.Query(query, featureId)
+	rows, err := r.db.QueryContext(ctx, query, featureId)
 	if err != nil {
 		return nil, err
 	}
@@ -58,24 +70,19 @@ func (r *matpelRepository) GetByFeature(featureId uint64) ([]Matpel, error) {
 	return matpels, nil
 }
 
-func (r *matpelRepository) Create(featureID uint64, name string, deskripsi *string, isActive bool) (*Matpel, error) {
+func (r *matpelRepository) Create(ctx context.Context, featureID uint64, name string, deskripsi *string, isActive bool) (*Matpel, error) {
 	query := `
 		INSERT INTO subjects (feature_id, name, deskripsi, is_active, created_at, updated_at)
 		VALUES (?, ?, ?, ?, NOW(), NOW())
 	`
 
-	res, err := r.db.Exec(query, featureID, name, deskripsi, isActive)
-	if err != nil {
-		return nil, err
-	}
-
-	id, err := res.LastInsertId()
+	id, err := r.db.InsertIDContext(ctx, query, featureID, name, deskripsi, isActive)
 	if err != nil {
 		return nil, err
 	}
 
 	subject := &Matpel{
-		ID:        uint64(id),
+		ID:        id,
 		FeatureID: featureID,
 		Name:      name,
 		Deskripsi: deskripsi,
@@ -84,38 +91,38 @@ func (r *matpelRepository) Create(featureID uint64, name string, deskripsi *stri
 	return subject, nil
 }
 
-func (r *matpelRepository) ExistsByNameAndFeatureID(name string, featureID uint64) (bool, error) {
+func (r *matpelRepository) ExistsByNameAndFeatureID(ctx context.Context, name string, featureID uint64) (bool, error) {
 	var exists bool
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM subjects 
-			WHERE feature_id = ? AND LOWER(TRIM(name)) = LOWER(TRIM(?))
+			WHERE feature_id = ? AND LOWER(TRIM(name)) = LOWER(TRIM(?)) AND deleted_at IS NULL
 		)
 	`
-	err := r.db.QueryRow(query, featureID, name).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query, featureID, name).Scan(&exists)
 	return exists, err
 }
 
-func (r *matpelRepository) Update(id uint64, featureID uint64, name string, deskripsi *string, isActive bool) (*Matpel, error) {
-	_, err := r.db.Exec(`
+func (r *matpelRepository) Update(ctx context.Context, id uint64, featureID uint64, name string, deskripsi *string, isActive bool) (*Matpel, error) {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE subjects
 		SET feature_id = ?, name = ?, deskripsi = ?, is_active = ?, updated_at = NOW()
-		WHERE id = ?`, featureID, name, deskripsi, isActive, id)
+		WHERE id = ? AND deleted_at IS NULL`, featureID, name, deskripsi, isActive, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return r.GetByID(id)
+	return r.GetByID(ctx, id)
 }
 
-func (r *matpelRepository) GetByID(id uint64) (*Matpel, error) {
+func (r *matpelRepository) GetByID(ctx context.Context, id uint64) (*Matpel, error) {
 	var m Matpel
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT id, feature_id, name, deskripsi, is_active, created_at, updated_at
-		FROM subjects WHERE id = ?`, id).
+		FROM subjects WHERE id = ? AND deleted_at IS NULL`, id).
 		Scan(&m.ID, &m.FeatureID, &m.Name, &m.Deskripsi, &m.IsActive, &m.CreatedAt, &m.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("matpel not found")
+		return nil, domain.ErrMatpelNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -123,17 +130,29 @@ func (r *matpelRepository) GetByID(id uint64) (*Matpel, error) {
 	return &m, nil
 }
 
-func (r *matpelRepository) ExistsByNameAndFeatureIDExceptID(id uint64, featureID uint64, name string) (bool, error) {
+// LockByID mengunci baris subject (FOR UPDATE) sampai transaksi selesai,
+// dipakai untuk menyerialkan pembuatan bimbel per subject
+func (r *matpelRepository) LockByID(ctx context.Context, id uint64) (bool, error) {
+	var lockedID uint64
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM subjects WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *matpelRepository) ExistsByNameAndFeatureIDExceptID(ctx context.Context, id uint64, featureID uint64, name string) (bool, error) {
 	var count int
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM subjects 
-		WHERE feature_id = ? AND name = ? AND id <> ?`, featureID, name, id).Scan(&count)
+		WHERE feature_id = ? AND name = ? AND id <> ? AND deleted_at IS NULL`, featureID, name, id).Scan(&count)
 	return count > 0, err
 }
 
-func (r *matpelRepository) Delete(id uint64) error {
-	query := `DELETE FROM subjects WHERE id = ?`
-	result, err := r.db.Exec(query, id)
+// Delete memindahkan subject ke trash (soft delete)
+func (r *matpelRepository) Delete(ctx context.Context, id uint64, deletedAt time.Time) error {
+	query := `UPDATE subjects SET deleted_at = ?, updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, deletedAt, id)
 	if err != nil {
 		return err
 	}
@@ -143,8 +162,103 @@ func (r *matpelRepository) Delete(id uint64) error {
 		return err
 	}
 	if rows == 0 {
-		return errors.New("data tidak ditemukan")
+		return domain.ErrMatpelNotFound
 	}
 
 	return nil
 }
+
+// LockByFeature mengunci semua subject aktif milik feature, supaya tidak ada
+// bimbel baru yang dibuat di bawahnya selama feature dihapus
+func (r *matpelRepository) LockByFeature(ctx context.Context, featureID uint64) error {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM subjects WHERE feature_id = ? AND deleted_at IS NULL FOR UPDATE`, featureID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		// baris tidak dipakai; cukup dibaca supaya semuanya terkunci
+	}
+	return rows.Err()
+}
+
+// DeleteByFeature ikut memindahkan subject milik feature ke trash dengan
+// deleted_at yang sama, sehingga bisa dipulihkan bersama feature-nya
+func (r *matpelRepository) DeleteByFeature(ctx context.Context, featureID uint64, deletedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE subjects SET deleted_at = ?, updated_at = NOW()
+		WHERE feature_id = ? AND deleted_at IS NULL`, deletedAt, featureID)
+	return err
+}
+
+func (r *matpelRepository) ListDeleted(ctx context.Context) ([]Matpel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, feature_id, name, deskripsi, is_active, created_at, updated_at, deleted_at
+		FROM subjects
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matpels := []Matpel{}
+	for rows.Next() {
+		var m Matpel
+		if err := rows.Scan(&m.ID, &m.FeatureID, &m.Name, &m.Deskripsi, &m.IsActive, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt); err != nil {
+			return nil, err
+		}
+		matpels = append(matpels, m)
+	}
+	return matpels, rows.Err()
+}
+
+// LockDeletedByID mengambil dan mengunci subject yang ada di trash
+func (r *matpelRepository) LockDeletedByID(ctx context.Context, id uint64) (*Matpel, error) {
+	var m Matpel
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, feature_id, name, deskripsi, is_active, created_at, updated_at, deleted_at
+		FROM subjects WHERE id = ? AND deleted_at IS NOT NULL FOR UPDATE`, id).
+		Scan(&m.ID, &m.FeatureID, &m.Name, &m.Deskripsi, &m.IsActive, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrMatpelNotInTrash
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *matpelRepository) Restore(ctx context.Context, id uint64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE subjects SET deleted_at = NULL, updated_at = NOW() WHERE id = ?`, id)
+	return err
+}
+
+// RestoreByFeature memulihkan subject yang ikut terhapus bersama feature
+// (deleted_at sama persis); subject yang dihapus sendiri tetap di trash.
+// Harus dipanggil sebelum feature-nya di-Restore.
+func (r *matpelRepository) RestoreByFeature(ctx context.Context, featureID uint64) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE subjects SET deleted_at = NULL, updated_at = NOW()
+		WHERE feature_id = ?
+		  AND deleted_at = (SELECT f.deleted_at FROM features f WHERE f.id = ?)`, featureID, featureID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PurgeDeleted menghapus permanen subject yang di-trash sebelum before dan
+// tidak lagi dirujuk bimbel mana pun (termasuk yang masih di trash)
+func (r *matpelRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM subjects
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		  AND NOT EXISTS (SELECT 1 FROM bimbels b WHERE b.subject_id = subjects.id)
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"time"
+)
+
+type PaymentNotificationRepository interface {
+	Create(ctx context.Context, n *domain.PaymentNotification) error
+	MarkResult(ctx context.Context, id uint64, result, message string) error
+	ExistsProcessed(ctx context.Context, provider, eventID string, excludeID uint64) (bool, error)
+	FindByID(ctx context.Context, id uint64) (*domain.PaymentNotification, error)
+	FindAll(ctx context.Context, result string, limit int) ([]domain.PaymentNotification, error)
+}
+
+type paymentNotificationRepository struct {
+	db db.Conn
+}
+
+func NewPaymentNotificationRepository(db db.Conn) PaymentNotificationRepository {
+	return &paymentNotificationRepository{db}
+}
+
+func (r *paymentNotificationRepository) Create(ctx context.Context, n *domain.PaymentNotification) error {
+	id, err := r.db.InsertIDContext(ctx, `
+		INSERT INTO payment_notifications
+			(provider, event_id, invoice_number, gateway_status, signature_valid, payload, result, result_message, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW())
+	`, n.Provider, n.EventID, n.InvoiceNumber, n.GatewayStatus, n.SignatureValid, n.Payload, n.Result, n.ResultMessage)
+	if err != nil {
+		return err
+	}
+	n.ID = id
+	n.CreatedAt = time.Now()
+	return nil
+}
+
+func (r *paymentNotificationRepository) MarkResult(ctx context.Context, id uint64, result, message string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE payment_notifications SET result = ?, result_message = ?, processed_at = NOW()
+		WHERE id = ?
+	`, result, message, id)
+	return err
+}
+
+// ExistsProcessed mengecek apakah event yang sama sudah pernah diterapkan ke invoice
+func (r *paymentNotificationRepository) ExistsProcessed(ctx context.Context, provider, eventID string, excludeID uint64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM payment_notifications
+			WHERE provider = ? AND event_id = ? AND result = ? AND id <> ?
+		)
+	`, provider, eventID, domain.NotificationResultProcessed, excludeID).Scan(&exists)
+	return exists, err
+}
+
+const paymentNotificationColumns = `id, provider, event_id, invoice_number, gateway_status, signature_valid,
+	payload, result, result_message, processed_at, created_at`
+
+func scanPaymentNotification(row rowScanner) (*domain.PaymentNotification, error) {
+	var (
+		n           domain.PaymentNotification
+		processedAt sql.NullTime
+	)
+	err := row.Scan(&n.ID, &n.Provider, &n.EventID, &n.InvoiceNumber, &n.GatewayStatus, &n.SignatureValid,
+		&n.Payload, &n.Result, &n.ResultMessage, &processedAt, &n.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if processedAt.Valid {
+		n.ProcessedAt = &processedAt.Time
+	}
+	return &n, nil
+}
+
+func (r *paymentNotificationRepository) FindByID(ctx context.Context, id uint64) (*domain.PaymentNotification, error) {
+	n, err := scanPaymentNotification(r.db.QueryRowContext(ctx, `
+		SELECT `+paymentNotificationColumns+` FROM payment_notifications WHERE id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotificationNotFound
+	}
+	return n, err
+}
+
+func (r *paymentNotificationRepository) FindAll(ctx context.Context, result string, limit int) ([]domain.PaymentNotification, error) {
+	query := `SELECT ` + paymentNotificationColumns + ` FROM payment_notifications`
+	args := []interface{}{}
+	if result != "" {
+		query += " WHERE result = ?"
+		args = append(args, result)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []domain.PaymentNotification{}
+	for rows.Next() {
+		n, err := scanPaymentNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *n)
+	}
+	return list, rows.Err()
+}
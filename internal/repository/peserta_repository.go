@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+)
+
+type PesertaRepository interface {
+	FindByID(ctx context.Context, id uint64) (*domain.PesertaProfile, error)
+	UpdateProfile(ctx context.Context, p *domain.PesertaProfile) error
+	IsEnrolledWithTutor(ctx context.Context, pesertaID, tutorID uint64) (bool, error)
+}
+
+type pesertaRepository struct {
+	db db.Conn
+}
+
+func NewPesertaRepository(db db.Conn) PesertaRepository {
+	return &pesertaRepository{db}
+}
+
+func (r *pesertaRepository) FindByID(ctx context.Context, id uint64) (*domain.PesertaProfile, error) {
+	var (
+		p         domain.PesertaProfile
+		birthDate sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT p.id, COALESCE(u.id, 0), COALESCE(u.name, ''), COALESCE(u.email, ''), COALESCE(p.school, ''),
+			COALESCE(p.jenjang, ''), COALESCE(p.grade, 0), p.birth_date, COALESCE(p.guardian_name, ''),
+			COALESCE(p.guardian_phone, '')
+		FROM pesertas p
+		LEFT JOIN users u ON u.peserta_id = p.id AND u.deleted_at IS NULL
+		WHERE p.id = ? AND p.is_active = TRUE
+	`, id).Scan(&p.ID, &p.UserID, &p.Name, &p.Email, &p.School, &p.Jenjang, &p.Grade, &birthDate,
+		&p.GuardianName, &p.GuardianPhone)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrPesertaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if birthDate.Valid {
+		d := birthDate.Time.Format("2006-01-02")
+		p.BirthDate = &d
+	}
+	return &p, nil
+}
+
+func (r *pesertaRepository) UpdateProfile(ctx context.Context, p *domain.PesertaProfile) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE pesertas
+		SET school = ?, jenjang = NULLIF(?, ''), grade = NULLIF(?, 0), birth_date = ?,
+			guardian_name = ?, guardian_phone = ?, updated_at = NOW()
+		WHERE id = ?
+	`, p.School, p.Jenjang, p.Grade, p.BirthDate, p.GuardianName, p.GuardianPhone, p.ID)
+	return err
+}
+
+// IsEnrolledWithTutor mengecek apakah peserta pernah/sedang terdaftar di bimbel milik tutor
+func (r *pesertaRepository) IsEnrolledWithTutor(ctx context.Context, pesertaID, tutorID uint64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM enrollments e
+			JOIN bimbels b ON b.id = e.bimbel_id
+			WHERE e.peserta_id = ? AND b.tutor_id = ? AND e.status IN (?, ?, ?)
+		)
+	`, pesertaID, tutorID, domain.EnrollmentStatusPending, domain.EnrollmentStatusActive,
+		domain.EnrollmentStatusCompleted).Scan(&exists)
+	return exists, err
+}
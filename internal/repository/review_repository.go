@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"time"
+)
+
+type ReviewRepository interface {
+	Create(ctx context.Context, rv *domain.Review) error
+	Update(ctx context.Context, id uint64, rating int, comment string) error
+	Reply(ctx context.Context, id uint64, reply string) error
+	SetHidden(ctx context.Context, id uint64, hidden bool, reason string) error
+	FindByID(ctx context.Context, id uint64) (*domain.Review, error)
+	FindByBimbel(ctx context.Context, bimbelID uint64, includeHidden bool) ([]domain.Review, error)
+}
+
+type reviewRepository struct {
+	db db.Conn
+}
+
+func NewReviewRepository(db db.Conn) ReviewRepository {
+	return &reviewRepository{db}
+}
+
+// adjustRating menambah/mengurangi agregat rating bimbel dan tutornya.
+// Agregat disimpan sebagai jumlah & banyaknya rating agar rata-rata tidak
+// perlu dihitung ulang setiap kali bimbel dibaca.
+func adjustRating(ctx context.Context, tx *db.Tx, bimbelID uint64, sumDelta int, countDelta int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE bimbels SET rating_sum = rating_sum + ?, rating_count = rating_count + ?
+		WHERE id = ?
+	`, sumDelta, countDelta, bimbelID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tutors SET rating_sum = rating_sum + ?, rating_count = rating_count + ?
+		WHERE id = (SELECT tutor_id FROM bimbels WHERE id = ?)
+	`, sumDelta, countDelta, bimbelID)
+	return err
+}
+
+func (r *reviewRepository) Create(ctx context.Context, rv *domain.Review) error {
+	// WithTx mengulang transaksi yang deadlock; percobaan berikutnya akan
+	// menemukan review yang sudah ada
+	var id uint64
+	err := r.db.WithTx(ctx, func(tx *db.Tx) error {
+		// Unique index (bimbel_id, peserta_id) tetap menjadi pengaman terakhir
+		// untuk dua request bersamaan yang sama-sama lolos pengecekan ini
+		var exists bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS(SELECT 1 FROM reviews WHERE bimbel_id = ? AND peserta_id = ?)
+		`, rv.BimbelID, rv.PesertaID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrReviewExists
+		}
+
+		id, err = tx.InsertIDContext(ctx, `
+			INSERT INTO reviews (bimbel_id, peserta_id, enrollment_id, rating, comment, is_hidden, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, FALSE, NOW(), NOW())
+		`, rv.BimbelID, rv.PesertaID, rv.EnrollmentID, rv.Rating, rv.Comment)
+		if db.IsDuplicateKey(err) {
+			return domain.ErrReviewExists
+		}
+		if err != nil {
+			return err
+		}
+
+		return adjustRating(ctx, tx, rv.BimbelID, rv.Rating, 1)
+	})
+	if err != nil {
+		return err
+	}
+	rv.ID = id
+	rv.CreatedAt = time.Now()
+	rv.UpdatedAt = rv.CreatedAt
+	return nil
+}
+
+// lockReview mengunci review dan mengembalikan data yang dibutuhkan untuk
+// menyesuaikan agregat rating
+func lockReview(ctx context.Context, tx *db.Tx, id uint64) (bimbelID uint64, rating int, hidden bool, err error) {
+	err = tx.QueryRowContext(ctx, `
+		SELECT bimbel_id, rating, is_hidden FROM reviews WHERE id = ? FOR UPDATE
+	`, id).Scan(&bimbelID, &rating, &hidden)
+	if err == sql.ErrNoRows {
+		err = domain.ErrReviewNotFound
+	}
+	return
+}
+
+func (r *reviewRepository) Update(ctx context.Context, id uint64, rating int, comment string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bimbelID, oldRating, hidden, err := lockReview(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE reviews SET rating = ?, comment = ?, updated_at = NOW() WHERE id = ?`, rating, comment, id)
+	if err != nil {
+		return err
+	}
+
+	// Review tersembunyi tidak masuk agregat
+	if !hidden {
+		if err := adjustRating(ctx, tx, bimbelID, rating-oldRating, 0); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *reviewRepository) Reply(ctx context.Context, id uint64, reply string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE reviews SET tutor_reply = ?, replied_at = NOW(), updated_at = NOW()
+		WHERE id = ? AND tutor_reply IS NULL
+	`, reply, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		if _, err := r.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return domain.ErrReviewAlreadyReplied
+	}
+	return nil
+}
+
+func (r *reviewRepository) SetHidden(ctx context.Context, id uint64, hidden bool, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bimbelID, rating, wasHidden, err := lockReview(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if !hidden {
+		reason = ""
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE reviews SET is_hidden = ?, hidden_reason = ?, updated_at = NOW() WHERE id = ?
+	`, hidden, reason, id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case hidden && !wasHidden:
+		err = adjustRating(ctx, tx, bimbelID, -rating, -1)
+	case !hidden && wasHidden:
+		err = adjustRating(ctx, tx, bimbelID, rating, 1)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const reviewColumns = `rv.id, rv.bimbel_id, rv.peserta_id, COALESCE(u.name, ''), rv.enrollment_id, rv.rating, rv.comment,
+	rv.tutor_reply, rv.replied_at, rv.is_hidden, COALESCE(rv.hidden_reason, ''), rv.created_at, rv.updated_at`
+
+func scanReview(row rowScanner) (*domain.Review, error) {
+	var (
+		rv         domain.Review
+		tutorReply sql.NullString
+		repliedAt  sql.NullTime
+	)
+	err := row.Scan(&rv.ID, &rv.BimbelID, &rv.PesertaID, &rv.PesertaName, &rv.EnrollmentID, &rv.Rating, &rv.Comment,
+		&tutorReply, &repliedAt, &rv.IsHidden, &rv.HiddenReason, &rv.CreatedAt, &rv.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if tutorReply.Valid {
+		rv.TutorReply = &tutorReply.String
+	}
+	if repliedAt.Valid {
+		rv.RepliedAt = &repliedAt.Time
+	}
+	return &rv, nil
+}
+
+func (r *reviewRepository) FindByID(ctx context.Context, id uint64) (*domain.Review, error) {
+	rv, err := scanReview(r.db.QueryRowContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM reviews rv LEFT JOIN users u ON u.peserta_id = rv.peserta_id
+		WHERE rv.id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrReviewNotFound
+	}
+	return rv, err
+}
+
+func (r *reviewRepository) FindByBimbel(ctx context.Context, bimbelID uint64, includeHidden bool) ([]domain.Review, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews rv LEFT JOIN users u ON u.peserta_id = rv.peserta_id
+		WHERE rv.bimbel_id = ?
+	`
+	if !includeHidden {
+		query += " AND rv.is_hidden = FALSE"
+	}
+	query += " ORDER BY rv.created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, bimbelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.Review{}
+	for rows.Next() {
+		rv, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *rv)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SessionRepository interface {
+	CreateSessions(ctx context.Context, tutorID uint64, rec *domain.SessionRecurrence, sessions []domain.BimbelSession) ([]domain.BimbelSession, error)
+	Update(ctx context.Context, tutorID uint64, s *domain.BimbelSession) error
+	Delete(ctx context.Context, bimbelID, id uint64) error
+	DeleteRecurrence(ctx context.Context, bimbelID, recurrenceID uint64, from time.Time) error
+	FindByID(ctx context.Context, bimbelID, id uint64) (*domain.BimbelSession, error)
+	FindByBimbel(ctx context.Context, bimbelID uint64, from, to *time.Time) ([]domain.BimbelSession, error)
+}
+
+type sessionRepository struct {
+	db db.Conn
+}
+
+func NewSessionRepository(db db.Conn) SessionRepository {
+	return &sessionRepository{db}
+}
+
+// lockTutor mengunci baris tutor agar pengecekan bentrok jadwal dan insert
+// sesi tidak balapan dengan request lain untuk tutor yang sama.
+func lockTutor(ctx context.Context, tx *db.Tx, tutorID uint64) error {
+	var id uint64
+	err := tx.QueryRowContext(ctx, `SELECT id FROM tutors WHERE id = ? FOR UPDATE`, tutorID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return domain.ErrTutorNotFound
+	}
+	return err
+}
+
+// findConflict mencari sesi lain milik tutor yang beririsan dengan [start, end)
+func findConflict(ctx context.Context, tx *db.Tx, tutorID uint64, start, end time.Time, excludeID uint64) error {
+	var (
+		conflictID    uint64
+		conflictStart time.Time
+	)
+	err := tx.QueryRowContext(ctx, `
+		SELECT s.id, s.start_at
+		FROM bimbel_sessions s
+		JOIN bimbels b ON b.id = s.bimbel_id
+		WHERE b.tutor_id = ? AND b.deleted_at IS NULL AND s.deleted_at IS NULL
+		  AND s.start_at < ? AND s.end_at > ? AND s.id <> ?
+		LIMIT 1
+	`, tutorID, end.UTC(), start.UTC(), excludeID).Scan(&conflictID, &conflictStart)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: sesi #%d pada %s", domain.ErrSessionConflict, conflictID, conflictStart.UTC().Format(time.RFC3339))
+}
+
+func (r *sessionRepository) CreateSessions(ctx context.Context, tutorID uint64, rec *domain.SessionRecurrence, sessions []domain.BimbelSession) ([]domain.BimbelSession, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockTutor(ctx, tx, tutorID); err != nil {
+		return nil, err
+	}
+
+	for _, s := range sessions {
+		if err := findConflict(ctx, tx, tutorID, s.StartAt, s.EndAt, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	var recurrenceID *uint64
+	if rec != nil {
+		weekdays := make([]string, len(rec.Weekdays))
+		for i, d := range rec.Weekdays {
+			weekdays[i] = strconv.Itoa(d)
+		}
+
+		rec.ID, err = tx.InsertIDContext(ctx, `
+			INSERT INTO bimbel_session_recurrences
+				(bimbel_id, title, weekdays, start_time, duration_minutes, timezone, start_date, end_date, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW())
+		`, rec.BimbelID, rec.Title, strings.Join(weekdays, ","), rec.StartTime, rec.DurationMinutes,
+			rec.Timezone, rec.StartDate, rec.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		rec.CreatedAt = time.Now()
+		recurrenceID = &rec.ID
+	}
+
+	created := make([]domain.BimbelSession, 0, len(sessions))
+	for _, s := range sessions {
+		id, err := tx.InsertIDContext(ctx, `
+			INSERT INTO bimbel_sessions (bimbel_id, recurrence_id, title, start_at, end_at, timezone, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())
+		`, s.BimbelID, recurrenceID, s.Title, s.StartAt.UTC(), s.EndAt.UTC(), s.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		s.ID = id
+		s.RecurrenceID = recurrenceID
+		s.CreatedAt = time.Now()
+		s.UpdatedAt = s.CreatedAt
+		created = append(created, s)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *sessionRepository) Update(ctx context.Context, tutorID uint64, s *domain.BimbelSession) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockTutor(ctx, tx, tutorID); err != nil {
+		return err
+	}
+	if err := findConflict(ctx, tx, tutorID, s.StartAt, s.EndAt, s.ID); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE bimbel_sessions SET title = ?, start_at = ?, end_at = ?, timezone = ?, updated_at = NOW()
+		WHERE id = ? AND bimbel_id = ? AND deleted_at IS NULL
+	`, s.Title, s.StartAt.UTC(), s.EndAt.UTC(), s.Timezone, s.ID, s.BimbelID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrSessionNotFound
+	}
+
+	return tx.Commit()
+}
+
+func (r *sessionRepository) Delete(ctx context.Context, bimbelID, id uint64) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE bimbel_sessions SET deleted_at = NOW()
+		WHERE id = ? AND bimbel_id = ? AND deleted_at IS NULL
+	`, id, bimbelID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteRecurrence menghapus aturan berulang beserta sesi turunannya yang
+// dimulai setelah from. Sesi yang sudah lewat tetap disimpan sebagai riwayat.
+func (r *sessionRepository) DeleteRecurrence(ctx context.Context, bimbelID, recurrenceID uint64, from time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE bimbel_session_recurrences SET deleted_at = NOW()
+		WHERE id = ? AND bimbel_id = ? AND deleted_at IS NULL
+	`, recurrenceID, bimbelID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrRecurrenceNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE bimbel_sessions SET deleted_at = NOW()
+		WHERE recurrence_id = ? AND start_at >= ? AND deleted_at IS NULL
+	`, recurrenceID, from.UTC())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const sessionColumns = `id, bimbel_id, recurrence_id, title, start_at, end_at, timezone, created_at, updated_at`
+
+func (r *sessionRepository) FindByID(ctx context.Context, bimbelID, id uint64) (*domain.BimbelSession, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+sessionColumns+`
+		FROM bimbel_sessions WHERE id = ? AND bimbel_id = ? AND deleted_at IS NULL
+	`, id, bimbelID)
+	s, err := scanSession(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *sessionRepository) FindByBimbel(ctx context.Context, bimbelID uint64, from, to *time.Time) ([]domain.BimbelSession, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM bimbel_sessions WHERE bimbel_id = ? AND deleted_at IS NULL
+	`
+	args := []interface{}{bimbelID}
+	if from != nil {
+		query += " AND end_at > ?"
+		args = append(args, from.UTC())
+	}
+	if to != nil {
+		query += " AND start_at < ?"
+		args = append(args, to.UTC())
+	}
+	query += " ORDER BY start_at ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.BimbelSession{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *s)
+	}
+	return result, rows.Err()
+}
+
+func scanSession(row rowScanner) (*domain.BimbelSession, error) {
+	var (
+		s            domain.BimbelSession
+		recurrenceID sql.NullInt64
+	)
+	err := row.Scan(&s.ID, &s.BimbelID, &recurrenceID, &s.Title, &s.StartAt, &s.EndAt,
+		&s.Timezone, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if recurrenceID.Valid {
+		id := uint64(recurrenceID.Int64)
+		s.RecurrenceID = &id
+	}
+
+	// Tampilkan waktu sesuai zona waktu sesi
+	if loc, err := time.LoadLocation(s.Timezone); err == nil {
+		s.StartAt = s.StartAt.In(loc)
+		s.EndAt = s.EndAt.In(loc)
+	}
+	return &s, nil
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"strings"
+	"time"
+)
+
+type TutorRepository interface {
+	FindByID(ctx context.Context, id uint64) (*domain.TutorProfile, error)
+	FindByVerificationStatus(ctx context.Context, status string) ([]domain.TutorProfile, error)
+	UpdateProfile(ctx context.Context, p *domain.TutorProfile) error
+	UpdateAvatar(ctx context.Context, id uint64, avatarKey string) error
+	SetSubjects(ctx context.Context, id uint64, subjectIDs []uint64) error
+	FindSubjects(ctx context.Context, id uint64) ([]domain.TutorSubject, error)
+	AddDocument(ctx context.Context, doc *domain.TutorDocument) error
+	FindDocuments(ctx context.Context, tutorID uint64) ([]domain.TutorDocument, error)
+	FindDocument(ctx context.Context, tutorID, id uint64) (*domain.TutorDocument, error)
+	SetVerificationStatus(ctx context.Context, id uint64, from []string, to string, reason string, reviewedBy *uint64) error
+	IsVerified(ctx context.Context, id uint64) (bool, error)
+	LockByID(ctx context.Context, id uint64) error
+}
+
+type tutorRepository struct {
+	db db.Conn
+}
+
+func NewTutorRepository(db db.Conn) TutorRepository {
+	return &tutorRepository{db}
+}
+
+const tutorColumns = `t.id, COALESCE(u.id, 0), COALESCE(u.name, ''), COALESCE(t.bio, ''), COALESCE(t.education, ''),
+	COALESCE(t.experience, ''), COALESCE(t.city, ''), COALESCE(t.avatar_url, ''), t.verification_status,
+	COALESCE(t.rejection_reason, ''), t.verified_at, t.rating_sum, t.rating_count`
+
+func scanTutor(row rowScanner) (*domain.TutorProfile, error) {
+	var (
+		p          domain.TutorProfile
+		verifiedAt sql.NullTime
+		ratingSum  int64
+	)
+	err := row.Scan(&p.ID, &p.UserID, &p.Name, &p.Bio, &p.Education, &p.Experience, &p.City, &p.Avatar,
+		&p.VerificationStatus, &p.RejectionReason, &verifiedAt, &ratingSum, &p.RatingCount)
+	if err != nil {
+		return nil, err
+	}
+	if verifiedAt.Valid {
+		p.VerifiedAt = &verifiedAt.Time
+	}
+	p.RatingAvg = domain.RatingAverage(ratingSum, p.RatingCount)
+	return &p, nil
+}
+
+func (r *tutorRepository) FindByID(ctx context.Context, id uint64) (*domain.TutorProfile, error) {
+	p, err := scanTutor(r.db.QueryRowContext(ctx, `
+		SELECT `+tutorColumns+`
+		FROM tutors t
+		LEFT JOIN users u ON u.tutor_id = t.id AND u.deleted_at IS NULL
+		WHERE t.id = ? AND t.is_active = TRUE
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTutorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.Subjects, err = r.FindSubjects(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *tutorRepository) FindByVerificationStatus(ctx context.Context, status string) ([]domain.TutorProfile, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+tutorColumns+`
+		FROM tutors t
+		LEFT JOIN users u ON u.tutor_id = t.id AND u.deleted_at IS NULL
+		WHERE t.verification_status = ? AND t.is_active = TRUE
+		ORDER BY t.id ASC
+	`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.TutorProfile{}
+	for rows.Next() {
+		p, err := scanTutor(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *p)
+	}
+	return result, rows.Err()
+}
+
+func (r *tutorRepository) UpdateProfile(ctx context.Context, p *domain.TutorProfile) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE tutors SET bio = ?, education = ?, experience = ?, city = ?, updated_at = NOW()
+		WHERE id = ?
+	`, p.Bio, p.Education, p.Experience, p.City, p.ID)
+	return err
+}
+
+func (r *tutorRepository) UpdateAvatar(ctx context.Context, id uint64, avatarKey string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE tutors SET avatar_url = ?, updated_at = NOW() WHERE id = ?`, avatarKey, id)
+	return err
+}
+
+// SetSubjects mengganti seluruh daftar mata pelajaran yang diajar tutor
+func (r *tutorRepository) SetSubjects(ctx context.Context, id uint64, subjectIDs []uint64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tutor_subjects WHERE tutor_id = ?`, id); err != nil {
+		return err
+	}
+	for _, subjectID := range subjectIDs {
+		_, err := tx.ExecContext(ctx, `INSERT INTO tutor_subjects (tutor_id, subject_id) VALUES (?, ?)`, id, subjectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *tutorRepository) FindSubjects(ctx context.Context, id uint64) ([]domain.TutorSubject, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s.id, s.name
+		FROM tutor_subjects ts
+		JOIN subjects s ON s.id = ts.subject_id
+		WHERE ts.tutor_id = ? AND s.deleted_at IS NULL
+		ORDER BY s.name ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.TutorSubject{}
+	for rows.Next() {
+		var s domain.TutorSubject
+		if err := rows.Scan(&s.ID, &s.Name); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+func (r *tutorRepository) AddDocument(ctx context.Context, doc *domain.TutorDocument) error {
+	id, err := r.db.InsertIDContext(ctx, `
+		INSERT INTO tutor_documents (tutor_id, doc_type, file_name, file_path, created_at)
+		VALUES (?, ?, ?, ?, NOW())
+	`, doc.TutorID, doc.DocType, doc.FileName, doc.FilePath)
+	if err != nil {
+		return err
+	}
+	doc.ID = id
+	doc.CreatedAt = time.Now()
+	return nil
+}
+
+func (r *tutorRepository) FindDocuments(ctx context.Context, tutorID uint64) ([]domain.TutorDocument, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tutor_id, doc_type, file_name, file_path, created_at
+		FROM tutor_documents WHERE tutor_id = ? ORDER BY id ASC
+	`, tutorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.TutorDocument{}
+	for rows.Next() {
+		var d domain.TutorDocument
+		if err := rows.Scan(&d.ID, &d.TutorID, &d.DocType, &d.FileName, &d.FilePath, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, d)
+	}
+	return result, rows.Err()
+}
+
+func (r *tutorRepository) FindDocument(ctx context.Context, tutorID, id uint64) (*domain.TutorDocument, error) {
+	var d domain.TutorDocument
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, tutor_id, doc_type, file_name, file_path, created_at
+		FROM tutor_documents WHERE id = ? AND tutor_id = ?
+	`, id, tutorID).Scan(&d.ID, &d.TutorID, &d.DocType, &d.FileName, &d.FilePath, &d.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTutorDocumentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// SetVerificationStatus mengubah status verifikasi hanya jika status saat ini
+// termasuk dalam from, sehingga dua admin tidak bisa memproses pengajuan yang sama
+func (r *tutorRepository) SetVerificationStatus(ctx context.Context, id uint64, from []string, to string, reason string, reviewedBy *uint64) error {
+	query := `
+		UPDATE tutors
+		SET verification_status = ?, rejection_reason = ?, verification_reviewed_by = ?, updated_at = NOW()`
+	if to == domain.TutorVerificationVerified {
+		query += `, verified_at = NOW()`
+	}
+	if to == domain.TutorVerificationPending {
+		query += `, verification_submitted_at = NOW()`
+	}
+	query += ` WHERE id = ? AND verification_status IN (?` + strings.Repeat(", ?", len(from)-1) + `)`
+
+	args := []interface{}{to, reason, reviewedBy, id}
+	for _, status := range from {
+		args = append(args, status)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		if _, err := r.FindByID(ctx, id); err != nil {
+			return err
+		}
+		if to == domain.TutorVerificationPending {
+			return domain.ErrVerificationNotSubmittable
+		}
+		return domain.ErrVerificationNotPending
+	}
+	return nil
+}
+
+func (r *tutorRepository) IsVerified(ctx context.Context, id uint64) (bool, error) {
+	var status string
+	err := r.db.QueryRowContext(ctx, `SELECT verification_status FROM tutors WHERE id = ? AND is_active = TRUE`, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return false, domain.ErrTutorNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	return status == domain.TutorVerificationVerified, nil
+}
+
+// LockByID mengunci baris tutor (FOR UPDATE) sampai transaksi selesai,
+// dipakai untuk menyerialkan pengecekan nama bimbel per tutor
+func (r *tutorRepository) LockByID(ctx context.Context, id uint64) error {
+	var lockedID uint64
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM tutors WHERE id = ? FOR UPDATE`, id).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return domain.ErrTutorNotFound
+	}
+	return err
+}
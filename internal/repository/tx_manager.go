@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"main-service/internal/db"
+)
+
+// Repositories adalah kumpulan repository yang memakai koneksi yang sama.
+// Di dalam TxManager.WithinTx semuanya terikat ke satu transaksi.
+type Repositories struct {
+	User                UserRepository
+	Feature             FeatureRepository
+	Matpel              MatpelRepository
+	Bimbel              BimbelRepository
+	Enrollment          EnrollmentRepository
+	Session             SessionRepository
+	Attendance          AttendanceRepository
+	Invoice             InvoiceRepository
+	PaymentNotification PaymentNotificationRepository
+	Review              ReviewRepository
+	Tutor               TutorRepository
+	Peserta             PesertaRepository
+	AuthSession         AuthSessionRepository
+	UserToken           UserTokenRepository
+}
+
+func NewRepositories(conn db.Conn) *Repositories {
+	return &Repositories{
+		User:                NewUserRepository(conn),
+		Feature:             NewFeatureRepository(conn),
+		Matpel:              NewMatpelRepository(conn),
+		Bimbel:              NewBimbelRepository(conn),
+		Enrollment:          NewEnrollmentRepository(conn),
+		Session:             NewSessionRepository(conn),
+		Attendance:          NewAttendanceRepository(conn),
+		Invoice:             NewInvoiceRepository(conn),
+		PaymentNotification: NewPaymentNotificationRepository(conn),
+		Review:              NewReviewRepository(conn),
+		Tutor:               NewTutorRepository(conn),
+		Peserta:             NewPesertaRepository(conn),
+		AuthSession:         NewAuthSessionRepository(conn),
+		UserToken:           NewUserTokenRepository(conn),
+	}
+}
+
+// TxManager menjalankan beberapa langkah repository sebagai satu unit kerja
+type TxManager interface {
+	// WithinTx memanggil fn dengan repository yang terikat ke satu transaksi.
+	// Error atau panic dari fn me-rollback transaksi; deadlock diulang
+	// otomatis sehingga fn bisa dipanggil lebih dari sekali.
+	WithinTx(ctx context.Context, fn func(repos *Repositories) error) error
+}
+
+type txManager struct {
+	db db.Conn
+}
+
+func NewTxManager(db db.Conn) TxManager {
+	return &txManager{db: db}
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(repos *Repositories) error) error {
+	return m.db.WithTx(ctx, func(tx *db.Tx) error {
+		return fn(NewRepositories(tx))
+	})
+}
@@ -1,9 +1,10 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 	"fmt"
+	"main-service/internal/db"
 	"main-service/internal/domain"
 )
 
@@ -17,29 +18,39 @@ type User struct {
 }
 
 type UserRepository interface {
-	FindByEmail(email string) (*domain.User, error)
-	CreateUser(user *domain.User) error
-	FindTutorIDByUserID(userID uint64) (*domain.User, error)
+	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User) error
+	FindTutorIDByUserID(ctx context.Context, userID uint64) (*domain.User, error)
+	UpdatePassword(ctx context.Context, userID uint64, hashed string) error
+	MarkEmailVerified(ctx context.Context, userID uint64) error
+	ExistsEmail(ctx context.Context, email string) (bool, error)
+	FindByID(ctx context.Context, id uint64) (*domain.User, error)
+	List(ctx context.Context, role string, includeDeleted bool) ([]domain.User, error)
+	ChangeRole(ctx context.Context, id uint64, role string) error
+	SetActive(ctx context.Context, id uint64, active bool) error
+	SoftDelete(ctx context.Context, id uint64) error
+	Restore(ctx context.Context, id uint64) error
 }
 
 type userRepository struct {
-	db *sql.DB
+	db db.Conn
 }
 
-func NewUserRepository(db *sql.DB) UserRepository {
+func NewUserRepository(db db.Conn) UserRepository {
 	return &userRepository{db}
 }
 
-func (r *userRepository) FindByEmail(email string) (*domain.User, error) {
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, name, email, password, role, is_active
+		SELECT id, name, email, password, role, tutor_id, peserta_id, is_active, email_verified_at
 		FROM users
-		WHERE email = ? AND is_active = 1 AND deleted_at IS NULL
+		WHERE email = ? AND is_active = TRUE AND deleted_at IS NULL
 	`
-	row := r.db.QueryRow(query, email)
+	row := r.db.QueryRowContext(ctx, query, email)
 
 	var user domain.User
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.IsActive)
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.TutorID, &user.PesertaID,
+		&user.IsActive, &user.EmailVerifiedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -47,66 +58,57 @@ func (r *userRepository) FindByEmail(email string) (*domain.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) CreateUser(user *domain.User) error {
-	tx, err := r.db.Begin()
-	if err != nil {
-		return err
-	}
-	defer func() {
+// createRoleProfile membuat baris tutors/pesertas yang dibutuhkan role tersebut
+func createRoleProfile(ctx context.Context, tx *db.Tx, role string) (tutorID, pesertaID sql.NullInt64, err error) {
+	switch role {
+	case domain.RoleTutor:
+		id, err := tx.InsertIDContext(ctx, `INSERT INTO tutors (is_active, created_at) VALUES (TRUE, NOW())`)
 		if err != nil {
-			tx.Rollback()
+			return tutorID, pesertaID, fmt.Errorf("gagal insert tutor: %w", err)
 		}
-	}()
+		tutorID = sql.NullInt64{Int64: int64(id), Valid: true}
+	case domain.RolePeserta:
+		id, err := tx.InsertIDContext(ctx, `INSERT INTO pesertas (is_active, created_at) VALUES (TRUE, NOW())`)
+		if err != nil {
+			return tutorID, pesertaID, fmt.Errorf("gagal insert peserta: %w", err)
+		}
+		pesertaID = sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+	return tutorID, pesertaID, nil
+}
 
+func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
 	var tutorID, pesertaID sql.NullInt64
-
-	// ==== 1️⃣ Buat relasi tutor/peserta bila diperlukan ====
-	if user.Role == "tutor" {
-		queryTutor := `INSERT INTO tutors (is_active, created_at) VALUES (1, NOW())`
-		res, err := tx.Exec(queryTutor)
+	err := r.db.WithTx(ctx, func(tx *db.Tx) error {
+		// ==== 1️⃣ Buat relasi tutor/peserta bila diperlukan ====
+		var err error
+		tutorID, pesertaID, err = createRoleProfile(ctx, tx, user.Role)
 		if err != nil {
-			return fmt.Errorf("gagal insert tutor: %v", err)
+			return err
 		}
-		lastID, _ := res.LastInsertId()
-		tutorID = sql.NullInt64{Int64: lastID, Valid: true}
-	} else if user.Role == "peserta" {
-		queryPeserta := `INSERT INTO pesertas (is_active, created_at) VALUES (1, NOW())`
-		res, err := tx.Exec(queryPeserta)
+
+		// ==== 2️⃣ Insert ke users ====
+		query := `
+			INSERT INTO users (name, email, password, role, tutor_id, peserta_id, is_active, email_verified_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, TRUE, ?, NOW())
+		`
+		id, err := tx.InsertIDContext(ctx, query,
+			user.Name,
+			user.Email,
+			user.Password,
+			user.Role,
+			tutorID,
+			pesertaID,
+			user.EmailVerifiedAt,
+		)
 		if err != nil {
-			return fmt.Errorf("gagal insert peserta: %v", err)
+			return fmt.Errorf("gagal insert user: %w", err)
 		}
-		lastID, _ := res.LastInsertId()
-		pesertaID = sql.NullInt64{Int64: lastID, Valid: true}
-	}
-
-	// ==== 2️⃣ Insert ke users ====
-	query := `
-		INSERT INTO users (name, email, password, role, tutor_id, peserta_id, is_active, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, 1, NOW())
-	`
-	res, err := tx.Exec(query,
-		user.Name,
-		user.Email,
-		user.Password,
-		user.Role,
-		tutorID,
-		pesertaID,
-	)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("gagal insert user: %v", err)
-	}
-
-	lastID, err := res.LastInsertId()
+		user.ID = id
+		return nil
+	})
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("gagal ambil user id: %v", err)
-	}
-	user.ID = uint64(lastID)
-
-	// ==== 3️⃣ Commit transaksi ====
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("gagal commit transaksi: %v", err)
+		return err
 	}
 
 	// Set nilai tutor/peserta ID ke struct user
@@ -120,46 +122,207 @@ func (r *userRepository) CreateUser(user *domain.User) error {
 	return nil
 }
 
-// func (r *userRepository) FindTutorIDByUserID(userID uint64) (*uint64, error) {
-// 	query := `
-// 		SELECT tutor_id
-// 		FROM users
-// 		WHERE id = ? AND role = 'tutor' AND deleted_at IS NULL
-// 	`
-// 	row := r.db.QueryRow(query, userID)
-
-// 	var tutorID sql.NullInt64
-// 	if err := row.Scan(&tutorID); err != nil {
-// 		if errors.Is(err, sql.ErrNoRows) {
-// 			return nil, errors.New("user tidak ditemukan atau bukan tutor")
-// 		}
-// 		return nil, err
-// 	}
-
-// 	if !tutorID.Valid {
-// 		return nil, errors.New("tutor_id kosong")
-// 	}
-
-// 	id := uint64(tutorID.Int64)
-// 	return &id, nil
-// }
-
-func (r *userRepository) FindTutorIDByUserID(userID uint64) (*domain.User, error) {
+func (r *userRepository) FindTutorIDByUserID(ctx context.Context, userID uint64) (*domain.User, error) {
 	query := `
-		SELECT id, name, email, role, tutor_id, peserta_id
+		SELECT id, name, email, role, tutor_id, peserta_id, is_active
 		FROM users
 		WHERE id = ? AND deleted_at IS NULL
 	`
-	row := r.db.QueryRow(query, userID)
+	row := r.db.QueryRowContext(ctx, query, userID)
 
 	var user domain.User
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.TutorID, &user.PesertaID)
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.TutorID, &user.PesertaID, &user.IsActive)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("user tidak ditemukan")
+			return nil, domain.ErrUserNotFound
 		}
 		return nil, err
 	}
 
 	return &user, nil
 }
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uint64, hashed string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET password = ?, updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`, hashed, userID)
+	return err
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, userID uint64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW()
+		WHERE id = ? AND deleted_at IS NULL
+	`, userID)
+	return err
+}
+
+// ExistsEmail juga memeriksa user yang sudah dihapus karena email tetap unik di tabel users
+func (r *userRepository) ExistsEmail(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE email = ?)`, email).Scan(&exists)
+	return exists, err
+}
+
+const userColumns = `id, name, email, role, tutor_id, peserta_id, is_active, email_verified_at, deleted_at`
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.TutorID, &user.PesertaID,
+		&user.IsActive, &user.EmailVerifiedAt, &user.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByID dipakai admin sehingga user yang sudah dihapus tetap bisa ditemukan
+func (r *userRepository) FindByID(ctx context.Context, id uint64) (*domain.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, err
+}
+
+func (r *userRepository) List(ctx context.Context, role string, includeDeleted bool) ([]domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE 1 = 1`
+	args := []interface{}{}
+	if role != "" {
+		query += " AND role = ?"
+		args = append(args, role)
+	}
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *user)
+	}
+	return result, rows.Err()
+}
+
+// ChangeRole memindahkan user ke role lain dalam satu transaksi: baris
+// tutors/pesertas lama dinonaktifkan dan dilepas, lalu baris untuk role baru
+// dibuat. Ditolak jika role lama masih punya bimbel atau pendaftaran aktif.
+func (r *userRepository) ChangeRole(ctx context.Context, id uint64, role string) error {
+	return r.db.WithTx(ctx, func(tx *db.Tx) error {
+		var (
+			oldRole   string
+			tutorID   sql.NullInt64
+			pesertaID sql.NullInt64
+		)
+		err := tx.QueryRowContext(ctx, `
+			SELECT role, tutor_id, peserta_id FROM users WHERE id = ? AND deleted_at IS NULL FOR UPDATE
+		`, id).Scan(&oldRole, &tutorID, &pesertaID)
+		if err == sql.ErrNoRows {
+			return domain.ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if oldRole == role {
+			return nil
+		}
+
+		if tutorID.Valid {
+			var busy bool
+			err := tx.QueryRowContext(ctx, `
+				SELECT EXISTS(SELECT 1 FROM bimbels WHERE tutor_id = ? AND status <> ? AND deleted_at IS NULL)
+			`, tutorID.Int64, domain.BimbelStatusArchived).Scan(&busy)
+			if err != nil {
+				return err
+			}
+			if busy {
+				return domain.ErrRoleChangeBlocked
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE tutors SET is_active = FALSE WHERE id = ?`, tutorID.Int64); err != nil {
+				return err
+			}
+		}
+		if pesertaID.Valid {
+			var busy bool
+			err := tx.QueryRowContext(ctx, `
+				SELECT EXISTS(SELECT 1 FROM enrollments WHERE peserta_id = ? AND status IN (?, ?))
+			`, pesertaID.Int64, domain.EnrollmentStatusPending, domain.EnrollmentStatusActive).Scan(&busy)
+			if err != nil {
+				return err
+			}
+			if busy {
+				return domain.ErrRoleChangeBlocked
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE pesertas SET is_active = FALSE WHERE id = ?`, pesertaID.Int64); err != nil {
+				return err
+			}
+		}
+
+		newTutorID, newPesertaID, err := createRoleProfile(ctx, tx, role)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE users SET role = ?, tutor_id = ?, peserta_id = ?, updated_at = NOW() WHERE id = ?
+		`, role, newTutorID, newPesertaID, id)
+		return err
+	})
+}
+
+func (r *userRepository) SetActive(ctx context.Context, id uint64, active bool) error {
+	return r.execUser(ctx, `UPDATE users SET is_active = ?, updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`, active, id)
+}
+
+func (r *userRepository) SoftDelete(ctx context.Context, id uint64) error {
+	return r.execUser(ctx, `UPDATE users SET deleted_at = NOW(), updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+func (r *userRepository) Restore(ctx context.Context, id uint64) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE users SET deleted_at = NULL, updated_at = NOW() WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		if _, err := r.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return domain.ErrUserNotDeleted
+	}
+	return nil
+}
+
+// execUser menjalankan update pada user yang belum dihapus; argumen terakhir harus id user
+func (r *userRepository) execUser(ctx context.Context, query string, args ...interface{}) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		// MySQL menghitung baris yang berubah, jadi 0 bisa berarti nilainya sudah sama
+		user, err := r.FindByID(ctx, args[len(args)-1].(uint64))
+		if err != nil {
+			return err
+		}
+		if user.DeletedAt != nil {
+			return domain.ErrUserNotFound
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"main-service/internal/db"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dialectCases berisi nilai kolom boolean sebagaimana dikirim driver masing-masing
+var dialectCases = []struct {
+	dialect          string
+	active, inactive driver.Value
+	placeholder      string
+}{
+	{db.DialectMySQL, int64(1), int64(0), "?"},
+	{db.DialectPostgres, true, false, "$1"},
+}
+
+func TestUserRepositoryScanIsActive(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx := context.Background()
+
+	for _, dc := range dialectCases {
+		t.Run(dc.dialect, func(t *testing.T) {
+			t.Run("FindByEmail", func(t *testing.T) {
+				conn, script := newFakeDB(t, dc.dialect, fakeResult{
+					columns: []string{"id", "name", "email", "password", "role", "tutor_id", "peserta_id", "is_active", "email_verified_at"},
+					rows:    [][]driver.Value{{int64(1), "Budi", "budi@example.com", "hash", "tutor", int64(7), nil, dc.active, now}},
+				})
+				user, err := NewUserRepository(conn).FindByEmail(ctx, "budi@example.com")
+				if err != nil {
+					t.Fatalf("FindByEmail: %v", err)
+				}
+				if !user.IsActive {
+					t.Error("IsActive = false, want true")
+				}
+				if user.TutorID == nil || *user.TutorID != 7 {
+					t.Errorf("TutorID = %v, want 7", user.TutorID)
+				}
+				if !strings.Contains(script.queries[0], "email = "+dc.placeholder) {
+					t.Errorf("query tidak di-rebind untuk %s:\n%s", dc.dialect, script.queries[0])
+				}
+			})
+
+			t.Run("FindTutorIDByUserID", func(t *testing.T) {
+				conn, _ := newFakeDB(t, dc.dialect, fakeResult{
+					columns: []string{"id", "name", "email", "role", "tutor_id", "peserta_id", "is_active"},
+					rows:    [][]driver.Value{{int64(2), "Sari", "sari@example.com", "peserta", nil, int64(9), dc.inactive}},
+				})
+				user, err := NewUserRepository(conn).FindTutorIDByUserID(ctx, 2)
+				if err != nil {
+					t.Fatalf("FindTutorIDByUserID: %v", err)
+				}
+				if user.IsActive {
+					t.Error("IsActive = true, want false")
+				}
+			})
+
+			t.Run("FindByID", func(t *testing.T) {
+				conn, _ := newFakeDB(t, dc.dialect, fakeResult{
+					columns: strings.Split(strings.ReplaceAll(userColumns, " ", ""), ","),
+					rows:    [][]driver.Value{{int64(3), "Admin", "admin@example.com", "admin", nil, nil, dc.active, nil, now}},
+				})
+				user, err := NewUserRepository(conn).FindByID(ctx, 3)
+				if err != nil {
+					t.Fatalf("FindByID: %v", err)
+				}
+				if !user.IsActive || user.DeletedAt == nil {
+					t.Errorf("user = %+v, want aktif dan terhapus", user)
+				}
+			})
+
+			t.Run("List", func(t *testing.T) {
+				conn, _ := newFakeDB(t, dc.dialect, fakeResult{
+					columns: strings.Split(strings.ReplaceAll(userColumns, " ", ""), ","),
+					rows: [][]driver.Value{
+						{int64(1), "A", "a@example.com", "tutor", int64(1), nil, dc.active, now, nil},
+						{int64(2), "B", "b@example.com", "peserta", nil, int64(1), dc.inactive, nil, nil},
+					},
+				})
+				users, err := NewUserRepository(conn).List(ctx, "", false)
+				if err != nil {
+					t.Fatalf("List: %v", err)
+				}
+				if len(users) != 2 || !users[0].IsActive || users[1].IsActive {
+					t.Errorf("users = %+v", users)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"main-service/internal/db"
+	"main-service/internal/domain"
+	"time"
+)
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, userID uint64, purpose, tokenHash string, expiresAt time.Time) error
+	Consume(ctx context.Context, purpose, tokenHash string) (uint64, error)
+}
+
+type userTokenRepository struct {
+	db db.Conn
+}
+
+func NewUserTokenRepository(db db.Conn) UserTokenRepository {
+	return &userTokenRepository{db}
+}
+
+// Create menyimpan hash token baru dan membatalkan token lama dengan kegunaan
+// yang sama, sehingga hanya link terakhir yang dikirim yang berlaku
+func (r *userTokenRepository) Create(ctx context.Context, userID uint64, purpose, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE user_tokens SET used_at = NOW()
+		WHERE user_id = ? AND purpose = ? AND used_at IS NULL
+	`, userID, purpose)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, NOW())
+	`, userID, purpose, tokenHash, expiresAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Consume menandai token sebagai terpakai dan mengembalikan user pemiliknya.
+// Token yang tidak ada, sudah dipakai, atau kedaluwarsa dianggap tidak valid.
+func (r *userTokenRepository) Consume(ctx context.Context, purpose, tokenHash string) (uint64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var (
+		id        uint64
+		userID    uint64
+		expiresAt time.Time
+		usedAt    sql.NullTime
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, user_id, expires_at, used_at FROM user_tokens
+		WHERE token_hash = ? AND purpose = ?
+		FOR UPDATE
+	`, tokenHash, purpose).Scan(&id, &userID, &expiresAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrUserTokenInvalid
+	}
+	if err != nil {
+		return 0, err
+	}
+	if usedAt.Valid || time.Now().After(expiresAt) {
+		return 0, domain.ErrUserTokenInvalid
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE user_tokens SET used_at = NOW() WHERE id = ?`, id); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
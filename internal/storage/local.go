@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSignatureInvalid = errors.New("storage: tanda tangan URL tidak valid")
+	ErrSignatureExpired = errors.New("storage: URL sudah kedaluwarsa")
+)
+
+// SignedURLVerifier diimplementasikan store yang signed URL-nya dilayani
+// aplikasi sendiri (local). Signed URL S3 diverifikasi oleh S3.
+type SignedURLVerifier interface {
+	VerifySignedURL(key, expires, signature string) error
+}
+
+// LocalStore menyimpan object sebagai file di bawah root. Cocok untuk satu
+// instance; untuk beberapa replica gunakan s3 atau volume bersama.
+type LocalStore struct {
+	root          string
+	publicURL     string
+	signedURLBase string
+	secret        []byte
+}
+
+func NewLocalStore(root, publicURL, signedURLBase, secret string) *LocalStore {
+	return &LocalStore{
+		root:          root,
+		publicURL:     publicURL,
+		signedURLBase: signedURLBase,
+		secret:        []byte(secret),
+	}
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put menulis ke file sementara lalu rename, sehingga pembaca tidak pernah
+// melihat file yang setengah tertulis
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	full, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(full), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op setelah rename berhasil
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), full)
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	full, err := s.path(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	f, err := os.Open(full)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, ObjectInfo{}, err
+	}
+	return f, ObjectInfo{
+		Size:        st.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(full)),
+		ModTime:     st.ModTime(),
+	}, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	full, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(full); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List menerima prefix berupa direktori, mis. "thumbnails/"; prefix kosong
+// atau yang keluar dari root ditolak seperti key pada Put/Get/Delete
+func (s *LocalStore) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	dir, err := s.path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return err
+	}
+	err = filepath.WalkDir(dir, func(full string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return ctx.Err()
+		}
+		st, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.root, full)
+		if err != nil {
+			return err
+		}
+		return fn(ObjectInfo{
+			Key:         filepath.ToSlash(rel),
+			Size:        st.Size(),
+			ContentType: mime.TypeByExtension(filepath.Ext(full)),
+			ModTime:     st.ModTime(),
+		})
+	})
+	// Direktori prefix yang belum pernah dibuat berarti belum ada object
+	if errors.Is(err, fs.ErrNotExist) && !dirExists(dir) {
+		return nil
+	}
+	return err
+}
+
+func dirExists(dir string) bool {
+	st, err := os.Stat(dir)
+	return err == nil && st.IsDir()
+}
+
+func (s *LocalStore) URL(key string) string {
+	return joinURL(s.publicURL, key)
+}
+
+// SignedURL mengarah ke endpoint aplikasi yang memanggil VerifySignedURL
+func (s *LocalStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", s.sign(key, expires))
+	return joinURL(s.signedURLBase, key) + "?" + q.Encode(), nil
+}
+
+func (s *LocalStore) VerifySignedURL(key, expires, signature string) error {
+	if err := validKey(key); err != nil {
+		return ErrSignatureInvalid
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	want, _ := hex.DecodeString(s.sign(key, expires))
+	if !hmac.Equal(sig, want) {
+		return ErrSignatureInvalid
+	}
+
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	if time.Now().Unix() > exp {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func (s *LocalStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *LocalStore {
+	t.Helper()
+	return NewLocalStore(t.TempDir(), "http://app/uploads", "http://app/api/v1/files", "rahasia")
+}
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key string
+		ok  bool
+	}{
+		{"thumbnails/bimbel_1.jpg", true},
+		{"tutor_documents/7/ktp.pdf", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"..", false},
+		{"../secret", false},
+		{"thumbnails/../../secret", false},
+		{"thumbnails/./a.jpg", false},
+		{"thumbnails//a.jpg", false},
+		{"thumbnails/", false},
+	}
+	for _, tt := range tests {
+		err := validKey(tt.key)
+		if (err == nil) != tt.ok {
+			t.Errorf("validKey(%q) err = %v, want ok=%v", tt.key, err, tt.ok)
+		}
+	}
+}
+
+func TestLocalStoreRejectsInvalidKeys(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"../escape.txt", "/abs.txt", ""} {
+		if err := s.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put(%q) berhasil, want error", key)
+		}
+		if _, _, err := s.Get(ctx, key); err == nil {
+			t.Errorf("Get(%q) berhasil, want error", key)
+		}
+		if err := s.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) berhasil, want error", key)
+		}
+		if _, err := s.SignedURL(ctx, key, time.Minute); err == nil {
+			t.Errorf("SignedURL(%q) berhasil, want error", key)
+		}
+	}
+
+	for _, prefix := range []string{"../", "..", "/", "", "thumbnails/../../"} {
+		err := s.List(ctx, prefix, func(ObjectInfo) error { return nil })
+		if err == nil {
+			t.Errorf("List(%q) berhasil, want error", prefix)
+		}
+	}
+}
+
+func TestLocalStorePutGetListDelete(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "thumbnails/a.jpg", strings.NewReader("gambar"), 6, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	// File di luar prefix tidak boleh ikut ter-list
+	if err := os.WriteFile(filepath.Join(s.root, "other.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, info, err := s.Get(ctx, "thumbnails/a.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	rc.Close()
+	if info.Size != 6 || info.ContentType != "image/jpeg" {
+		t.Errorf("info = %+v", info)
+	}
+
+	var keys []string
+	err = s.List(ctx, "thumbnails/", func(obj ObjectInfo) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "thumbnails/a.jpg" {
+		t.Errorf("keys = %v, want [thumbnails/a.jpg]", keys)
+	}
+
+	// Prefix yang belum pernah dibuat berarti kosong, bukan error
+	if err := s.List(ctx, "avatars/", func(ObjectInfo) error { return errors.New("tidak boleh dipanggil") }); err != nil {
+		t.Errorf("List prefix kosong: %v", err)
+	}
+
+	if err := s.Delete(ctx, "thumbnails/a.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete(ctx, "thumbnails/a.jpg"); err != nil {
+		t.Errorf("Delete kedua: %v, want nil", err)
+	}
+	if _, _, err := s.Get(ctx, "thumbnails/a.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get setelah Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+// signedParams memecah signed URL menjadi key, expires, dan signature
+func signedParams(t *testing.T, s *LocalStore, key string, ttl time.Duration) (string, string, string) {
+	t.Helper()
+	raw, err := s.SignedURL(context.Background(), key, ttl)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotKey := strings.TrimPrefix(u.Path, "/api/v1/files/")
+	return gotKey, u.Query().Get("expires"), u.Query().Get("signature")
+}
+
+func TestLocalStoreSignedURL(t *testing.T) {
+	s := newTestStore(t)
+	const key = "tutor_documents/7/ktp.pdf"
+
+	gotKey, expires, sig := signedParams(t, s, key, time.Minute)
+	if gotKey != key {
+		t.Fatalf("key di URL = %q, want %q", gotKey, key)
+	}
+	if err := s.VerifySignedURL(key, expires, sig); err != nil {
+		t.Errorf("URL valid ditolak: %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		key, expires, sig string
+		want              error
+	}{
+		{"key lain", "tutor_documents/8/ktp.pdf", expires, sig, ErrSignatureInvalid},
+		{"expires diubah", key, expires + "0", sig, ErrSignatureInvalid},
+		{"signature bukan hex", key, expires, "zz", ErrSignatureInvalid},
+		{"signature kosong", key, expires, "", ErrSignatureInvalid},
+		{"key keluar root", "../" + key, expires, sig, ErrSignatureInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.VerifySignedURL(tt.key, tt.expires, tt.sig); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	other := NewLocalStore(t.TempDir(), "", "http://app/api/v1/files", "rahasia-lain")
+	if err := other.VerifySignedURL(key, expires, sig); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("secret lain: err = %v, want ErrSignatureInvalid", err)
+	}
+}
+
+func TestLocalStoreSignedURLExpiry(t *testing.T) {
+	s := newTestStore(t)
+	const key = "tutor_documents/7/ktp.pdf"
+
+	_, expires, sig := signedParams(t, s, key, -time.Second)
+	if err := s.VerifySignedURL(key, expires, sig); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("URL kedaluwarsa: err = %v, want ErrSignatureExpired", err)
+	}
+
+	_, expires, sig = signedParams(t, s, key, time.Hour)
+	if err := s.VerifySignedURL(key, expires, sig); err != nil {
+		t.Errorf("URL belum kedaluwarsa ditolak: %v", err)
+	}
+}
+
+func TestNewRequiresSigningSecretForLocal(t *testing.T) {
+	if _, err := New(Config{Driver: "local", LocalDir: t.TempDir()}); err == nil {
+		t.Error("New tanpa SigningSecret berhasil, want error")
+	}
+	if _, err := New(Config{Driver: "local", LocalDir: t.TempDir(), SigningSecret: "rahasia"}); err != nil {
+		t.Errorf("New: %v", err)
+	}
+}
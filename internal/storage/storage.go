@@ -0,0 +1,107 @@
+// Package storage menyimpan file upload (thumbnail, avatar, dokumen) sebagai
+// object dengan key relatif, mis. "thumbnails/bimbel_123.jpg". Database hanya
+// menyimpan key; URL dibentuk saat response lewat BlobStore.URL/SignedURL.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var ErrNotFound = errors.New("storage: object tidak ditemukan")
+
+// PublicPrefixes adalah prefix key yang boleh diakses publik tanpa tanda
+// tangan. Object lain (mis. tutor_documents/) hanya lewat SignedURL.
+var PublicPrefixes = []string{"thumbnails/", "avatars/"}
+
+// ObjectInfo adalah metadata object hasil Get/List
+type ObjectInfo struct {
+	Key         string // hanya diisi oleh List
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// BlobStore menyimpan object berdasarkan key. Implementasi dipilih lewat STORAGE_DRIVER.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get mengembalikan ErrNotFound jika key tidak ada; pemanggil wajib Close
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	// Delete tidak mengembalikan error jika key memang sudah tidak ada
+	Delete(ctx context.Context, key string) error
+	// URL adalah URL publik permanen; hanya valid untuk PublicPrefixes
+	URL(key string) string
+	// SignedURL adalah URL sementara yang berlaku selama ttl
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// List memanggil fn untuk setiap object di bawah prefix (rekursif).
+	// Error dari fn menghentikan iterasi dan dikembalikan apa adanya.
+	List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+}
+
+type Config struct {
+	Driver    string // local | s3
+	PublicURL string // base URL untuk BlobStore.URL
+
+	// local
+	LocalDir      string
+	SignedURLBase string // endpoint yang memverifikasi signed URL lokal
+	SigningSecret string
+
+	// s3 (AWS S3, MinIO, atau layanan S3-compatible lain)
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+}
+
+// New membuat BlobStore sesuai driver; driver kosong memakai local
+func New(cfg Config) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		if cfg.SigningSecret == "" {
+			return nil, fmt.Errorf("STORAGE_SIGNING_SECRET wajib diisi untuk STORAGE_DRIVER=local")
+		}
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL, cfg.SignedURLBase, cfg.SigningSecret), nil
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT dan S3_BUCKET wajib diisi untuk STORAGE_DRIVER=s3")
+		}
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("storage driver %q tidak dikenal", cfg.Driver)
+	}
+}
+
+// NewKey membuat key unik, mis. NewKey("thumbnails", "bimbel", ".jpg")
+func NewKey(prefix, name, ext string) string {
+	return fmt.Sprintf("%s/%s_%d%s", prefix, name, time.Now().UnixNano(), ext)
+}
+
+// IsPublic mengecek apakah key berada di bawah PublicPrefixes
+func IsPublic(key string) bool {
+	for _, p := range PublicPrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// validKey menolak key absolut atau yang keluar dari root (../)
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return fmt.Errorf("storage: key tidak valid %q", key)
+	}
+	return nil
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"math/big"
+	"time"
+)
+
+const (
+	defaultCheckinCodeTTL = 10 * time.Minute
+	maxCheckinCodeTTL     = time.Hour
+	lateThreshold         = 15 * time.Minute
+
+	// maxCheckinFailures adalah jumlah kode salah per peserta per sesi
+	// sebelum check-in dikunci; kode baru dari tutor membuka kuncinya
+	maxCheckinFailures = 5
+)
+
+type AttendanceUsecase interface {
+	GenerateCheckinCode(ctx context.Context, role string, userTutorID uint64, bimbelID, sessionID uint64, ttl time.Duration) (*domain.CheckinCode, error)
+	CheckIn(ctx context.Context, role string, pesertaID uint64, bimbelID, sessionID uint64, code string) (*domain.Attendance, error)
+	Mark(ctx context.Context, role string, userTutorID, markedBy uint64, bimbelID, sessionID uint64, items []domain.Attendance) ([]domain.AttendanceRosterItem, error)
+	Roster(ctx context.Context, role string, userTutorID uint64, bimbelID, sessionID uint64) ([]domain.AttendanceRosterItem, error)
+	BimbelSummary(ctx context.Context, role string, userTutorID uint64, bimbelID uint64) (*domain.BimbelAttendanceSummary, error)
+	PesertaSummary(ctx context.Context, role string, userTutorID uint64, bimbelID, pesertaID uint64) (map[string]interface{}, error)
+}
+
+type attendanceUsecase struct {
+	repo           repository.AttendanceRepository
+	sessionRepo    repository.SessionRepository
+	enrollmentRepo repository.EnrollmentRepository
+	bimbelRepo     repository.BimbelRepository
+	tx             repository.TxManager
+}
+
+func NewAttendanceUsecase(r repository.AttendanceRepository, sr repository.SessionRepository, er repository.EnrollmentRepository, br repository.BimbelRepository, tx repository.TxManager) AttendanceUsecase {
+	return &attendanceUsecase{
+		repo:           r,
+		sessionRepo:    sr,
+		enrollmentRepo: er,
+		bimbelRepo:     br,
+		tx:             tx,
+	}
+}
+
+// authorizeManager hanya mengizinkan tutor pemilik bimbel atau admin
+func (u *attendanceUsecase) authorizeManager(ctx context.Context, role string, userTutorID uint64, bimbelID uint64) error {
+	b, err := u.bimbelRepo.FindByID(ctx, bimbelID)
+	if err != nil {
+		return err
+	}
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	return policy.Authorize(actor, policy.AttendanceManage, &policy.Resource{TutorID: b.TutorID})
+}
+
+func hashCheckinCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func (u *attendanceUsecase) GenerateCheckinCode(ctx context.Context, role string, userTutorID uint64, bimbelID, sessionID uint64, ttl time.Duration) (*domain.CheckinCode, error) {
+	if err := u.authorizeManager(ctx, role, userTutorID, bimbelID); err != nil {
+		return nil, err
+	}
+	if _, err := u.sessionRepo.FindByID(ctx, bimbelID, sessionID); err != nil {
+		return nil, err
+	}
+
+	if ttl <= 0 {
+		ttl = defaultCheckinCodeTTL
+	}
+	if ttl > maxCheckinCodeTTL {
+		return nil, domain.InvalidField("expires_in_minutes", "masa berlaku kode maksimal 60 menit")
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return nil, err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+	expiresAt := time.Now().Add(ttl)
+
+	if err := u.repo.SaveCheckinCode(ctx, sessionID, hashCheckinCode(code), expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &domain.CheckinCode{SessionID: sessionID, Code: code, ExpiresAt: expiresAt}, nil
+}
+
+func (u *attendanceUsecase) CheckIn(ctx context.Context, role string, pesertaID uint64, bimbelID, sessionID uint64, code string) (*domain.Attendance, error) {
+	if err := policy.Authorize(policy.Actor{Role: role, PesertaID: pesertaID}, policy.AttendanceCheckin, nil); err != nil {
+		return nil, err
+	}
+	if pesertaID == 0 {
+		return nil, policy.ErrForbidden
+	}
+
+	session, err := u.sessionRepo.FindByID(ctx, bimbelID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrolled, err := u.enrollmentRepo.ExistsActive(ctx, bimbelID, pesertaID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, domain.ErrNotEnrolled
+	}
+
+	var (
+		a        *domain.Attendance
+		checkErr error
+	)
+	err = u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		a, checkErr = nil, nil
+
+		// Kunci kode sesi supaya hitungan percobaan salah tidak balapan
+		codeHash, expiresAt, err := repos.Attendance.LockCheckinCode(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		failures, err := repos.Attendance.CheckinFailures(ctx, sessionID, pesertaID)
+		if err != nil {
+			return err
+		}
+		if failures >= maxCheckinFailures {
+			checkErr = domain.ErrCheckinLocked
+			return nil
+		}
+
+		now := time.Now()
+		if now.After(expiresAt) {
+			checkErr = domain.ErrCheckinCodeInvalid
+			return nil
+		}
+		if subtle.ConstantTimeCompare([]byte(codeHash), []byte(hashCheckinCode(code))) != 1 {
+			// Percobaan salah tetap di-commit, jadi error dikembalikan setelah transaksi
+			if _, err := repos.Attendance.RecordCheckinFailure(ctx, sessionID, pesertaID); err != nil {
+				return err
+			}
+			checkErr = domain.ErrCheckinCodeInvalid
+			return nil
+		}
+
+		status := domain.AttendancePresent
+		if now.After(session.StartAt.Add(lateThreshold)) {
+			status = domain.AttendanceLate
+		}
+
+		a = &domain.Attendance{
+			SessionID:   sessionID,
+			PesertaID:   pesertaID,
+			Status:      status,
+			CheckedInAt: &now,
+		}
+		return repos.Attendance.Upsert(ctx, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if checkErr != nil {
+		return nil, checkErr
+	}
+	return a, nil
+}
+
+func (u *attendanceUsecase) Mark(ctx context.Context, role string, userTutorID, markedBy uint64, bimbelID, sessionID uint64, items []domain.Attendance) ([]domain.AttendanceRosterItem, error) {
+	if err := u.authorizeManager(ctx, role, userTutorID, bimbelID); err != nil {
+		return nil, err
+	}
+	if _, err := u.sessionRepo.FindByID(ctx, bimbelID, sessionID); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, domain.InvalidField("items", "data kehadiran wajib diisi")
+	}
+
+	for _, item := range items {
+		if !domain.IsValidAttendanceStatus(item.Status) {
+			return nil, domain.ErrInvalidAttendanceStatus
+		}
+	}
+
+	// Semua item disimpan dalam satu transaksi: satu peserta yang tidak
+	// terdaftar membatalkan seluruh absensi, tidak ada perubahan setengah jalan
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		for _, item := range items {
+			enrolled, err := repos.Enrollment.ExistsActive(ctx, bimbelID, item.PesertaID)
+			if err != nil {
+				return err
+			}
+			if !enrolled {
+				return fmt.Errorf("%w: peserta_id %d", domain.ErrNotEnrolled, item.PesertaID)
+			}
+
+			a := &domain.Attendance{
+				SessionID: sessionID,
+				PesertaID: item.PesertaID,
+				Status:    item.Status,
+				Note:      item.Note,
+				MarkedBy:  &markedBy,
+			}
+			if err := repos.Attendance.Upsert(ctx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindRoster(ctx, bimbelID, sessionID)
+}
+
+func (u *attendanceUsecase) Roster(ctx context.Context, role string, userTutorID uint64, bimbelID, sessionID uint64) ([]domain.AttendanceRosterItem, error) {
+	if err := u.authorizeManager(ctx, role, userTutorID, bimbelID); err != nil {
+		return nil, err
+	}
+	if _, err := u.sessionRepo.FindByID(ctx, bimbelID, sessionID); err != nil {
+		return nil, err
+	}
+	return u.repo.FindRoster(ctx, bimbelID, sessionID)
+}
+
+func (u *attendanceUsecase) BimbelSummary(ctx context.Context, role string, userTutorID uint64, bimbelID uint64) (*domain.BimbelAttendanceSummary, error) {
+	if err := u.authorizeManager(ctx, role, userTutorID, bimbelID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pesertas, err := u.repo.Summarize(ctx, bimbelID, now)
+	if err != nil {
+		return nil, err
+	}
+	totalSessions, err := u.repo.CountSessions(ctx, bimbelID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.BimbelAttendanceSummary{
+		BimbelID:      bimbelID,
+		TotalSessions: totalSessions,
+		Pesertas:      pesertas,
+	}
+
+	var attended, expected int
+	for _, p := range pesertas {
+		attended += p.Present + p.Late
+		expected += p.TotalSessions
+	}
+	if expected > 0 {
+		summary.Rate = float64(attended) / float64(expected)
+	}
+	return summary, nil
+}
+
+func (u *attendanceUsecase) PesertaSummary(ctx context.Context, role string, userTutorID uint64, bimbelID, pesertaID uint64) (map[string]interface{}, error) {
+	if err := u.authorizeManager(ctx, role, userTutorID, bimbelID); err != nil {
+		return nil, err
+	}
+
+	summaries, err := u.repo.Summarize(ctx, bimbelID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var summary *domain.AttendanceSummary
+	for i := range summaries {
+		if summaries[i].PesertaID == pesertaID {
+			summary = &summaries[i]
+			break
+		}
+	}
+	if summary == nil {
+		return nil, domain.ErrNotEnrolled
+	}
+
+	records, err := u.repo.FindByPeserta(ctx, bimbelID, pesertaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"summary": summary,
+		"records": records,
+	}, nil
+}
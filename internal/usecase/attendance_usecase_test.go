@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/repository"
+	"testing"
+	"time"
+)
+
+type fakeSessionRepo struct {
+	repository.SessionRepository
+	session domain.BimbelSession
+	listed  bool
+}
+
+func (f *fakeSessionRepo) FindByID(ctx context.Context, bimbelID, id uint64) (*domain.BimbelSession, error) {
+	s := f.session
+	return &s, nil
+}
+
+func (f *fakeSessionRepo) FindByBimbel(ctx context.Context, bimbelID uint64, from, to *time.Time) ([]domain.BimbelSession, error) {
+	f.listed = true
+	return []domain.BimbelSession{f.session}, nil
+}
+
+type fakeAttendanceRepo struct {
+	repository.AttendanceRepository
+	codeHash  string
+	expiresAt time.Time
+	failures  map[uint64]int
+	upserted  []domain.Attendance
+}
+
+func (f *fakeAttendanceRepo) LockCheckinCode(ctx context.Context, sessionID uint64) (string, time.Time, error) {
+	return f.codeHash, f.expiresAt, nil
+}
+
+func (f *fakeAttendanceRepo) CheckinFailures(ctx context.Context, sessionID, pesertaID uint64) (int, error) {
+	return f.failures[pesertaID], nil
+}
+
+func (f *fakeAttendanceRepo) RecordCheckinFailure(ctx context.Context, sessionID, pesertaID uint64) (int, error) {
+	f.failures[pesertaID]++
+	return f.failures[pesertaID], nil
+}
+
+func (f *fakeAttendanceRepo) Upsert(ctx context.Context, a *domain.Attendance) error {
+	f.upserted = append(f.upserted, *a)
+	return nil
+}
+
+func newCheckinFixture(expiresAt time.Time) (*attendanceUsecase, *fakeAttendanceRepo) {
+	att := &fakeAttendanceRepo{
+		codeHash:  hashCheckinCode("123456"),
+		expiresAt: expiresAt,
+		failures:  map[uint64]int{},
+	}
+	u := &attendanceUsecase{
+		repo:           att,
+		sessionRepo:    &fakeSessionRepo{session: domain.BimbelSession{ID: 1, BimbelID: 1, StartAt: time.Now()}},
+		enrollmentRepo: &fakeEnrollmentRepo{active: true},
+		tx:             &fakeTx{repos: &repository.Repositories{Attendance: att}},
+	}
+	return u, att
+}
+
+func TestCheckInLocksAfterMaxFailures(t *testing.T) {
+	u, att := newCheckinFixture(time.Now().Add(time.Hour))
+	ctx := context.Background()
+
+	for i := 0; i < maxCheckinFailures; i++ {
+		_, err := u.CheckIn(ctx, domain.RolePeserta, 7, 1, 1, "000000")
+		if !errors.Is(err, domain.ErrCheckinCodeInvalid) {
+			t.Fatalf("attempt %d: err = %v, want ErrCheckinCodeInvalid", i+1, err)
+		}
+	}
+	if att.failures[7] != maxCheckinFailures {
+		t.Fatalf("failures = %d, want %d", att.failures[7], maxCheckinFailures)
+	}
+
+	// Kode yang benar pun ditolak setelah terkunci
+	if _, err := u.CheckIn(ctx, domain.RolePeserta, 7, 1, 1, "123456"); !errors.Is(err, domain.ErrCheckinLocked) {
+		t.Fatalf("err = %v, want ErrCheckinLocked", err)
+	}
+	if len(att.upserted) != 0 {
+		t.Fatalf("attendance tersimpan walau terkunci: %+v", att.upserted)
+	}
+
+	// Peserta lain di sesi yang sama tidak ikut terkunci
+	if _, err := u.CheckIn(ctx, domain.RolePeserta, 8, 1, 1, "123456"); err != nil {
+		t.Fatalf("peserta lain: err = %v", err)
+	}
+}
+
+func TestCheckInSuccessBelowLimit(t *testing.T) {
+	u, att := newCheckinFixture(time.Now().Add(time.Hour))
+	ctx := context.Background()
+
+	for i := 0; i < maxCheckinFailures-1; i++ {
+		_, _ = u.CheckIn(ctx, domain.RolePeserta, 7, 1, 1, "999999")
+	}
+	a, err := u.CheckIn(ctx, domain.RolePeserta, 7, 1, 1, "123456")
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if a.Status != domain.AttendancePresent || len(att.upserted) != 1 {
+		t.Fatalf("attendance = %+v, upserted = %d", a, len(att.upserted))
+	}
+}
+
+func TestCheckInExpiredCodeIsNotCounted(t *testing.T) {
+	u, att := newCheckinFixture(time.Now().Add(-time.Minute))
+
+	_, err := u.CheckIn(context.Background(), domain.RolePeserta, 7, 1, 1, "000000")
+	if !errors.Is(err, domain.ErrCheckinCodeInvalid) {
+		t.Fatalf("err = %v, want ErrCheckinCodeInvalid", err)
+	}
+	if att.failures[7] != 0 {
+		t.Fatalf("kode kedaluwarsa ikut dihitung: %d", att.failures[7])
+	}
+}
+
+func (f *fakeAttendanceRepo) FindRoster(ctx context.Context, bimbelID, sessionID uint64) ([]domain.AttendanceRosterItem, error) {
+	roster := make([]domain.AttendanceRosterItem, 0, len(f.upserted))
+	for _, a := range f.upserted {
+		roster = append(roster, domain.AttendanceRosterItem{PesertaID: a.PesertaID, Status: a.Status})
+	}
+	return roster, nil
+}
+
+func newMarkFixture(inactive map[uint64]bool) (*attendanceUsecase, *fakeAttendanceRepo, *fakeTx) {
+	att := &fakeAttendanceRepo{}
+	enrollments := &fakeEnrollmentRepo{active: true, inactive: inactive}
+	tx := &fakeTx{repos: &repository.Repositories{Attendance: att, Enrollment: enrollments}}
+	u := &attendanceUsecase{
+		repo:           att,
+		sessionRepo:    &fakeSessionRepo{session: domain.BimbelSession{ID: 1, BimbelID: 1, StartAt: time.Now()}},
+		enrollmentRepo: enrollments,
+		bimbelRepo:     &fakeBimbelRepo{bimbel: domain.Bimbel{ID: 1, TutorID: 4}},
+		tx:             tx,
+	}
+	return u, att, tx
+}
+
+func TestMarkSavesAllItemsInOneTx(t *testing.T) {
+	u, att, tx := newMarkFixture(nil)
+	items := []domain.Attendance{
+		{PesertaID: 7, Status: domain.AttendancePresent},
+		{PesertaID: 8, Status: domain.AttendanceExcused, Note: "sakit"},
+	}
+
+	roster, err := u.Mark(context.Background(), domain.RoleTutor, 4, 10, 1, 1, items)
+	if err != nil {
+		t.Fatalf("Mark: %v", err)
+	}
+	if tx.calls != 1 {
+		t.Errorf("WithinTx dipanggil %d kali, want 1", tx.calls)
+	}
+	if len(att.upserted) != 2 || len(roster) != 2 {
+		t.Errorf("upserted = %d, roster = %d, want 2", len(att.upserted), len(roster))
+	}
+}
+
+func TestMarkRejectsUnenrolledPesertaInsideTx(t *testing.T) {
+	u, _, tx := newMarkFixture(map[uint64]bool{8: true})
+	items := []domain.Attendance{
+		{PesertaID: 7, Status: domain.AttendancePresent},
+		{PesertaID: 8, Status: domain.AttendancePresent},
+	}
+
+	_, err := u.Mark(context.Background(), domain.RoleTutor, 4, 10, 1, 1, items)
+	if !errors.Is(err, domain.ErrNotEnrolled) {
+		t.Fatalf("err = %v, want ErrNotEnrolled", err)
+	}
+	if tx.calls != 1 {
+		t.Errorf("WithinTx dipanggil %d kali, want 1 (rollback seluruh item)", tx.calls)
+	}
+}
@@ -1,93 +1,426 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"main-service/internal/domain"
+	"main-service/internal/policy"
 	"main-service/internal/repository"
+	"time"
 )
 
 type BimbelUsecase interface {
-	Create(role string, userTutorID uint64, req *domain.Bimbel) error
-	Update(role string, userTutorID uint64, req *domain.Bimbel) error
-	Delete(role string, userTutorID uint64, id uint64) error
-	FindByID(role string, userTutorID uint64, id uint64) (*domain.Bimbel, error)
-	IsDuplicateName(name string, tutorID uint64) (bool, error)
+	AuthorizeCreate(role string, userTutorID uint64, tutorID uint64) error
+	Create(ctx context.Context, role string, actorID uint64, userTutorID uint64, req *domain.Bimbel) error
+	FindForUpdate(ctx context.Context, role string, userTutorID uint64, id uint64) (*domain.Bimbel, error)
+	Update(ctx context.Context, role string, userTutorID uint64, req *domain.Bimbel) error
+	Delete(ctx context.Context, role string, userTutorID uint64, id uint64) error
+	FindByID(ctx context.Context, role string, userTutorID uint64, id uint64) (*domain.Bimbel, error)
+	Catalog(ctx context.Context, filter domain.BimbelFilter) (*domain.BimbelPage, error)
+	Trash(ctx context.Context, role string) ([]domain.Bimbel, error)
+	Restore(ctx context.Context, role string, id uint64) (*domain.Bimbel, error)
+	ChangeStatus(ctx context.Context, role string, actorID uint64, userTutorID uint64, id uint64, action domain.BimbelAction, reason string) (*domain.Bimbel, error)
+	StatusHistory(ctx context.Context, role string, userTutorID uint64, id uint64) ([]domain.BimbelStatusChange, error)
+	CloseFinished(ctx context.Context) (int, error)
 }
 
 type bimbelUsecase struct {
 	repo repository.BimbelRepository
+	tx   repository.TxManager
+
+	// moderation true berarti draft harus diajukan dan disetujui admin
+	// sebelum bisa dipublikasikan
+	moderation bool
+}
+
+func NewBimbelUsecase(r repository.BimbelRepository, tx repository.TxManager, moderation bool) BimbelUsecase {
+	return &bimbelUsecase{repo: r, tx: tx, moderation: moderation}
 }
 
-func NewBimbelUsecase(r repository.BimbelRepository) BimbelUsecase {
-	return &bimbelUsecase{repo: r}
+// AuthorizeCreate mengecek izin membuat bimbel untuk tutorID tanpa IO, supaya
+// handler bisa menolak request sebelum thumbnail diproses dan disimpan
+func (u *bimbelUsecase) AuthorizeCreate(role string, userTutorID uint64, tutorID uint64) error {
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	return policy.Authorize(actor, policy.BimbelCreate, &policy.Resource{TutorID: tutorID})
 }
 
-func (u *bimbelUsecase) Create(role string, userTutorID uint64, req *domain.Bimbel) error {
-	if req.SubjectID == 0 || req.Thumbnail == "" || req.Deskripsi == "" || req.Harga <= 0 {
-		return errors.New("all required fields must be filled")
+// Create menyimpan bimbel baru sebagai draft; bimbel baru tampil di katalog
+// setelah dipublikasikan lewat ChangeStatus
+func (u *bimbelUsecase) Create(ctx context.Context, role string, actorID uint64, userTutorID uint64, req *domain.Bimbel) error {
+	if req.SubjectID == 0 || req.Thumbnail == "" || req.Deskripsi == "" || req.Harga < 0 {
+		return domain.Validation("all required fields must be filled", nil)
 	}
 
-	if role == "tutor" {
-		req.TutorID = userTutorID
-	} else if role != "admin" {
-		return errors.New("forbidden")
+	if err := u.AuthorizeCreate(role, userTutorID, req.TutorID); err != nil {
+		return err
 	}
 
-	exists, _ := u.repo.ExistsDuplicate(req.Name, req.FeatureID, req.SubjectID, nil)
-	if exists {
-		return errors.New("duplicate bimbel name for this feature and subject")
+	req.Status = domain.BimbelStatusDraft
+
+	return u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		// Kunci subject lalu tutor supaya cek duplikat dan insert tidak
+		// balapan dengan request lain untuk subject / tutor yang sama.
+		// Unique index (tutor_id, name) tetap menjaga jika ada jalur lain.
+		if err := lockSubject(ctx, repos, req.SubjectID); err != nil {
+			return err
+		}
+		if err := repos.Tutor.LockByID(ctx, req.TutorID); err != nil {
+			return err
+		}
+
+		exists, err := repos.Bimbel.ExistsDuplicate(ctx, req.Name, req.FeatureID, req.SubjectID, nil)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrBimbelDuplicate
+		}
+		taken, err := repos.Bimbel.ExistsByNameAndTutor(ctx, req.Name, req.TutorID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return domain.ErrBimbelNameTaken
+		}
+
+		if err := repos.Bimbel.Create(ctx, req); err != nil {
+			return err
+		}
+
+		return repos.Bimbel.RecordStatusChange(ctx, &domain.BimbelStatusChange{
+			BimbelID:    req.ID,
+			ToStatus:    domain.BimbelStatusDraft,
+			ActorUserID: &actorID,
+			ActorRole:   role,
+		})
+	})
+}
+
+func (u *bimbelUsecase) Update(ctx context.Context, role string, userTutorID uint64, req *domain.Bimbel) error {
+	return u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if err := lockSubject(ctx, repos, req.SubjectID); err != nil {
+			return err
+		}
+
+		existing, err := repos.Bimbel.FindByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		actor := policy.Actor{Role: role, TutorID: userTutorID}
+		if err := policy.Authorize(actor, policy.BimbelUpdate, &policy.Resource{TutorID: existing.TutorID}); err != nil {
+			return err
+		}
+		if !domain.BimbelEditable(existing.Status) {
+			return domain.ErrBimbelNotEditable
+		}
+
+		exists, err := repos.Bimbel.ExistsDuplicate(ctx, req.Name, req.FeatureID, req.SubjectID, &req.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrBimbelDuplicate
+		}
+
+		return repos.Bimbel.Update(ctx, req)
+	})
+}
+
+// FindForUpdate mengambil bimbel yang boleh diubah actor: pemilik atau admin,
+// dan statusnya masih editable. Dipakai handler sebelum memproses upload.
+func (u *bimbelUsecase) FindForUpdate(ctx context.Context, role string, userTutorID uint64, id uint64) (*domain.Bimbel, error) {
+	b, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	if !req.IsActive {
-		req.IsActive = true
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	if err := policy.Authorize(actor, policy.BimbelUpdate, &policy.Resource{TutorID: b.TutorID}); err != nil {
+		return nil, err
 	}
+	if !domain.BimbelEditable(b.Status) {
+		return nil, domain.ErrBimbelNotEditable
+	}
+	return b, nil
+}
 
-	return u.repo.Create(req)
+func lockSubject(ctx context.Context, repos *repository.Repositories, subjectID uint64) error {
+	found, err := repos.Matpel.LockByID(ctx, subjectID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return domain.InvalidField("subject_id", "subject_id tidak ditemukan")
+	}
+	return nil
 }
 
-func (u *bimbelUsecase) Update(role string, userTutorID uint64, req *domain.Bimbel) error {
-	existing, err := u.repo.FindByID(req.ID)
+func (u *bimbelUsecase) Delete(ctx context.Context, role string, userTutorID uint64, id uint64) error {
+	b, err := u.repo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	if role == "tutor" && existing.TutorID != userTutorID {
-		return errors.New("unauthorized")
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	if err := policy.Authorize(actor, policy.BimbelDelete, &policy.Resource{TutorID: b.TutorID}); err != nil {
+		return err
+	}
+
+	return u.repo.Delete(ctx, id)
+}
+
+func (u *bimbelUsecase) FindByID(ctx context.Context, role string, userTutorID uint64, id uint64) (*domain.Bimbel, error) {
+	b, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	exists, _ := u.repo.ExistsDuplicate(req.Name, req.FeatureID, req.SubjectID, &req.ID)
-	if exists {
-		return errors.New("duplicate bimbel name for this feature and subject")
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	res := &policy.Resource{TutorID: b.TutorID}
+	if err := policy.Authorize(actor, policy.BimbelView, res); err != nil {
+		return nil, err
 	}
 
-	return u.repo.Update(req)
+	// Draft, review, dan arsip hanya terlihat oleh pemilik dan admin
+	if !domain.BimbelVisible(b.Status) && policy.Authorize(actor, policy.BimbelUpdate, res) != nil {
+		return nil, domain.ErrBimbelNotFound
+	}
+	return b, nil
 }
 
-func (u *bimbelUsecase) Delete(role string, userTutorID uint64, id uint64) error {
-	b, err := u.repo.FindByID(id)
+func (u *bimbelUsecase) Catalog(ctx context.Context, filter domain.BimbelFilter) (*domain.BimbelPage, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = domain.BimbelSortNewest
+	case domain.BimbelSortNewest, domain.BimbelSortPriceAsc, domain.BimbelSortPriceDesc, domain.BimbelSortName:
+	default:
+		return nil, domain.InvalidField("sort", "sort harus salah satu dari newest, price_asc, price_desc, name")
+	}
+
+	if filter.MinHarga != nil && filter.MaxHarga != nil && *filter.MinHarga > *filter.MaxHarga {
+		return nil, domain.InvalidField("min_harga", "min_harga tidak boleh lebih besar dari max_harga")
+	}
+
+	items, total, err := u.repo.List(ctx, filter)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	totalPages := int((total + int64(filter.Limit) - 1) / int64(filter.Limit))
+	return &domain.BimbelPage{
+		Items:      items,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (u *bimbelUsecase) Trash(ctx context.Context, role string) ([]domain.Bimbel, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.BimbelTrash, nil); err != nil {
+		return nil, err
 	}
+	return u.repo.ListDeleted(ctx)
+}
+
+// Restore memulihkan bimbel dari trash; feature dan subject-nya harus aktif
+func (u *bimbelUsecase) Restore(ctx context.Context, role string, id uint64) (*domain.Bimbel, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.BimbelTrash, nil); err != nil {
+		return nil, err
+	}
+
+	var restored *domain.Bimbel
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		b, err := repos.Bimbel.LockDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	if role == "tutor" && b.TutorID != userTutorID {
-		return errors.New("unauthorized")
+		// Urutan kunci feature -> subject sama dengan saat feature dihapus
+		if err := repos.Feature.LockByID(ctx, b.FeatureID); err != nil {
+			if errors.Is(err, domain.ErrFeatureNotFound) {
+				return domain.ErrBimbelParentInTrash
+			}
+			return err
+		}
+		found, err := repos.Matpel.LockByID(ctx, b.SubjectID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return domain.ErrBimbelParentInTrash
+		}
+
+		dup, err := repos.Bimbel.ExistsDuplicate(ctx, b.Name, b.FeatureID, b.SubjectID, &b.ID)
+		if err != nil {
+			return err
+		}
+		if dup {
+			return domain.ErrBimbelDuplicate
+		}
+		taken, err := repos.Bimbel.ExistsByNameAndTutor(ctx, b.Name, b.TutorID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return domain.ErrBimbelNameTaken
+		}
+
+		if err := repos.Bimbel.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		restored, err = repos.Bimbel.FindByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return restored, nil
+}
+
+// ChangeStatus menjalankan aksi siklus hidup bimbel. Status asal dicek
+// terhadap action.From setelah baris dikunci; di sini juga dicek siapa yang
+// boleh melakukannya:
+//   - pemilik & admin boleh semua transisi yang sah
+//   - pending_review -> published hanya admin (moderasi)
+//   - draft -> published oleh pemilik ditolak jika moderasi aktif
+//   - submit & publish hanya untuk tutor yang sudah diverifikasi
+//   - publish ditolak selama kuota peserta masih penuh
+func (u *bimbelUsecase) ChangeStatus(ctx context.Context, role string, actorID uint64, userTutorID uint64, id uint64, action domain.BimbelAction, reason string) (*domain.Bimbel, error) {
+	actor := policy.Actor{UserID: actorID, Role: role, TutorID: userTutorID}
+	to := action.To
+
+	var updated *domain.Bimbel
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		b, err := repos.Bimbel.LockByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := policy.Authorize(actor, policy.BimbelUpdate, &policy.Resource{TutorID: b.TutorID}); err != nil {
+			return err
+		}
+		if !action.Allows(b.Status) {
+			return domain.ErrInvalidBimbelTransition
+		}
 
-	return u.repo.Delete(id)
+		if to == domain.BimbelStatusPublished {
+			moderator := policy.Authorize(actor, policy.BimbelModerate, nil) == nil
+			switch b.Status {
+			case domain.BimbelStatusPendingReview:
+				if !moderator {
+					return policy.ErrForbidden
+				}
+			case domain.BimbelStatusDraft:
+				if u.moderation && !moderator {
+					return domain.ErrBimbelReviewRequired
+				}
+			}
+		}
+
+		// Bimbel yang kuotanya masih penuh tidak boleh menerima pendaftaran
+		// lagi; kursi dihitung di bawah lock bimbel seperti saat Enroll
+		if to == domain.BimbelStatusPublished && b.LimitPeserta > 0 {
+			taken, err := repos.Enrollment.CountTakenSeats(ctx, id)
+			if err != nil {
+				return err
+			}
+			if taken >= b.LimitPeserta {
+				return domain.ErrBimbelFull
+			}
+		}
+
+		// Hanya tutor yang sudah diverifikasi admin yang boleh mempublikasikan bimbel
+		if to == domain.BimbelStatusPendingReview || to == domain.BimbelStatusPublished {
+			verified, err := repos.Tutor.IsVerified(ctx, b.TutorID)
+			if err != nil {
+				return err
+			}
+			if !verified {
+				return domain.ErrTutorNotVerified
+			}
+		}
+
+		if err := repos.Bimbel.UpdateStatus(ctx, id, b.Status, to); err != nil {
+			return err
+		}
+		err = repos.Bimbel.RecordStatusChange(ctx, &domain.BimbelStatusChange{
+			BimbelID:    id,
+			FromStatus:  b.Status,
+			ToStatus:    to,
+			ActorUserID: &actorID,
+			ActorRole:   role,
+			Reason:      reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		updated, err = repos.Bimbel.FindByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
-func (u *bimbelUsecase) FindByID(role string, userTutorID uint64, id uint64) (*domain.Bimbel, error) {
-	b, err := u.repo.FindByID(id)
+// StatusHistory menampilkan riwayat status bimbel ke pemilik dan admin
+func (u *bimbelUsecase) StatusHistory(ctx context.Context, role string, userTutorID uint64, id uint64) ([]domain.BimbelStatusChange, error) {
+	b, err := u.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if role == "tutor" && b.TutorID != userTutorID {
-		return nil, errors.New("unauthorized")
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	if err := policy.Authorize(actor, policy.BimbelUpdate, &policy.Resource{TutorID: b.TutorID}); err != nil {
+		return nil, err
 	}
-	return b, nil
+
+	return u.repo.StatusHistory(ctx, id)
 }
 
-func (u *bimbelUsecase) IsDuplicateName(name string, tutorID uint64) (bool, error) {
-	return u.repo.ExistsByNameAndTutor(name, tutorID)
+// CloseFinished menutup bimbel published yang semua sesinya sudah lewat.
+// Dipanggil job berkala; bimbel yang statusnya sudah berubah dilewati.
+func (u *bimbelUsecase) CloseFinished(ctx context.Context) (int, error) {
+	ids, err := u.repo.FindFinishedPublished(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, id := range ids {
+		err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+			if err := repos.Bimbel.UpdateStatus(ctx, id, domain.BimbelStatusPublished, domain.BimbelStatusClosed); err != nil {
+				return err
+			}
+			return repos.Bimbel.RecordStatusChange(ctx, &domain.BimbelStatusChange{
+				BimbelID:   id,
+				FromStatus: domain.BimbelStatusPublished,
+				ToStatus:   domain.BimbelStatusClosed,
+				ActorRole:  domain.BimbelActorSystem,
+				Reason:     domain.BimbelReasonFinished,
+			})
+		})
+		if errors.Is(err, domain.ErrInvalidBimbelTransition) {
+			continue
+		}
+		if err != nil {
+			return closed, err
+		}
+		closed++
+	}
+	return closed, nil
 }
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"testing"
+)
+
+func TestBimbelTrashAndRestoreAuthorize(t *testing.T) {
+	tests := []struct {
+		role    string
+		allowed bool
+	}{
+		{domain.RoleAdmin, true},
+		{domain.RoleTutor, false},
+		{domain.RolePeserta, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			bimbels := &fakeBimbelRepo{deleted: []domain.Bimbel{{ID: 1, TutorID: 4}}}
+			tx := &fakeTx{repos: &repository.Repositories{Bimbel: bimbels}}
+			u := &bimbelUsecase{repo: bimbels, tx: tx}
+
+			items, err := u.Trash(context.Background(), tt.role)
+			if tt.allowed {
+				if err != nil || len(items) != 1 {
+					t.Errorf("Trash = %v, %v; want 1 item", items, err)
+				}
+			} else if !errors.Is(err, policy.ErrForbidden) {
+				t.Errorf("Trash err = %v, want ErrForbidden", err)
+			}
+
+			// Id yang tidak ada di trash: admin sampai ke repository, role lain berhenti di policy
+			_, err = u.Restore(context.Background(), tt.role, 99)
+			if tt.allowed {
+				if !errors.Is(err, domain.ErrBimbelNotFound) || tx.calls != 1 {
+					t.Errorf("Restore err = %v, tx.calls = %d; want ErrBimbelNotFound, 1", err, tx.calls)
+				}
+			} else if !errors.Is(err, policy.ErrForbidden) || tx.calls != 0 {
+				t.Errorf("Restore err = %v, tx.calls = %d; want ErrForbidden, 0", err, tx.calls)
+			}
+		})
+	}
+}
+
+func TestBimbelChangeStatusChecksSourceState(t *testing.T) {
+	const (
+		ownerUserID  = 10
+		ownerTutorID = 4
+	)
+	tests := []struct {
+		name    string
+		status  string
+		action  domain.BimbelAction
+		role    string
+		limit   int
+		taken   int
+		wantErr error
+		want    string
+	}{
+		{"withdraw pending_review", domain.BimbelStatusPendingReview, domain.BimbelActionWithdraw, domain.RoleTutor, 0, 0, nil, domain.BimbelStatusDraft},
+		{"withdraw archived", domain.BimbelStatusArchived, domain.BimbelActionWithdraw, domain.RoleTutor, 0, 0, domain.ErrInvalidBimbelTransition, ""},
+		{"reject pending_review", domain.BimbelStatusPendingReview, domain.BimbelActionReject, domain.RoleAdmin, 0, 0, nil, domain.BimbelStatusDraft},
+		{"reject archived", domain.BimbelStatusArchived, domain.BimbelActionReject, domain.RoleAdmin, 0, 0, domain.ErrInvalidBimbelTransition, ""},
+		{"unarchive archived", domain.BimbelStatusArchived, domain.BimbelActionUnarchive, domain.RoleTutor, 0, 0, nil, domain.BimbelStatusDraft},
+		{"unarchive pending_review", domain.BimbelStatusPendingReview, domain.BimbelActionUnarchive, domain.RoleTutor, 0, 0, domain.ErrInvalidBimbelTransition, ""},
+		{"close draft", domain.BimbelStatusDraft, domain.BimbelActionClose, domain.RoleTutor, 0, 0, domain.ErrInvalidBimbelTransition, ""},
+		{"publish closed", domain.BimbelStatusClosed, domain.BimbelActionPublish, domain.RoleTutor, 0, 0, nil, domain.BimbelStatusPublished},
+		{"publish closed ada kursi", domain.BimbelStatusClosed, domain.BimbelActionPublish, domain.RoleTutor, 10, 9, nil, domain.BimbelStatusPublished},
+		{"publish closed masih penuh", domain.BimbelStatusClosed, domain.BimbelActionPublish, domain.RoleTutor, 10, 10, domain.ErrBimbelFull, ""},
+		{"admin publish closed masih penuh", domain.BimbelStatusClosed, domain.BimbelActionPublish, domain.RoleAdmin, 10, 12, domain.ErrBimbelFull, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bimbels := &fakeBimbelRepo{bimbel: domain.Bimbel{ID: 1, TutorID: ownerTutorID, Status: tt.status, LimitPeserta: tt.limit}}
+			tx := &fakeTx{repos: &repository.Repositories{
+				Bimbel:     bimbels,
+				Tutor:      &fakeTutorRepo{verified: true},
+				Enrollment: &fakeEnrollmentRepo{taken: tt.taken},
+			}}
+			u := &bimbelUsecase{repo: bimbels, tx: tx}
+
+			tutorID := uint64(ownerTutorID)
+			if tt.role == domain.RoleAdmin {
+				tutorID = 0
+			}
+			b, err := u.ChangeStatus(context.Background(), tt.role, ownerUserID, tutorID, 1, tt.action, "alasan")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if bimbels.bimbel.Status != tt.status || len(bimbels.history) != 0 {
+					t.Errorf("status = %s, history = %v; want tidak berubah", bimbels.bimbel.Status, bimbels.history)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if b.Status != tt.want {
+				t.Errorf("status = %s, want %s", b.Status, tt.want)
+			}
+			if len(bimbels.history) != 1 || bimbels.history[0].FromStatus != tt.status || bimbels.history[0].ToStatus != tt.want {
+				t.Errorf("history = %+v", bimbels.history)
+			}
+		})
+	}
+}
+
+func TestBimbelCreateChecksNameInsideTx(t *testing.T) {
+	for _, taken := range []bool{false, true} {
+		bimbels := &fakeBimbelRepo{names: map[string]bool{"Kelas Fisika": taken}}
+		subjects := &fakeMatpelRepo{}
+		tutors := &fakeTutorRepo{}
+		tx := &fakeTx{repos: &repository.Repositories{Bimbel: bimbels, Matpel: subjects, Tutor: tutors}}
+		u := &bimbelUsecase{repo: bimbels, tx: tx}
+
+		req := &domain.Bimbel{TutorID: 4, FeatureID: 1, SubjectID: 2, Name: "Kelas Fisika", Thumbnail: "thumbnails/a.jpg", Deskripsi: "x"}
+		err := u.Create(context.Background(), domain.RoleTutor, 10, 4, req)
+
+		if tx.calls != 1 || len(subjects.locked) != 1 || len(tutors.locked) != 1 || tutors.locked[0] != 4 {
+			t.Errorf("taken=%v: tx.calls = %d, subject locked = %v, tutor locked = %v", taken, tx.calls, subjects.locked, tutors.locked)
+		}
+		if taken {
+			if !errors.Is(err, domain.ErrBimbelNameTaken) || len(bimbels.created) != 0 {
+				t.Errorf("nama dipakai: err = %v, created = %d; want ErrBimbelNameTaken", err, len(bimbels.created))
+			}
+			continue
+		}
+		if err != nil || len(bimbels.created) != 1 || req.Status != domain.BimbelStatusDraft {
+			t.Errorf("err = %v, created = %d, status = %s", err, len(bimbels.created), req.Status)
+		}
+	}
+}
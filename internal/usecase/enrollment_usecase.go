@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+)
+
+type EnrollmentUsecase interface {
+	Enroll(ctx context.Context, role string, pesertaID uint64, bimbelID uint64) (*domain.Enrollment, error)
+	Cancel(ctx context.Context, role string, pesertaID uint64, bimbelID uint64) error
+	ListMine(ctx context.Context, role string, pesertaID uint64) ([]domain.Enrollment, error)
+	ListByBimbel(ctx context.Context, role string, userTutorID uint64, bimbelID uint64) ([]domain.Enrollment, error)
+	Complete(ctx context.Context, role string, userTutorID uint64, bimbelID, enrollmentID uint64) error
+}
+
+type enrollmentUsecase struct {
+	repo       repository.EnrollmentRepository
+	bimbelRepo repository.BimbelRepository
+	invoiceUC  InvoiceUsecase
+	tx         repository.TxManager
+}
+
+func NewEnrollmentUsecase(r repository.EnrollmentRepository, br repository.BimbelRepository, invoiceUC InvoiceUsecase, tx repository.TxManager) EnrollmentUsecase {
+	return &enrollmentUsecase{repo: r, bimbelRepo: br, invoiceUC: invoiceUC, tx: tx}
+}
+
+func (u *enrollmentUsecase) Enroll(ctx context.Context, role string, pesertaID uint64, bimbelID uint64) (*domain.Enrollment, error) {
+	if err := policy.Authorize(policy.Actor{Role: role, PesertaID: pesertaID}, policy.EnrollmentCreate, nil); err != nil {
+		return nil, err
+	}
+	if pesertaID == 0 {
+		return nil, policy.ErrForbidden
+	}
+
+	b, err := u.bimbelRepo.FindByID(ctx, bimbelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bimbel berbayar baru dikonfirmasi setelah invoice lunas
+	paid := b.Harga > 0
+	status := domain.EnrollmentStatusActive
+	if paid {
+		status = domain.EnrollmentStatusPending
+	}
+
+	if !paid {
+		return u.repo.Enroll(ctx, bimbelID, pesertaID, status)
+	}
+
+	// Enrollment pending dan invoice-nya dibuat dalam satu transaksi, jadi
+	// tidak ada kursi yang terpesan tanpa invoice
+	var (
+		enrollment *domain.Enrollment
+		invoice    *domain.Invoice
+	)
+	err = u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		enrollment, err = repos.Enrollment.Enroll(ctx, bimbelID, pesertaID, status)
+		if err != nil {
+			return err
+		}
+		invoice, err = u.invoiceUC.NewForEnrollment(enrollment, b)
+		if err != nil {
+			return err
+		}
+		return repos.Invoice.Create(ctx, invoice)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Tagihan gagal dibuat: Charge membatalkan invoice beserta enrollment-nya
+	enrollment.Invoice, err = u.invoiceUC.Charge(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+func (u *enrollmentUsecase) Cancel(ctx context.Context, role string, pesertaID uint64, bimbelID uint64) error {
+	actor := policy.Actor{Role: role, PesertaID: pesertaID}
+	if err := policy.Authorize(actor, policy.EnrollmentCancel, &policy.Resource{PesertaID: pesertaID}); err != nil {
+		return err
+	}
+
+	return u.repo.Cancel(ctx, bimbelID, pesertaID)
+}
+
+func (u *enrollmentUsecase) ListMine(ctx context.Context, role string, pesertaID uint64) ([]domain.Enrollment, error) {
+	actor := policy.Actor{Role: role, PesertaID: pesertaID}
+	if err := policy.Authorize(actor, policy.EnrollmentList, &policy.Resource{PesertaID: pesertaID}); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindByPeserta(ctx, pesertaID)
+}
+
+// ownedBimbel memastikan bimbel dikelola oleh tutor pemilik atau admin
+func (u *enrollmentUsecase) ownedBimbel(ctx context.Context, role string, userTutorID uint64, bimbelID uint64) error {
+	b, err := u.bimbelRepo.FindByID(ctx, bimbelID)
+	if err != nil {
+		return err
+	}
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	return policy.Authorize(actor, policy.EnrollmentManage, &policy.Resource{TutorID: b.TutorID})
+}
+
+func (u *enrollmentUsecase) ListByBimbel(ctx context.Context, role string, userTutorID uint64, bimbelID uint64) ([]domain.Enrollment, error) {
+	if err := u.ownedBimbel(ctx, role, userTutorID, bimbelID); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByBimbel(ctx, bimbelID)
+}
+
+func (u *enrollmentUsecase) Complete(ctx context.Context, role string, userTutorID uint64, bimbelID, enrollmentID uint64) error {
+	if err := u.ownedBimbel(ctx, role, userTutorID, bimbelID); err != nil {
+		return err
+	}
+	return u.repo.Complete(ctx, bimbelID, enrollmentID)
+}
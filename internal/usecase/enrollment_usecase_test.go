@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/payment"
+	"main-service/internal/repository"
+	"testing"
+	"time"
+)
+
+type fakeProvider struct {
+	payment.Provider
+	chargeErr error
+	charges   int
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) CreateCharge(inv *domain.Invoice) (*payment.Charge, error) {
+	f.charges++
+	if f.chargeErr != nil {
+		return nil, f.chargeErr
+	}
+	return &payment.Charge{Reference: "ref-" + inv.Number, PaymentURL: "https://pay.example/" + inv.Number}, nil
+}
+
+func newEnrollFixture(harga int64, provider *fakeProvider) (*enrollmentUsecase, *fakeEnrollmentRepo, *fakeInvoiceRepo, *fakeTx) {
+	enrollments := &fakeEnrollmentRepo{}
+	invoices := &fakeInvoiceRepo{}
+	tx := &fakeTx{repos: &repository.Repositories{Enrollment: enrollments, Invoice: invoices}}
+	u := &enrollmentUsecase{
+		repo:       enrollments,
+		bimbelRepo: &fakeBimbelRepo{bimbel: domain.Bimbel{ID: 3, Harga: harga}},
+		invoiceUC:  &invoiceUsecase{repo: invoices, provider: provider, expiry: time.Hour},
+		tx:         tx,
+	}
+	return u, enrollments, invoices, tx
+}
+
+func TestEnrollPaidCreatesEnrollmentAndInvoiceInOneTx(t *testing.T) {
+	provider := &fakeProvider{}
+	u, enrollments, invoices, tx := newEnrollFixture(150000, provider)
+
+	e, err := u.Enroll(context.Background(), domain.RolePeserta, 7, 3)
+	if err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+	if tx.calls != 1 {
+		t.Errorf("WithinTx dipanggil %d kali, want 1", tx.calls)
+	}
+	if len(enrollments.enrolled) != 1 || enrollments.enrolled[0].Status != domain.EnrollmentStatusPending {
+		t.Errorf("enrolled = %+v, want satu enrollment pending", enrollments.enrolled)
+	}
+	if e.Invoice == nil || e.Invoice.EnrollmentID != e.ID || e.Invoice.PaymentURL == "" || e.Invoice.Amount != 150000 {
+		t.Errorf("invoice = %+v", e.Invoice)
+	}
+	if provider.charges != 1 || len(invoices.transitions) != 0 {
+		t.Errorf("charges = %d, transitions = %v", provider.charges, invoices.transitions)
+	}
+}
+
+func TestEnrollFreeSkipsInvoice(t *testing.T) {
+	provider := &fakeProvider{}
+	u, enrollments, _, tx := newEnrollFixture(0, provider)
+
+	e, err := u.Enroll(context.Background(), domain.RolePeserta, 7, 3)
+	if err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+	if e.Status != domain.EnrollmentStatusActive || e.Invoice != nil {
+		t.Errorf("enrollment = %+v, want active tanpa invoice", e)
+	}
+	if tx.calls != 0 || provider.charges != 0 || len(enrollments.enrolled) != 1 {
+		t.Errorf("tx.calls = %d, charges = %d, enrolled = %d", tx.calls, provider.charges, len(enrollments.enrolled))
+	}
+}
+
+func TestEnrollChargeFailureCancelsInvoice(t *testing.T) {
+	provider := &fakeProvider{chargeErr: errors.New("gateway down")}
+	u, _, invoices, _ := newEnrollFixture(150000, provider)
+
+	if _, err := u.Enroll(context.Background(), domain.RolePeserta, 7, 3); err == nil {
+		t.Fatal("Enroll berhasil walaupun tagihan gagal dibuat")
+	}
+	if len(invoices.transitions) != 1 || invoices.transitions[0] != domain.InvoiceStatusCancelled {
+		t.Errorf("transitions = %v, want [cancelled]", invoices.transitions)
+	}
+}
+
+func TestEnrollCompensationErrorIsReported(t *testing.T) {
+	chargeErr := errors.New("gateway down")
+	cancelErr := errors.New("db down")
+	provider := &fakeProvider{chargeErr: chargeErr}
+	u, _, invoices, _ := newEnrollFixture(150000, provider)
+	invoices.transitionErr = cancelErr
+
+	_, err := u.Enroll(context.Background(), domain.RolePeserta, 7, 3)
+	if !errors.Is(err, cancelErr) {
+		t.Errorf("err = %v, want memuat error pembatalan invoice", err)
+	}
+}
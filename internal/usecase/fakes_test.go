@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"main-service/internal/domain"
+	"main-service/internal/repository"
+	"time"
+)
+
+// fakeTx menjalankan fn langsung dengan repository fake (tanpa rollback).
+// lastErr != nil berarti transaksi sungguhan akan di-rollback.
+type fakeTx struct {
+	repos   *repository.Repositories
+	calls   int
+	lastErr error
+}
+
+func (f *fakeTx) WithinTx(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	f.calls++
+	f.lastErr = fn(f.repos)
+	return f.lastErr
+}
+
+type fakeInvoiceRepo struct {
+	repository.InvoiceRepository
+	invoice       domain.Invoice
+	transitions   []string
+	setChargeErr  error
+	transitionErr error
+}
+
+func (f *fakeInvoiceRepo) Create(ctx context.Context, inv *domain.Invoice) error {
+	inv.ID = 1
+	inv.Status = domain.InvoiceStatusPending
+	f.invoice = *inv
+	return nil
+}
+
+func (f *fakeInvoiceRepo) SetCharge(ctx context.Context, id uint64, provider, ref, paymentURL string) error {
+	if f.setChargeErr != nil {
+		return f.setChargeErr
+	}
+	f.invoice.Provider, f.invoice.ProviderRef, f.invoice.PaymentURL = provider, ref, paymentURL
+	return nil
+}
+
+func (f *fakeInvoiceRepo) FindByID(ctx context.Context, id uint64) (*domain.Invoice, error) {
+	inv := f.invoice
+	return &inv, nil
+}
+
+func (f *fakeInvoiceRepo) FindByNumber(ctx context.Context, number string) (*domain.Invoice, error) {
+	inv := f.invoice
+	return &inv, nil
+}
+
+func (f *fakeInvoiceRepo) Transition(ctx context.Context, id uint64, to string) (*domain.Invoice, error) {
+	if f.transitionErr != nil {
+		return nil, f.transitionErr
+	}
+	f.transitions = append(f.transitions, to)
+	f.invoice.Status = to
+	inv := f.invoice
+	return &inv, nil
+}
+
+type fakeEnrollmentRepo struct {
+	repository.EnrollmentRepository
+	active   bool
+	inactive map[uint64]bool // peserta yang tidak terdaftar walaupun active true
+	enrolled []domain.Enrollment
+	taken    int
+}
+
+func (f *fakeEnrollmentRepo) CountTakenSeats(ctx context.Context, bimbelID uint64) (int, error) {
+	return f.taken, nil
+}
+
+func (f *fakeEnrollmentRepo) ExistsActive(ctx context.Context, bimbelID, pesertaID uint64) (bool, error) {
+	return f.active && !f.inactive[pesertaID], nil
+}
+
+func (f *fakeEnrollmentRepo) Enroll(ctx context.Context, bimbelID, pesertaID uint64, status string) (*domain.Enrollment, error) {
+	e := domain.Enrollment{ID: uint64(len(f.enrolled) + 1), BimbelID: bimbelID, PesertaID: pesertaID, Status: status}
+	f.enrolled = append(f.enrolled, e)
+	return &e, nil
+}
+
+type fakeBimbelRepo struct {
+	repository.BimbelRepository
+	bimbel     domain.Bimbel
+	deleted    []domain.Bimbel
+	thumbnails map[string]bool
+	purged     int64
+	history    []domain.BimbelStatusChange
+	names      map[string]bool // nama yang sudah dipakai tutor
+	created    []domain.Bimbel
+}
+
+func (f *fakeBimbelRepo) ExistsDuplicate(ctx context.Context, name string, featureID, subjectID uint64, excludeID *uint64) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeBimbelRepo) ExistsByNameAndTutor(ctx context.Context, name string, tutorID uint64) (bool, error) {
+	return f.names[name], nil
+}
+
+func (f *fakeBimbelRepo) Create(ctx context.Context, b *domain.Bimbel) error {
+	b.ID = uint64(len(f.created) + 1)
+	f.created = append(f.created, *b)
+	return nil
+}
+
+func (f *fakeBimbelRepo) LockByID(ctx context.Context, id uint64) (*domain.Bimbel, error) {
+	if f.bimbel.ID != id {
+		return nil, domain.ErrBimbelNotFound
+	}
+	b := f.bimbel
+	return &b, nil
+}
+
+func (f *fakeBimbelRepo) UpdateStatus(ctx context.Context, id uint64, from, to string) error {
+	if !domain.CanTransitionBimbel(from, to) || f.bimbel.Status != from {
+		return domain.ErrInvalidBimbelTransition
+	}
+	f.bimbel.Status = to
+	return nil
+}
+
+func (f *fakeBimbelRepo) RecordStatusChange(ctx context.Context, change *domain.BimbelStatusChange) error {
+	f.history = append(f.history, *change)
+	return nil
+}
+
+func (f *fakeBimbelRepo) ListDeleted(ctx context.Context) ([]domain.Bimbel, error) {
+	return f.deleted, nil
+}
+
+func (f *fakeBimbelRepo) LockDeletedByID(ctx context.Context, id uint64) (*domain.Bimbel, error) {
+	for _, b := range f.deleted {
+		if b.ID == id {
+			return &b, nil
+		}
+	}
+	return nil, domain.ErrBimbelNotFound
+}
+
+func (f *fakeBimbelRepo) ThumbnailKeysInUse(ctx context.Context) (map[string]bool, error) {
+	return f.thumbnails, nil
+}
+
+func (f *fakeBimbelRepo) FindByID(ctx context.Context, id uint64) (*domain.Bimbel, error) {
+	b := f.bimbel
+	return &b, nil
+}
+
+func (f *fakeBimbelRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return f.purged, nil
+}
+
+type fakeTutorRepo struct {
+	repository.TutorRepository
+	verified bool
+	locked   []uint64
+}
+
+func (f *fakeTutorRepo) LockByID(ctx context.Context, id uint64) error {
+	f.locked = append(f.locked, id)
+	return nil
+}
+
+func (f *fakeTutorRepo) IsVerified(ctx context.Context, id uint64) (bool, error) {
+	return f.verified, nil
+}
+
+type fakeMatpelRepo struct {
+	repository.MatpelRepository
+	purged  int64
+	err     error
+	locked  []uint64
+	deleted []repository.Matpel
+}
+
+func (f *fakeMatpelRepo) ListDeleted(ctx context.Context) ([]repository.Matpel, error) {
+	return f.deleted, nil
+}
+
+func (f *fakeMatpelRepo) LockByID(ctx context.Context, id uint64) (bool, error) {
+	f.locked = append(f.locked, id)
+	return true, nil
+}
+
+func (f *fakeMatpelRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return f.purged, f.err
+}
+
+type fakeFeatureRepo struct {
+	repository.FeatureRepository
+	purged  int64
+	deleted []repository.Feature
+}
+
+func (f *fakeFeatureRepo) ListDeleted(ctx context.Context) ([]repository.Feature, error) {
+	return f.deleted, nil
+}
+
+func (f *fakeFeatureRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return f.purged, nil
+}
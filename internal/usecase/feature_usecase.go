@@ -1,43 +1,53 @@
 package usecase
 
 import (
-	"errors"
+	"context"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
 	"main-service/internal/repository"
 	"strings"
+	"time"
 )
 
 type FeatureUsecase interface {
-	GetFeaturesByRole(role string) ([]repository.Feature, error)
-	Create(name string, roles string, isActive *bool) (*repository.Feature, error)
-	Update(id uint64, name string, roles string, isActive bool) (*repository.Feature, error)
-	Delete(id uint64, role string) error
-	GetDetail(id uint64) (*repository.Feature, error)
+	GetFeaturesByRole(ctx context.Context, role string) ([]repository.Feature, error)
+	Create(ctx context.Context, name string, roles string, isActive *bool, role string) (*repository.Feature, error)
+	Update(ctx context.Context, id uint64, name string, roles string, isActive bool, role string) (*repository.Feature, error)
+	Delete(ctx context.Context, id uint64, role string) error
+	GetDetail(ctx context.Context, id uint64) (*repository.Feature, error)
+	Trash(ctx context.Context, role string) ([]repository.Feature, error)
+	Restore(ctx context.Context, id uint64, role string) (*repository.Feature, error)
 }
 
 type featureUsecase struct {
 	repo repository.FeatureRepository
+	tx   repository.TxManager
 }
 
-func NewFeatureUsecase(r repository.FeatureRepository) FeatureUsecase {
-	return &featureUsecase{repo: r}
+func NewFeatureUsecase(r repository.FeatureRepository, tx repository.TxManager) FeatureUsecase {
+	return &featureUsecase{repo: r, tx: tx}
 }
 
-func (u *featureUsecase) GetFeaturesByRole(role string) ([]repository.Feature, error) {
-	return u.repo.GetByRole(role)
+func (u *featureUsecase) GetFeaturesByRole(ctx context.Context, role string) ([]repository.Feature, error) {
+	return u.repo.GetByRole(ctx, role)
 }
 
-func (u *featureUsecase) Create(name string, roles string, isActive *bool) (*repository.Feature, error) {
+func (u *featureUsecase) Create(ctx context.Context, name string, roles string, isActive *bool, role string) (*repository.Feature, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.FeatureCreate, nil); err != nil {
+		return nil, err
+	}
+
 	name = strings.TrimSpace(name)
-	dup, err := u.repo.ExistsByName(name)
+	dup, err := u.repo.ExistsByName(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 	if dup {
-		return nil, errors.New("fitur dengan nama tersebut sudah ada")
+		return nil, domain.ErrFeatureNameTaken
 	}
 
 	if name == "" {
-		return nil, errors.New("nama fitur tidak boleh kosong")
+		return nil, domain.InvalidField("name", "nama fitur tidak boleh kosong")
 	}
 
 	active := true
@@ -45,7 +55,7 @@ func (u *featureUsecase) Create(name string, roles string, isActive *bool) (*rep
 		active = *isActive
 	}
 
-	feature, err := u.repo.Create(name, roles, active)
+	feature, err := u.repo.Create(ctx, name, roles, active)
 	if err != nil {
 		return nil, err
 	}
@@ -53,26 +63,30 @@ func (u *featureUsecase) Create(name string, roles string, isActive *bool) (*rep
 	return feature, nil
 }
 
-func (u *featureUsecase) Update(id uint64, name string, roles string, isActive bool) (*repository.Feature, error) {
+func (u *featureUsecase) Update(ctx context.Context, id uint64, name string, roles string, isActive bool, role string) (*repository.Feature, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.FeatureUpdate, nil); err != nil {
+		return nil, err
+	}
+
 	name = strings.TrimSpace(name)
 	if name == "" {
-		return nil, errors.New("nama fitur wajib diisi")
+		return nil, domain.InvalidField("name", "nama fitur wajib diisi")
 	}
 
 	roles = strings.TrimSpace(roles)
 	if roles == "" {
-		return nil, errors.New("roles wajib diisi")
+		return nil, domain.InvalidField("roles", "roles wajib diisi")
 	}
 
-	dup, err := u.repo.ExistsByNameExceptID(id, name)
+	dup, err := u.repo.ExistsByNameExceptID(ctx, id, name)
 	if err != nil {
 		return nil, err
 	}
 	if dup {
-		return nil, errors.New("nama fitur sudah ada")
+		return nil, domain.ErrFeatureNameTaken
 	}
 
-	updated, err := u.repo.Update(id, name, roles, isActive)
+	updated, err := u.repo.Update(ctx, id, name, roles, isActive)
 	if err != nil {
 		return nil, err
 	}
@@ -80,19 +94,90 @@ func (u *featureUsecase) Update(id uint64, name string, roles string, isActive b
 	return updated, nil
 }
 
-func (u *featureUsecase) Delete(id uint64, role string) error {
-	if role != "admin" {
-		return errors.New("akses ditolak, hanya admin yang dapat menghapus mata pelajaran")
+func (u *featureUsecase) Delete(ctx context.Context, id uint64, role string) error {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.FeatureDelete, nil); err != nil {
+		return err
 	}
 
-	return u.repo.Delete(id)
+	// Subject ikut masuk trash dengan deleted_at yang sama supaya bisa
+	// dipulihkan bersama. DATETIME MySQL tidak menyimpan pecahan detik.
+	deletedAt := time.Now().UTC().Truncate(time.Second)
+
+	return u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		// Feature dan subject-nya dikunci supaya tidak ada bimbel baru
+		// yang lolos dibuat di antara pengecekan dan soft delete
+		if err := repos.Feature.LockByID(ctx, id); err != nil {
+			return err
+		}
+		if err := repos.Matpel.LockByFeature(ctx, id); err != nil {
+			return err
+		}
+
+		inUse, err := repos.Bimbel.ExistsActiveByFeature(ctx, id)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return domain.ErrFeatureInUse
+		}
+
+		if err := repos.Matpel.DeleteByFeature(ctx, id, deletedAt); err != nil {
+			return err
+		}
+		return repos.Feature.Delete(ctx, id, deletedAt)
+	})
 }
 
-func (u *featureUsecase) GetDetail(id uint64) (*repository.Feature, error) {
-	updated, err := u.repo.GetByID(id)
+func (u *featureUsecase) GetDetail(ctx context.Context, id uint64) (*repository.Feature, error) {
+	updated, err := u.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	return updated, nil
 }
+
+func (u *featureUsecase) Trash(ctx context.Context, role string) ([]repository.Feature, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.FeatureTrash, nil); err != nil {
+		return nil, err
+	}
+	return u.repo.ListDeleted(ctx)
+}
+
+// Restore memulihkan feature beserta subject yang ikut terhapus bersamanya
+func (u *featureUsecase) Restore(ctx context.Context, id uint64, role string) (*repository.Feature, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.FeatureTrash, nil); err != nil {
+		return nil, err
+	}
+
+	var restored *repository.Feature
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		f, err := repos.Feature.LockDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Nama bisa saja sudah dipakai feature baru selama ada di trash
+		dup, err := repos.Feature.ExistsByName(ctx, f.Name)
+		if err != nil {
+			return err
+		}
+		if dup {
+			return domain.ErrFeatureNameTaken
+		}
+
+		if _, err := repos.Matpel.RestoreByFeature(ctx, id); err != nil {
+			return err
+		}
+		if err := repos.Feature.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		restored, err = repos.Feature.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
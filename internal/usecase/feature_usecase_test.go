@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"testing"
+)
+
+func TestFeatureUsecaseAuthorizesEveryWrite(t *testing.T) {
+	for _, role := range []string{domain.RoleTutor, domain.RolePeserta, ""} {
+		t.Run(role, func(t *testing.T) {
+			features := &fakeFeatureRepo{}
+			tx := &fakeTx{repos: &repository.Repositories{Feature: features}}
+			u := &featureUsecase{repo: features, tx: tx}
+			ctx := context.Background()
+			active := true
+
+			calls := map[string]error{}
+			_, calls["create"] = u.Create(ctx, "Les Privat", domain.RoleTutor, &active, role)
+			_, calls["update"] = u.Update(ctx, 1, "Les Privat", domain.RoleTutor, true, role)
+			calls["delete"] = u.Delete(ctx, 1, role)
+			_, calls["trash"] = u.Trash(ctx, role)
+			_, calls["restore"] = u.Restore(ctx, 1, role)
+
+			for name, err := range calls {
+				if !errors.Is(err, policy.ErrForbidden) {
+					t.Errorf("%s: err = %v, want ErrForbidden", name, err)
+				}
+			}
+			if tx.calls != 0 {
+				t.Errorf("WithinTx dipanggil %d kali, want 0", tx.calls)
+			}
+		})
+	}
+}
+
+func TestFeatureTrashAdmin(t *testing.T) {
+	features := &fakeFeatureRepo{deleted: []repository.Feature{{ID: 1}}}
+	u := &featureUsecase{repo: features}
+
+	items, err := u.Trash(context.Background(), domain.RoleAdmin)
+	if err != nil || len(items) != 1 {
+		t.Errorf("Trash = %v, %v; want 1 item", items, err)
+	}
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"main-service/internal/domain"
+	"main-service/internal/payment"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"strings"
+	"time"
+)
+
+type InvoiceUsecase interface {
+	NewForEnrollment(e *domain.Enrollment, b *domain.Bimbel) (*domain.Invoice, error)
+	Charge(ctx context.Context, inv *domain.Invoice) (*domain.Invoice, error)
+	List(ctx context.Context, role string, pesertaID uint64, status string) ([]domain.Invoice, error)
+	Detail(ctx context.Context, role string, pesertaID uint64, id uint64) (*domain.Invoice, error)
+	Cancel(ctx context.Context, role string, pesertaID uint64, id uint64) (*domain.Invoice, error)
+	Refund(ctx context.Context, role string, id uint64) (*domain.Invoice, error)
+	MarkPaid(ctx context.Context, id uint64) (*domain.Invoice, error)
+	SimulatePayment(ctx context.Context, role string, pesertaID uint64, id uint64) (*domain.Invoice, error)
+	ExpireOverdue(ctx context.Context) (int, error)
+}
+
+type invoiceUsecase struct {
+	repo     repository.InvoiceRepository
+	provider payment.Provider
+	expiry   time.Duration
+}
+
+func NewInvoiceUsecase(r repository.InvoiceRepository, provider payment.Provider, expiry time.Duration) InvoiceUsecase {
+	return &invoiceUsecase{repo: r, provider: provider, expiry: expiry}
+}
+
+func newInvoiceNumber() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("INV-%s-%s", time.Now().Format("20060102"), strings.ToUpper(hex.EncodeToString(buf))), nil
+}
+
+// NewForEnrollment menyiapkan invoice pending untuk enrollment berbayar.
+// Invoice belum disimpan: pemanggil menyimpannya lewat InvoiceRepository.Create
+// dalam transaksi yang sama dengan enrollment, lalu memanggil Charge.
+func (u *invoiceUsecase) NewForEnrollment(e *domain.Enrollment, b *domain.Bimbel) (*domain.Invoice, error) {
+	amount := b.Harga
+	if amount <= 0 {
+		return nil, domain.Unprocessable("INVOICE_NOT_REQUIRED", "bimbel gratis tidak memerlukan invoice")
+	}
+
+	number, err := newInvoiceNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Invoice{
+		Number:       number,
+		EnrollmentID: e.ID,
+		BimbelID:     e.BimbelID,
+		PesertaID:    e.PesertaID,
+		Amount:       amount,
+		ExpiresAt:    time.Now().Add(u.expiry),
+	}, nil
+}
+
+// Charge membuat tagihan di payment provider untuk invoice yang sudah
+// tersimpan. Provider dipanggil di luar transaksi karena WithinTx bisa
+// diulang; jika gagal, invoice dibatalkan sehingga enrollment pending ikut
+// batal dan kursinya dilepas.
+func (u *invoiceUsecase) Charge(ctx context.Context, inv *domain.Invoice) (*domain.Invoice, error) {
+	charge, err := u.provider.CreateCharge(inv)
+	if err == nil {
+		err = u.repo.SetCharge(ctx, inv.ID, u.provider.Name(), charge.Reference, charge.PaymentURL)
+		if err == nil {
+			return u.repo.FindByID(ctx, inv.ID)
+		}
+	} else {
+		err = fmt.Errorf("gagal membuat tagihan pembayaran: %v", err)
+	}
+
+	if _, cancelErr := u.repo.Transition(ctx, inv.ID, domain.InvoiceStatusCancelled); cancelErr != nil {
+		return nil, errors.Join(err, fmt.Errorf("gagal membatalkan invoice %s: %w", inv.Number, cancelErr))
+	}
+	return nil, err
+}
+
+// expireIfOverdue menandai invoice pending yang sudah lewat batas waktu
+// sebagai expired saat dibaca, tanpa menunggu job berkala
+func (u *invoiceUsecase) expireIfOverdue(ctx context.Context, inv *domain.Invoice) (*domain.Invoice, error) {
+	if inv.Status != domain.InvoiceStatusPending || time.Now().Before(inv.ExpiresAt) {
+		return inv, nil
+	}
+
+	expired, err := u.repo.Transition(ctx, inv.ID, domain.InvoiceStatusExpired)
+	if errors.Is(err, domain.ErrInvalidInvoiceTransition) {
+		return u.repo.FindByID(ctx, inv.ID)
+	}
+	return expired, err
+}
+
+// ownedInvoice mengambil invoice yang boleh diakses user untuk aksi tertentu:
+// peserta pemilik atau admin
+func (u *invoiceUsecase) ownedInvoice(ctx context.Context, role string, pesertaID uint64, id uint64, action string) (*domain.Invoice, error) {
+	inv, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := policy.Actor{Role: role, PesertaID: pesertaID}
+	if err := policy.Authorize(actor, action, &policy.Resource{PesertaID: inv.PesertaID}); err != nil {
+		return nil, err
+	}
+
+	return u.expireIfOverdue(ctx, inv)
+}
+
+// List menampilkan semua invoice untuk yang berhak melihat seluruhnya (admin),
+// selain itu hanya invoice milik peserta sendiri
+func (u *invoiceUsecase) List(ctx context.Context, role string, pesertaID uint64, status string) ([]domain.Invoice, error) {
+	actor := policy.Actor{Role: role, PesertaID: pesertaID}
+	if err := policy.Authorize(actor, policy.InvoiceList, nil); err == nil {
+		return u.repo.FindAll(ctx, status)
+	}
+	if err := policy.Authorize(actor, policy.InvoiceList, &policy.Resource{PesertaID: pesertaID}); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByPeserta(ctx, pesertaID)
+}
+
+func (u *invoiceUsecase) Detail(ctx context.Context, role string, pesertaID uint64, id uint64) (*domain.Invoice, error) {
+	return u.ownedInvoice(ctx, role, pesertaID, id, policy.InvoiceView)
+}
+
+func (u *invoiceUsecase) Cancel(ctx context.Context, role string, pesertaID uint64, id uint64) (*domain.Invoice, error) {
+	if _, err := u.ownedInvoice(ctx, role, pesertaID, id, policy.InvoiceCancel); err != nil {
+		return nil, err
+	}
+	return u.repo.Transition(ctx, id, domain.InvoiceStatusCancelled)
+}
+
+func (u *invoiceUsecase) Refund(ctx context.Context, role string, id uint64) (*domain.Invoice, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.InvoiceRefund, nil); err != nil {
+		return nil, err
+	}
+
+	inv, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanTransitionInvoice(inv.Status, domain.InvoiceStatusRefunded) {
+		return nil, domain.ErrInvalidInvoiceTransition
+	}
+	if err := u.provider.Refund(inv); err != nil {
+		return nil, fmt.Errorf("gagal memproses refund: %v", err)
+	}
+
+	return u.repo.Transition(ctx, id, domain.InvoiceStatusRefunded)
+}
+
+func (u *invoiceUsecase) MarkPaid(ctx context.Context, id uint64) (*domain.Invoice, error) {
+	return u.repo.Transition(ctx, id, domain.InvoiceStatusPaid)
+}
+
+// SimulatePayment hanya tersedia untuk FakeProvider (development/testing)
+func (u *invoiceUsecase) SimulatePayment(ctx context.Context, role string, pesertaID uint64, id uint64) (*domain.Invoice, error) {
+	if u.provider.Name() != payment.FakeProviderName {
+		return nil, domain.Unprocessable("SIMULATION_UNAVAILABLE", "simulasi pembayaran hanya tersedia untuk provider fake")
+	}
+
+	inv, err := u.ownedInvoice(ctx, role, pesertaID, id, policy.InvoiceSimulate)
+	if err != nil {
+		return nil, err
+	}
+	return u.MarkPaid(ctx, inv.ID)
+}
+
+// ExpireOverdue dipanggil berkala untuk melepas kursi dari invoice yang tidak dibayar
+func (u *invoiceUsecase) ExpireOverdue(ctx context.Context) (int, error) {
+	ids, err := u.repo.FindExpiredPending(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		_, err := u.repo.Transition(ctx, id, domain.InvoiceStatusExpired)
+		if err != nil && !errors.Is(err, domain.ErrInvalidInvoiceTransition) {
+			return expired, err
+		}
+		if err == nil {
+			expired++
+		}
+	}
+	return expired, nil
+}
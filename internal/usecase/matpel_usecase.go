@@ -1,57 +1,49 @@
 package usecase
 
 import (
+	"context"
 	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
 	"main-service/internal/repository"
 	"strings"
+	"time"
 )
 
 type MatpelUsecase interface {
-	GetMatpelByFeature(featureId uint64) ([]repository.Matpel, error)
-	Create(featureID uint64, name string, deskripsi *string, isActive *bool) (*repository.Matpel, error)
-	Update(id uint64, featureID uint64, name string, deskripsi *string, isActive bool) (*repository.Matpel, error)
-	Delete(id uint64, role string) error
-	GetDetail(id uint64) (*repository.Matpel, error)
+	GetMatpelByFeature(ctx context.Context, featureId uint64) ([]repository.Matpel, error)
+	Create(ctx context.Context, featureID uint64, name string, deskripsi *string, isActive *bool, role string) (*repository.Matpel, error)
+	Update(ctx context.Context, id uint64, featureID uint64, name string, deskripsi *string, isActive bool, role string) (*repository.Matpel, error)
+	Delete(ctx context.Context, id uint64, role string) error
+	GetDetail(ctx context.Context, id uint64) (*repository.Matpel, error)
+	Trash(ctx context.Context, role string) ([]repository.Matpel, error)
+	Restore(ctx context.Context, id uint64, role string) (*repository.Matpel, error)
 }
 
 type matpelUsecase struct {
-	matpelRepo  repository.MatpelRepository
-	featureRepo repository.FeatureRepository
+	matpelRepo repository.MatpelRepository
+	tx         repository.TxManager
 }
 
-func NewMatpelUsecase(subjectRepo repository.MatpelRepository, featureRepo repository.FeatureRepository) MatpelUsecase {
+func NewMatpelUsecase(subjectRepo repository.MatpelRepository, tx repository.TxManager) MatpelUsecase {
 	return &matpelUsecase{
-		matpelRepo:  subjectRepo,
-		featureRepo: featureRepo,
+		matpelRepo: subjectRepo,
+		tx:         tx,
 	}
 }
 
-func (u *matpelUsecase) GetMatpelByFeature(featureId uint64) ([]repository.Matpel, error) {
-	return u.matpelRepo.GetByFeature(featureId)
+func (u *matpelUsecase) GetMatpelByFeature(ctx context.Context, featureId uint64) ([]repository.Matpel, error) {
+	return u.matpelRepo.GetByFeature(ctx, featureId)
 }
 
-func (u *matpelUsecase) Create(featureID uint64, name string, deskripsi *string, isActive *bool) (*repository.Matpel, error) {
-	// Check: Feature ID valid?
-	exists, err := u.featureRepo.ExistsByID(featureID)
-	if err != nil {
+func (u *matpelUsecase) Create(ctx context.Context, featureID uint64, name string, deskripsi *string, isActive *bool, role string) (*repository.Matpel, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.MatpelCreate, nil); err != nil {
 		return nil, err
 	}
-	if !exists {
-		return nil, errors.New("feature_id tidak ditemukan")
-	}
 
 	name = strings.TrimSpace(name)
-	// Check: apakah subject dengan nama sama sudah ada?
-	dup, err := u.matpelRepo.ExistsByNameAndFeatureID(name, featureID)
-	if err != nil {
-		return nil, err
-	}
-	if dup {
-		return nil, errors.New("mata pelajaran dengan nama tersebut sudah ada pada feature ini")
-	}
-
 	if name == "" {
-		return nil, errors.New("nama mata pelajaran tidak boleh kosong")
+		return nil, domain.InvalidField("name", "nama mata pelajaran tidak boleh kosong")
 	}
 
 	active := true
@@ -59,7 +51,30 @@ func (u *matpelUsecase) Create(featureID uint64, name string, deskripsi *string,
 		active = *isActive
 	}
 
-	subject, err := u.matpelRepo.Create(featureID, name, deskripsi, active)
+	var subject *repository.Matpel
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		// Check: Feature ID valid? Baris feature dikunci supaya dua request
+		// dengan nama yang sama tidak lolos cek duplikat bersamaan
+		exists, err := repos.Feature.LockActiveByID(ctx, featureID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.InvalidField("feature_id", "feature_id tidak ditemukan")
+		}
+
+		// Check: apakah subject dengan nama sama sudah ada?
+		dup, err := repos.Matpel.ExistsByNameAndFeatureID(ctx, name, featureID)
+		if err != nil {
+			return err
+		}
+		if dup {
+			return domain.ErrMatpelNameTaken
+		}
+
+		subject, err = repos.Matpel.Create(ctx, featureID, name, deskripsi, active)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -67,29 +82,73 @@ func (u *matpelUsecase) Create(featureID uint64, name string, deskripsi *string,
 	return subject, nil
 }
 
-func (u *matpelUsecase) Update(id uint64, featureID uint64, name string, deskripsi *string, isActive bool) (*repository.Matpel, error) {
-	name = strings.TrimSpace(name)
-	if name == "" {
-		return nil, errors.New("nama mata pelajaran wajib diisi")
-	}
-
-	exists, err := u.featureRepo.ExistsByID(featureID)
-	if err != nil {
+func (u *matpelUsecase) Update(ctx context.Context, id uint64, featureID uint64, name string, deskripsi *string, isActive bool, role string) (*repository.Matpel, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.MatpelUpdate, nil); err != nil {
 		return nil, err
 	}
-	if !exists {
-		return nil, errors.New("feature_id tidak ditemukan atau tidak aktif")
-	}
 
-	dup, err := u.matpelRepo.ExistsByNameAndFeatureIDExceptID(id, featureID, name)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.InvalidField("name", "nama mata pelajaran wajib diisi")
+	}
+
+	var updated *repository.Matpel
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		exists, err := repos.Feature.LockActiveByID(ctx, featureID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.InvalidField("feature_id", "feature_id tidak ditemukan atau tidak aktif")
+		}
+
+		dup, err := repos.Matpel.ExistsByNameAndFeatureIDExceptID(ctx, id, featureID, name)
+		if err != nil {
+			return err
+		}
+		if dup {
+			return domain.ErrMatpelNameTaken
+		}
+
+		updated, err = repos.Matpel.Update(ctx, id, featureID, name, deskripsi, isActive)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if dup {
-		return nil, errors.New("nama mata pelajaran sudah ada pada feature ini")
-	}
 
-	updated, err := u.matpelRepo.Update(id, featureID, name, deskripsi, isActive)
+	return updated, nil
+}
+
+func (u *matpelUsecase) Delete(ctx context.Context, id uint64, role string) error {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.MatpelDelete, nil); err != nil {
+		return err
+	}
+
+	return u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		// Kunci subject: pembuatan bimbel juga mengunci baris ini (lockSubject)
+		found, err := repos.Matpel.LockByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return domain.ErrMatpelNotFound
+		}
+
+		inUse, err := repos.Bimbel.ExistsActiveBySubject(ctx, id)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return domain.ErrMatpelInUse
+		}
+
+		return repos.Matpel.Delete(ctx, id, time.Now().UTC().Truncate(time.Second))
+	})
+}
+
+func (u *matpelUsecase) GetDetail(ctx context.Context, id uint64) (*repository.Matpel, error) {
+	updated, err := u.matpelRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -97,19 +156,50 @@ func (u *matpelUsecase) Update(id uint64, featureID uint64, name string, deskrip
 	return updated, nil
 }
 
-func (u *matpelUsecase) Delete(id uint64, role string) error {
-	if role != "admin" {
-		return errors.New("akses ditolak, hanya admin yang dapat menghapus mata pelajaran")
+func (u *matpelUsecase) Trash(ctx context.Context, role string) ([]repository.Matpel, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.MatpelTrash, nil); err != nil {
+		return nil, err
 	}
-
-	return u.matpelRepo.Delete(id)
+	return u.matpelRepo.ListDeleted(ctx)
 }
 
-func (u *matpelUsecase) GetDetail(id uint64) (*repository.Matpel, error) {
-	updated, err := u.matpelRepo.GetByID(id)
-	if err != nil {
+func (u *matpelUsecase) Restore(ctx context.Context, id uint64, role string) (*repository.Matpel, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.MatpelTrash, nil); err != nil {
 		return nil, err
 	}
 
-	return updated, nil
+	var restored *repository.Matpel
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		m, err := repos.Matpel.LockDeletedByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Feature induk harus sudah aktif lagi; dikunci seperti saat create
+		if err := repos.Feature.LockByID(ctx, m.FeatureID); err != nil {
+			if errors.Is(err, domain.ErrFeatureNotFound) {
+				return domain.ErrFeatureInTrash
+			}
+			return err
+		}
+
+		dup, err := repos.Matpel.ExistsByNameAndFeatureID(ctx, m.Name, m.FeatureID)
+		if err != nil {
+			return err
+		}
+		if dup {
+			return domain.ErrMatpelNameTaken
+		}
+
+		if err := repos.Matpel.Restore(ctx, id); err != nil {
+			return err
+		}
+
+		restored, err = repos.Matpel.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
 }
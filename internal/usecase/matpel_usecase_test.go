@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"testing"
+)
+
+func TestMatpelUsecaseAuthorizesEveryWrite(t *testing.T) {
+	for _, role := range []string{domain.RoleTutor, domain.RolePeserta, ""} {
+		t.Run(role, func(t *testing.T) {
+			subjects := &fakeMatpelRepo{}
+			tx := &fakeTx{repos: &repository.Repositories{Matpel: subjects}}
+			u := &matpelUsecase{matpelRepo: subjects, tx: tx}
+			ctx := context.Background()
+			active := true
+
+			calls := map[string]error{}
+			_, calls["create"] = u.Create(ctx, 1, "Fisika", nil, &active, role)
+			_, calls["update"] = u.Update(ctx, 2, 1, "Fisika", nil, true, role)
+			calls["delete"] = u.Delete(ctx, 2, role)
+			_, calls["trash"] = u.Trash(ctx, role)
+			_, calls["restore"] = u.Restore(ctx, 2, role)
+
+			for name, err := range calls {
+				if !errors.Is(err, policy.ErrForbidden) {
+					t.Errorf("%s: err = %v, want ErrForbidden", name, err)
+				}
+			}
+			if tx.calls != 0 || len(subjects.locked) != 0 {
+				t.Errorf("tx.calls = %d, locked = %v; want repository tidak disentuh", tx.calls, subjects.locked)
+			}
+		})
+	}
+}
+
+func TestMatpelTrashAdmin(t *testing.T) {
+	subjects := &fakeMatpelRepo{deleted: []repository.Matpel{{ID: 2}}}
+	u := &matpelUsecase{matpelRepo: subjects}
+
+	items, err := u.Trash(context.Background(), domain.RoleAdmin)
+	if err != nil || len(items) != 1 {
+		t.Errorf("Trash = %v, %v; want 1 item", items, err)
+	}
+}
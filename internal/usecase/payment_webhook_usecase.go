@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"main-service/internal/domain"
+	"main-service/internal/payment"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+)
+
+type PaymentWebhookUsecase interface {
+	Receive(ctx context.Context, provider, signature string, body []byte) (*domain.PaymentNotification, error)
+	Replay(ctx context.Context, role string, id uint64) (*domain.PaymentNotification, error)
+	List(ctx context.Context, role string, result string, limit int) ([]domain.PaymentNotification, error)
+}
+
+type paymentWebhookUsecase struct {
+	repo        repository.PaymentNotificationRepository
+	invoiceRepo repository.InvoiceRepository
+	secret      string
+}
+
+func NewPaymentWebhookUsecase(r repository.PaymentNotificationRepository, ir repository.InvoiceRepository, secret string) PaymentWebhookUsecase {
+	return &paymentWebhookUsecase{repo: r, invoiceRepo: ir, secret: secret}
+}
+
+// Receive menyimpan notifikasi mentah lalu menerapkannya ke invoice.
+// Notifikasi yang sama (event_id sama) hanya diterapkan sekali.
+func (u *paymentWebhookUsecase) Receive(ctx context.Context, provider, signature string, body []byte) (*domain.PaymentNotification, error) {
+	n := &domain.PaymentNotification{
+		Provider:       provider,
+		SignatureValid: payment.VerifySignature(u.secret, body, signature),
+		Payload:        string(body),
+	}
+
+	parsed, parseErr := payment.ParseNotification(body)
+	if parsed != nil {
+		n.EventID = parsed.EventID
+		n.InvoiceNumber = parsed.InvoiceNumber
+		n.GatewayStatus = parsed.GatewayStatus
+	}
+
+	// Tetap disimpan walau ditolak agar bisa diaudit
+	switch {
+	case !n.SignatureValid:
+		n.Result, n.ResultMessage = domain.NotificationResultRejected, domain.ErrInvalidSignature.Error()
+	case parseErr != nil:
+		n.Result, n.ResultMessage = domain.NotificationResultRejected, parseErr.Error()
+	}
+	if err := u.repo.Create(ctx, n); err != nil {
+		return nil, err
+	}
+
+	if !n.SignatureValid {
+		return n, domain.ErrInvalidSignature
+	}
+	if parseErr != nil {
+		return n, parseErr
+	}
+
+	return u.process(ctx, n, parsed)
+}
+
+// Replay memproses ulang notifikasi tersimpan, mis. setelah invoice diperbaiki manual
+func (u *paymentWebhookUsecase) Replay(ctx context.Context, role string, id uint64) (*domain.PaymentNotification, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.PaymentNotificationReplay, nil); err != nil {
+		return nil, err
+	}
+
+	n, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !n.SignatureValid {
+		return nil, domain.ErrNotificationNotReplayable
+	}
+
+	parsed, err := payment.ParseNotification([]byte(n.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return u.process(ctx, n, parsed)
+}
+
+func (u *paymentWebhookUsecase) List(ctx context.Context, role string, result string, limit int) ([]domain.PaymentNotification, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.PaymentNotificationList, nil); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return u.repo.FindAll(ctx, result, limit)
+}
+
+func (u *paymentWebhookUsecase) process(ctx context.Context, n *domain.PaymentNotification, parsed *payment.Notification) (*domain.PaymentNotification, error) {
+	result, message, err := u.apply(ctx, n, parsed)
+	if err != nil {
+		result, message = domain.NotificationResultFailed, err.Error()
+	}
+
+	if markErr := u.repo.MarkResult(ctx, n.ID, result, message); markErr != nil {
+		return nil, markErr
+	}
+	n.Result, n.ResultMessage = result, message
+	return n, err
+}
+
+// apply menerapkan status dari gateway ke invoice secara aman:
+//   - event yang sudah diproses diabaikan (idempotent)
+//   - status yang sama dengan status invoice saat ini tidak mengubah apa pun
+//   - transisi mundur/urutan terbalik (mis. pending setelah paid) diabaikan
+//   - pembayaran yang datang setelah invoice expired/cancelled ditandai needs_review
+//   - pembayaran dengan nominal berbeda atau tanpa nominal ditandai needs_review
+func (u *paymentWebhookUsecase) apply(ctx context.Context, n *domain.PaymentNotification, parsed *payment.Notification) (string, string, error) {
+	duplicate, err := u.repo.ExistsProcessed(ctx, n.Provider, n.EventID, n.ID)
+	if err != nil {
+		return "", "", err
+	}
+	if duplicate {
+		return domain.NotificationResultDuplicate, "event sudah pernah diproses", nil
+	}
+
+	if parsed.InvoiceStatus == "" {
+		return domain.NotificationResultIgnored, fmt.Sprintf("status gateway %q tidak mengubah invoice", parsed.GatewayStatus), nil
+	}
+
+	inv, err := u.invoiceRepo.FindByNumber(ctx, parsed.InvoiceNumber)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvoiceNotFound) {
+			return domain.NotificationResultNeedsReview, err.Error(), nil
+		}
+		return "", "", err
+	}
+
+	// Notifikasi paid tanpa nominal diperlakukan sebagai nominal tidak sesuai
+	if parsed.InvoiceStatus == domain.InvoiceStatusPaid && parsed.Amount != inv.Amount {
+		if parsed.Amount == 0 {
+			return domain.NotificationResultNeedsReview,
+				fmt.Sprintf("nominal tidak dikirim gateway, tagihan %d", inv.Amount), nil
+		}
+		return domain.NotificationResultNeedsReview,
+			fmt.Sprintf("nominal %d tidak sesuai tagihan %d", parsed.Amount, inv.Amount), nil
+	}
+
+	if inv.Status == parsed.InvoiceStatus {
+		return domain.NotificationResultDuplicate, "invoice sudah berstatus " + inv.Status, nil
+	}
+
+	if _, err := u.invoiceRepo.Transition(ctx, inv.ID, parsed.InvoiceStatus); err != nil {
+		if !errors.Is(err, domain.ErrInvalidInvoiceTransition) {
+			return "", "", err
+		}
+
+		// Notifikasi kembar yang datang bersamaan: yang kalah balapan cukup dianggap duplikat
+		if current, err := u.invoiceRepo.FindByID(ctx, inv.ID); err == nil && current.Status == parsed.InvoiceStatus {
+			return domain.NotificationResultDuplicate, "invoice sudah berstatus " + current.Status, nil
+		}
+
+		message := fmt.Sprintf("transisi %s -> %s diabaikan", inv.Status, parsed.InvoiceStatus)
+		if parsed.InvoiceStatus == domain.InvoiceStatusPaid {
+			// Uang sudah diterima tetapi invoice tidak lagi pending
+			return domain.NotificationResultNeedsReview, message, nil
+		}
+		return domain.NotificationResultIgnored, message, nil
+	}
+
+	return domain.NotificationResultProcessed, fmt.Sprintf("invoice %s -> %s", inv.Status, parsed.InvoiceStatus), nil
+}
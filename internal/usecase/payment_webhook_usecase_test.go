@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"main-service/internal/domain"
+	"main-service/internal/payment"
+	"main-service/internal/repository"
+	"testing"
+)
+
+type fakeNotificationRepo struct {
+	repository.PaymentNotificationRepository
+}
+
+func (f *fakeNotificationRepo) ExistsProcessed(ctx context.Context, provider, eventID string, excludeID uint64) (bool, error) {
+	return false, nil
+}
+
+func TestApplyPaidNotificationChecksAmount(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     int64
+		wantResult string
+	}{
+		{name: "nominal sesuai", amount: 150000, wantResult: domain.NotificationResultProcessed},
+		{name: "nominal berbeda", amount: 1000, wantResult: domain.NotificationResultNeedsReview},
+		{name: "tanpa nominal", amount: 0, wantResult: domain.NotificationResultNeedsReview},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoices := &fakeInvoiceRepo{invoice: domain.Invoice{
+				ID: 1, Number: "INV-1", Amount: 150000, Status: domain.InvoiceStatusPending,
+			}}
+			u := &paymentWebhookUsecase{repo: &fakeNotificationRepo{}, invoiceRepo: invoices}
+
+			result, msg, err := u.apply(context.Background(), &domain.PaymentNotification{ID: 1}, &payment.Notification{
+				EventID:       "evt-1",
+				InvoiceNumber: "INV-1",
+				InvoiceStatus: domain.InvoiceStatusPaid,
+				Amount:        tt.amount,
+			})
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if result != tt.wantResult {
+				t.Fatalf("result = %s (%s), want %s", result, msg, tt.wantResult)
+			}
+
+			paid := len(invoices.transitions) > 0
+			if paid != (tt.wantResult == domain.NotificationResultProcessed) {
+				t.Fatalf("transitions = %v", invoices.transitions)
+			}
+		})
+	}
+}
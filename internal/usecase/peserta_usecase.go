@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type PesertaProfileInput struct {
+	School        string  `json:"school" validate:"max=150"`
+	Jenjang       string  `json:"jenjang"`
+	Grade         int     `json:"grade" validate:"min=0,max=12"`
+	BirthDate     *string `json:"birth_date"`
+	GuardianName  string  `json:"guardian_name" validate:"max=100"`
+	GuardianPhone string  `json:"guardian_phone" validate:"max=20"`
+}
+
+type PesertaUsecase interface {
+	MyProfile(ctx context.Context, role string, pesertaID uint64) (*domain.PesertaProfile, error)
+	UpdateProfile(ctx context.Context, role string, pesertaID uint64, input PesertaProfileInput) (*domain.PesertaProfile, error)
+	Detail(ctx context.Context, role string, pesertaID uint64) (*domain.PesertaProfile, error)
+	LimitedDetail(ctx context.Context, role string, userTutorID uint64, pesertaID uint64) (*domain.PesertaLimitedProfile, error)
+}
+
+type pesertaUsecase struct {
+	repo repository.PesertaRepository
+}
+
+func NewPesertaUsecase(r repository.PesertaRepository) PesertaUsecase {
+	return &pesertaUsecase{repo: r}
+}
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+
+func (u *pesertaUsecase) MyProfile(ctx context.Context, role string, pesertaID uint64) (*domain.PesertaProfile, error) {
+	actor := policy.Actor{Role: role, PesertaID: pesertaID}
+	if err := policy.Authorize(actor, policy.PesertaProfileManage, &policy.Resource{PesertaID: pesertaID}); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, pesertaID)
+}
+
+func (u *pesertaUsecase) UpdateProfile(ctx context.Context, role string, pesertaID uint64, input PesertaProfileInput) (*domain.PesertaProfile, error) {
+	actor := policy.Actor{Role: role, PesertaID: pesertaID}
+	if err := policy.Authorize(actor, policy.PesertaProfileManage, &policy.Resource{PesertaID: pesertaID}); err != nil {
+		return nil, err
+	}
+
+	p := &domain.PesertaProfile{
+		ID:           pesertaID,
+		School:       strings.TrimSpace(input.School),
+		Jenjang:      strings.ToUpper(strings.TrimSpace(input.Jenjang)),
+		Grade:        input.Grade,
+		GuardianName: strings.TrimSpace(input.GuardianName),
+	}
+
+	if p.Jenjang != "" {
+		maxGrade, ok := domain.IsValidJenjang(p.Jenjang)
+		if !ok {
+			return nil, domain.InvalidField("jenjang", "jenjang harus salah satu dari SD, SMP, SMA, SMK, KULIAH, UMUM")
+		}
+		if maxGrade == 0 {
+			p.Grade = 0
+		} else if p.Grade < 1 || p.Grade > maxGrade {
+			return nil, domain.InvalidField("grade", fmt.Sprintf("grade untuk jenjang %s harus 1-%d", p.Jenjang, maxGrade))
+		}
+	} else if p.Grade != 0 {
+		return nil, domain.InvalidField("jenjang", "jenjang wajib diisi jika grade diisi")
+	}
+
+	if input.BirthDate != nil && strings.TrimSpace(*input.BirthDate) != "" {
+		birth, err := time.Parse("2006-01-02", strings.TrimSpace(*input.BirthDate))
+		if err != nil {
+			return nil, domain.InvalidField("birth_date", "birth_date harus berformat YYYY-MM-DD")
+		}
+		if birth.After(time.Now()) {
+			return nil, domain.InvalidField("birth_date", "birth_date tidak boleh di masa depan")
+		}
+		d := birth.Format("2006-01-02")
+		p.BirthDate = &d
+	}
+
+	// Nomor wali dinormalisasi tanpa spasi/tanda hubung
+	phone := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(input.GuardianPhone))
+	if phone != "" && !phonePattern.MatchString(phone) {
+		return nil, domain.InvalidField("guardian_phone", "guardian_phone tidak valid")
+	}
+	p.GuardianPhone = phone
+
+	if _, err := u.repo.FindByID(ctx, pesertaID); err != nil {
+		return nil, err
+	}
+	if err := u.repo.UpdateProfile(ctx, p); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, pesertaID)
+}
+
+// Detail menampilkan profil lengkap, hanya untuk admin
+func (u *pesertaUsecase) Detail(ctx context.Context, role string, pesertaID uint64) (*domain.PesertaProfile, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.PesertaView, nil); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, pesertaID)
+}
+
+// LimitedDetail menampilkan profil terbatas untuk tutor, hanya bagi peserta
+// yang terdaftar di salah satu bimbel miliknya
+func (u *pesertaUsecase) LimitedDetail(ctx context.Context, role string, userTutorID uint64, pesertaID uint64) (*domain.PesertaLimitedProfile, error) {
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	if !policy.Allowed(role, policy.PesertaViewLimited) || userTutorID == 0 {
+		return nil, policy.ErrForbidden
+	}
+
+	// Peserta dianggap "milik" tutor jika terdaftar di salah satu bimbelnya
+	enrolled, err := u.repo.IsEnrolledWithTutor(ctx, pesertaID, userTutorID)
+	if err != nil {
+		return nil, err
+	}
+	res := &policy.Resource{}
+	if enrolled {
+		res.TutorID = userTutorID
+	}
+	if err := policy.Authorize(actor, policy.PesertaViewLimited, res); err != nil {
+		return nil, err
+	}
+
+	p, err := u.repo.FindByID(ctx, pesertaID)
+	if err != nil {
+		return nil, err
+	}
+	return p.Limited(), nil
+}
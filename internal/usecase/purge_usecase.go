@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/repository"
+	"time"
+)
+
+// errPurgeDryRun membatalkan transaksi purge setelah jumlahnya dihitung
+var errPurgeDryRun = errors.New("purge dry-run")
+
+// PurgeUsecase menghapus permanen data trash yang melewati masa retensi
+type PurgeUsecase interface {
+	Purge(ctx context.Context, before time.Time, dryRun bool) (*domain.PurgeReport, error)
+}
+
+type purgeUsecase struct {
+	tx repository.TxManager
+}
+
+func NewPurgeUsecase(tx repository.TxManager) PurgeUsecase {
+	return &purgeUsecase{tx: tx}
+}
+
+// Purge berjalan dari anak ke induk (bimbel -> subject -> feature) dalam
+// satu transaksi, sehingga induk yang anaknya ikut ter-purge langsung ikut
+// terhapus. Dry-run menjalankan query yang sama lalu rollback.
+func (u *purgeUsecase) Purge(ctx context.Context, before time.Time, dryRun bool) (*domain.PurgeReport, error) {
+	report := &domain.PurgeReport{Before: before, DryRun: dryRun}
+
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		if report.Bimbels, err = repos.Bimbel.PurgeDeleted(ctx, before); err != nil {
+			return err
+		}
+		if report.Subjects, err = repos.Matpel.PurgeDeleted(ctx, before); err != nil {
+			return err
+		}
+		if report.Features, err = repos.Feature.PurgeDeleted(ctx, before); err != nil {
+			return err
+		}
+		if dryRun {
+			return errPurgeDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPurgeDryRun) {
+		return nil, err
+	}
+	return report, nil
+}
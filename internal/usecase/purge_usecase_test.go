@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/repository"
+	"testing"
+	"time"
+)
+
+func newPurgeFixture(subjectErr error) (*purgeUsecase, *fakeTx) {
+	tx := &fakeTx{repos: &repository.Repositories{
+		Bimbel:  &fakeBimbelRepo{purged: 3},
+		Matpel:  &fakeMatpelRepo{purged: 2, err: subjectErr},
+		Feature: &fakeFeatureRepo{purged: 1},
+	}}
+	return &purgeUsecase{tx: tx}, tx
+}
+
+func TestPurgeDryRunRollsBack(t *testing.T) {
+	before := time.Now().Add(-30 * 24 * time.Hour)
+
+	for _, dryRun := range []bool{true, false} {
+		u, tx := newPurgeFixture(nil)
+		report, err := u.Purge(context.Background(), before, dryRun)
+		if err != nil {
+			t.Fatalf("dryRun=%v: err = %v", dryRun, err)
+		}
+		if report.DryRun != dryRun || !report.Before.Equal(before) {
+			t.Errorf("dryRun=%v: report = %+v", dryRun, report)
+		}
+		// Dry-run menghitung dengan query yang sama, jadi angkanya harus sama
+		if report.Bimbels != 3 || report.Subjects != 2 || report.Features != 1 {
+			t.Errorf("dryRun=%v: jumlah = %d/%d/%d, want 3/2/1", dryRun, report.Bimbels, report.Subjects, report.Features)
+		}
+		rolledBack := tx.lastErr != nil
+		if rolledBack != dryRun {
+			t.Errorf("dryRun=%v: rollback = %v", dryRun, rolledBack)
+		}
+		if dryRun && !errors.Is(tx.lastErr, errPurgeDryRun) {
+			t.Errorf("dry-run di-rollback dengan %v, want errPurgeDryRun", tx.lastErr)
+		}
+	}
+}
+
+func TestPurgeReturnsRepositoryError(t *testing.T) {
+	repoErr := errors.New("db down")
+	for _, dryRun := range []bool{true, false} {
+		u, _ := newPurgeFixture(repoErr)
+		report, err := u.Purge(context.Background(), time.Now(), dryRun)
+		if !errors.Is(err, repoErr) || report != nil {
+			t.Errorf("dryRun=%v: report = %v, err = %v; want nil, %v", dryRun, report, err, repoErr)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"strings"
+)
+
+type ReviewUsecase interface {
+	List(ctx context.Context, role string, userTutorID uint64, bimbelID uint64) ([]domain.Review, error)
+	Create(ctx context.Context, role string, pesertaID uint64, bimbelID uint64, rating int, comment string) (*domain.Review, error)
+	Update(ctx context.Context, role string, pesertaID uint64, id uint64, rating int, comment string) (*domain.Review, error)
+	Reply(ctx context.Context, role string, userTutorID uint64, id uint64, reply string) (*domain.Review, error)
+	SetHidden(ctx context.Context, role string, id uint64, hidden bool, reason string) (*domain.Review, error)
+}
+
+type reviewUsecase struct {
+	repo           repository.ReviewRepository
+	enrollmentRepo repository.EnrollmentRepository
+	bimbelRepo     repository.BimbelRepository
+}
+
+func NewReviewUsecase(r repository.ReviewRepository, er repository.EnrollmentRepository, br repository.BimbelRepository) ReviewUsecase {
+	return &reviewUsecase{repo: r, enrollmentRepo: er, bimbelRepo: br}
+}
+
+func validateRating(rating int) error {
+	if rating < 1 || rating > 5 {
+		return domain.ErrInvalidRating
+	}
+	return nil
+}
+
+// List menampilkan review publik; admin juga melihat review yang disembunyikan.
+// Review bimbel draft, review, dan arsip hanya terlihat oleh pemilik dan admin.
+func (u *reviewUsecase) List(ctx context.Context, role string, userTutorID uint64, bimbelID uint64) ([]domain.Review, error) {
+	b, err := u.bimbelRepo.FindByID(ctx, bimbelID)
+	if err != nil {
+		return nil, err
+	}
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	if !domain.BimbelVisible(b.Status) && policy.Authorize(actor, policy.BimbelUpdate, &policy.Resource{TutorID: b.TutorID}) != nil {
+		return nil, domain.ErrBimbelNotFound
+	}
+	includeHidden := policy.Authorize(policy.Actor{Role: role}, policy.ReviewViewHidden, nil) == nil
+	return u.repo.FindByBimbel(ctx, bimbelID, includeHidden)
+}
+
+func (u *reviewUsecase) Create(ctx context.Context, role string, pesertaID uint64, bimbelID uint64, rating int, comment string) (*domain.Review, error) {
+	if err := policy.Authorize(policy.Actor{Role: role, PesertaID: pesertaID}, policy.ReviewCreate, nil); err != nil {
+		return nil, err
+	}
+	if pesertaID == 0 {
+		return nil, policy.ErrForbidden
+	}
+	if err := validateRating(rating); err != nil {
+		return nil, err
+	}
+
+	enrollment, err := u.enrollmentRepo.FindByBimbelAndPeserta(ctx, bimbelID, pesertaID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEnrollmentNotFound) {
+			return nil, domain.ErrReviewNotAllowed
+		}
+		return nil, err
+	}
+	if enrollment.Status != domain.EnrollmentStatusCompleted {
+		return nil, domain.ErrReviewNotAllowed
+	}
+
+	rv := &domain.Review{
+		BimbelID:     bimbelID,
+		PesertaID:    pesertaID,
+		EnrollmentID: enrollment.ID,
+		Rating:       rating,
+		Comment:      strings.TrimSpace(comment),
+	}
+	if err := u.repo.Create(ctx, rv); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, rv.ID)
+}
+
+func (u *reviewUsecase) Update(ctx context.Context, role string, pesertaID uint64, id uint64, rating int, comment string) (*domain.Review, error) {
+	if err := validateRating(rating); err != nil {
+		return nil, err
+	}
+
+	rv, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	actor := policy.Actor{Role: role, PesertaID: pesertaID}
+	if err := policy.Authorize(actor, policy.ReviewUpdate, &policy.Resource{PesertaID: rv.PesertaID}); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.Update(ctx, id, rating, strings.TrimSpace(comment)); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, id)
+}
+
+func (u *reviewUsecase) Reply(ctx context.Context, role string, userTutorID uint64, id uint64, reply string) (*domain.Review, error) {
+	if !policy.Allowed(role, policy.ReviewReply) {
+		return nil, policy.ErrForbidden
+	}
+
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return nil, domain.InvalidField("reply", "balasan wajib diisi")
+	}
+
+	rv, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	b, err := u.bimbelRepo.FindByID(ctx, rv.BimbelID)
+	if err != nil {
+		return nil, err
+	}
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	if err := policy.Authorize(actor, policy.ReviewReply, &policy.Resource{TutorID: b.TutorID}); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.Reply(ctx, id, reply); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, id)
+}
+
+func (u *reviewUsecase) SetHidden(ctx context.Context, role string, id uint64, hidden bool, reason string) (*domain.Review, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.ReviewModerate, nil); err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if hidden && reason == "" {
+		return nil, domain.InvalidField("reason", "alasan wajib diisi saat menyembunyikan review")
+	}
+
+	if err := u.repo.SetHidden(ctx, id, hidden, reason); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, id)
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/repository"
+	"testing"
+)
+
+type fakeReviewRepo struct {
+	repository.ReviewRepository
+	reviews []domain.Review
+	listed  bool
+}
+
+func (f *fakeReviewRepo) FindByBimbel(ctx context.Context, bimbelID uint64, includeHidden bool) ([]domain.Review, error) {
+	f.listed = true
+	return f.reviews, nil
+}
+
+func TestReviewListHidesInvisibleBimbel(t *testing.T) {
+	cases := []struct {
+		name        string
+		status      string
+		role        string
+		userTutorID uint64
+		wantErr     error
+	}{
+		{"published publik", domain.BimbelStatusPublished, domain.RolePeserta, 0, nil},
+		{"closed publik", domain.BimbelStatusClosed, "", 0, nil},
+		{"draft publik", domain.BimbelStatusDraft, domain.RolePeserta, 0, domain.ErrBimbelNotFound},
+		{"archived tutor lain", domain.BimbelStatusArchived, domain.RoleTutor, 11, domain.ErrBimbelNotFound},
+		{"draft pemilik", domain.BimbelStatusDraft, domain.RoleTutor, 10, nil},
+		{"archived admin", domain.BimbelStatusArchived, domain.RoleAdmin, 0, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reviews := &fakeReviewRepo{reviews: []domain.Review{{ID: 1, BimbelID: 1}}}
+			bimbels := &fakeBimbelRepo{bimbel: domain.Bimbel{ID: 1, TutorID: 10, Status: tc.status}}
+			u := &reviewUsecase{repo: reviews, bimbelRepo: bimbels}
+
+			got, err := u.List(context.Background(), tc.role, tc.userTutorID, 1)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				if reviews.listed {
+					t.Error("review dibaca walaupun bimbel tidak terlihat")
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Errorf("List = %+v, want 1 review", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"sort"
+	"time"
+)
+
+const (
+	maxSessionDuration    = 12 * time.Hour
+	maxRecurrenceSessions = 200
+	maxRecurrenceSpan     = 366 * 24 * time.Hour
+)
+
+type SessionUsecase interface {
+	List(ctx context.Context, role string, userTutorID uint64, bimbelID uint64, from, to *time.Time) ([]domain.BimbelSession, error)
+	Create(ctx context.Context, role string, userTutorID uint64, s *domain.BimbelSession) (*domain.BimbelSession, error)
+	CreateRecurrence(ctx context.Context, role string, userTutorID uint64, rec *domain.SessionRecurrence) ([]domain.BimbelSession, error)
+	Update(ctx context.Context, role string, userTutorID uint64, s *domain.BimbelSession) (*domain.BimbelSession, error)
+	Delete(ctx context.Context, role string, userTutorID uint64, bimbelID, id uint64) error
+	DeleteRecurrence(ctx context.Context, role string, userTutorID uint64, bimbelID, recurrenceID uint64) error
+}
+
+type sessionUsecase struct {
+	repo       repository.SessionRepository
+	bimbelRepo repository.BimbelRepository
+}
+
+func NewSessionUsecase(r repository.SessionRepository, br repository.BimbelRepository) SessionUsecase {
+	return &sessionUsecase{repo: r, bimbelRepo: br}
+}
+
+// ownedBimbel memastikan bimbel ada dan boleh dikelola oleh user
+func (u *sessionUsecase) ownedBimbel(ctx context.Context, role string, userTutorID uint64, bimbelID uint64) (*domain.Bimbel, error) {
+	b, err := u.bimbelRepo.FindByID(ctx, bimbelID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	if err := policy.Authorize(actor, policy.SessionManage, &policy.Resource{TutorID: b.TutorID}); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func validateSessionTime(s *domain.BimbelSession) error {
+	if s.Timezone == "" {
+		s.Timezone = "Asia/Jakarta"
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return domain.InvalidField("timezone", "timezone tidak valid")
+	}
+	if !s.EndAt.After(s.StartAt) {
+		return domain.InvalidField("end_at", "end_at harus setelah start_at")
+	}
+	if s.EndAt.Sub(s.StartAt) > maxSessionDuration {
+		return domain.InvalidField("end_at", "durasi sesi maksimal 12 jam")
+	}
+	return nil
+}
+
+// List menampilkan jadwal bimbel; jadwal bimbel draft, review, dan arsip
+// hanya terlihat oleh pemilik dan admin
+func (u *sessionUsecase) List(ctx context.Context, role string, userTutorID uint64, bimbelID uint64, from, to *time.Time) ([]domain.BimbelSession, error) {
+	b, err := u.bimbelRepo.FindByID(ctx, bimbelID)
+	if err != nil {
+		return nil, err
+	}
+	actor := policy.Actor{Role: role, TutorID: userTutorID}
+	if !domain.BimbelVisible(b.Status) && policy.Authorize(actor, policy.BimbelUpdate, &policy.Resource{TutorID: b.TutorID}) != nil {
+		return nil, domain.ErrBimbelNotFound
+	}
+	return u.repo.FindByBimbel(ctx, bimbelID, from, to)
+}
+
+func (u *sessionUsecase) Create(ctx context.Context, role string, userTutorID uint64, s *domain.BimbelSession) (*domain.BimbelSession, error) {
+	b, err := u.ownedBimbel(ctx, role, userTutorID, s.BimbelID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSessionTime(s); err != nil {
+		return nil, err
+	}
+	if s.Title == "" {
+		s.Title = b.Name
+	}
+
+	created, err := u.repo.CreateSessions(ctx, b.TutorID, nil, []domain.BimbelSession{*s})
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, s.BimbelID, created[0].ID)
+}
+
+func (u *sessionUsecase) CreateRecurrence(ctx context.Context, role string, userTutorID uint64, rec *domain.SessionRecurrence) ([]domain.BimbelSession, error) {
+	b, err := u.ownedBimbel(ctx, role, userTutorID, rec.BimbelID)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Title == "" {
+		rec.Title = b.Name
+	}
+
+	sessions, err := expandRecurrence(rec)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.repo.CreateSessions(ctx, b.TutorID, rec, sessions); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByBimbel(ctx, rec.BimbelID, nil, nil)
+}
+
+func (u *sessionUsecase) Update(ctx context.Context, role string, userTutorID uint64, s *domain.BimbelSession) (*domain.BimbelSession, error) {
+	b, err := u.ownedBimbel(ctx, role, userTutorID, s.BimbelID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := u.repo.FindByID(ctx, s.BimbelID, s.ID)
+	if err != nil {
+		return nil, err
+	}
+	if s.Title == "" {
+		s.Title = existing.Title
+	}
+	if s.Timezone == "" {
+		s.Timezone = existing.Timezone
+	}
+	if err := validateSessionTime(s); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.Update(ctx, b.TutorID, s); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, s.BimbelID, s.ID)
+}
+
+func (u *sessionUsecase) Delete(ctx context.Context, role string, userTutorID uint64, bimbelID, id uint64) error {
+	if _, err := u.ownedBimbel(ctx, role, userTutorID, bimbelID); err != nil {
+		return err
+	}
+	return u.repo.Delete(ctx, bimbelID, id)
+}
+
+func (u *sessionUsecase) DeleteRecurrence(ctx context.Context, role string, userTutorID uint64, bimbelID, recurrenceID uint64) error {
+	if _, err := u.ownedBimbel(ctx, role, userTutorID, bimbelID); err != nil {
+		return err
+	}
+	return u.repo.DeleteRecurrence(ctx, bimbelID, recurrenceID, time.Now())
+}
+
+// expandRecurrence memecah aturan mingguan menjadi daftar sesi konkret.
+// Jam mulai dihitung di zona waktu aturan sehingga pergantian DST tetap
+// menghasilkan jam lokal yang sama.
+func expandRecurrence(rec *domain.SessionRecurrence) ([]domain.BimbelSession, error) {
+	if rec.Timezone == "" {
+		rec.Timezone = "Asia/Jakarta"
+	}
+	loc, err := time.LoadLocation(rec.Timezone)
+	if err != nil {
+		return nil, domain.InvalidField("timezone", "timezone tidak valid")
+	}
+
+	startDate, err := time.ParseInLocation("2006-01-02", rec.StartDate, loc)
+	if err != nil {
+		return nil, domain.InvalidField("start_date", "start_date harus berformat YYYY-MM-DD")
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", rec.EndDate, loc)
+	if err != nil {
+		return nil, domain.InvalidField("end_date", "end_date harus berformat YYYY-MM-DD")
+	}
+	if endDate.Before(startDate) {
+		return nil, domain.InvalidField("end_date", "end_date tidak boleh sebelum start_date")
+	}
+	if endDate.Sub(startDate) > maxRecurrenceSpan {
+		return nil, domain.InvalidField("end_date", "rentang jadwal berulang maksimal 1 tahun")
+	}
+
+	clock, err := time.Parse("15:04", rec.StartTime)
+	if err != nil {
+		return nil, domain.InvalidField("start_time", "start_time harus berformat HH:MM")
+	}
+
+	duration := time.Duration(rec.DurationMinutes) * time.Minute
+	if duration <= 0 || duration > maxSessionDuration {
+		return nil, domain.InvalidField("duration_minutes", "duration_minutes harus antara 1 dan 720")
+	}
+
+	if len(rec.Weekdays) == 0 {
+		return nil, domain.InvalidField("weekdays", "weekdays wajib diisi")
+	}
+	days := map[time.Weekday]bool{}
+	for _, d := range rec.Weekdays {
+		if d < 0 || d > 6 {
+			return nil, domain.InvalidField("weekdays", "weekdays harus bernilai 0 (Minggu) s/d 6 (Sabtu)")
+		}
+		days[time.Weekday(d)] = true
+	}
+	rec.Weekdays = rec.Weekdays[:0]
+	for d := range days {
+		rec.Weekdays = append(rec.Weekdays, int(d))
+	}
+	sort.Ints(rec.Weekdays)
+
+	var sessions []domain.BimbelSession
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+		if !days[d.Weekday()] {
+			continue
+		}
+
+		start := time.Date(d.Year(), d.Month(), d.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		sessions = append(sessions, domain.BimbelSession{
+			BimbelID: rec.BimbelID,
+			Title:    rec.Title,
+			StartAt:  start,
+			EndAt:    start.Add(duration),
+			Timezone: rec.Timezone,
+		})
+
+		if len(sessions) > maxRecurrenceSessions {
+			return nil, domain.Validation(fmt.Sprintf("jadwal berulang maksimal %d sesi", maxRecurrenceSessions), nil)
+		}
+	}
+
+	if len(sessions) == 0 {
+		return nil, domain.Validation("tidak ada sesi yang jatuh pada rentang tanggal tersebut", nil)
+	}
+	return sessions, nil
+}
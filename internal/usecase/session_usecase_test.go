@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"testing"
+)
+
+func TestSessionListHidesInvisibleBimbel(t *testing.T) {
+	cases := []struct {
+		name        string
+		status      string
+		role        string
+		userTutorID uint64
+		wantErr     error
+	}{
+		{"published peserta", domain.BimbelStatusPublished, domain.RolePeserta, 0, nil},
+		{"closed peserta", domain.BimbelStatusClosed, domain.RolePeserta, 0, nil},
+		{"draft peserta", domain.BimbelStatusDraft, domain.RolePeserta, 0, domain.ErrBimbelNotFound},
+		{"pending review tutor lain", domain.BimbelStatusPendingReview, domain.RoleTutor, 11, domain.ErrBimbelNotFound},
+		{"archived tutor lain", domain.BimbelStatusArchived, domain.RoleTutor, 11, domain.ErrBimbelNotFound},
+		{"draft pemilik", domain.BimbelStatusDraft, domain.RoleTutor, 10, nil},
+		{"archived admin", domain.BimbelStatusArchived, domain.RoleAdmin, 0, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sessions := &fakeSessionRepo{session: domain.BimbelSession{ID: 1, BimbelID: 1}}
+			bimbels := &fakeBimbelRepo{bimbel: domain.Bimbel{ID: 1, TutorID: 10, Status: tc.status}}
+			u := &sessionUsecase{repo: sessions, bimbelRepo: bimbels}
+
+			got, err := u.List(context.Background(), tc.role, tc.userTutorID, 1, nil, nil)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				if sessions.listed {
+					t.Error("jadwal dibaca walaupun bimbel tidak terlihat")
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Errorf("List = %+v, want 1 sesi", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"strings"
+)
+
+type TutorProfileInput struct {
+	Bio        string   `json:"bio" validate:"max=2000"`
+	Education  string   `json:"education" validate:"max=255"`
+	Experience string   `json:"experience" validate:"max=2000"`
+	City       string   `json:"city" validate:"max=100"`
+	SubjectIDs []uint64 `json:"subject_ids" validate:"max=20"`
+}
+
+type TutorUsecase interface {
+	PublicProfile(ctx context.Context, id uint64) (*domain.TutorProfile, error)
+	MyProfile(ctx context.Context, role string, tutorID uint64) (*domain.TutorProfile, error)
+	UpdateProfile(ctx context.Context, role string, tutorID uint64, input TutorProfileInput) (*domain.TutorProfile, error)
+	UpdateAvatar(ctx context.Context, role string, tutorID uint64, avatarKey string) (oldAvatarKey string, err error)
+	AddDocument(ctx context.Context, role string, tutorID uint64, doc *domain.TutorDocument) error
+	SubmitVerification(ctx context.Context, role string, tutorID uint64) (*domain.TutorProfile, error)
+	ListVerifications(ctx context.Context, role string, status string) ([]domain.TutorProfile, error)
+	AdminDetail(ctx context.Context, role string, id uint64) (*domain.TutorProfile, error)
+	Document(ctx context.Context, role string, tutorID uint64, docID uint64) (*domain.TutorDocument, error)
+	Approve(ctx context.Context, role string, adminUserID uint64, id uint64) (*domain.TutorProfile, error)
+	Reject(ctx context.Context, role string, adminUserID uint64, id uint64, reason string) (*domain.TutorProfile, error)
+}
+
+type tutorUsecase struct {
+	repo       repository.TutorRepository
+	bimbelRepo repository.BimbelRepository
+	matpelRepo repository.MatpelRepository
+}
+
+func NewTutorUsecase(r repository.TutorRepository, br repository.BimbelRepository, mr repository.MatpelRepository) TutorUsecase {
+	return &tutorUsecase{repo: r, bimbelRepo: br, matpelRepo: mr}
+}
+
+// PublicProfile menampilkan profil tutor beserta bimbel yang sudah dipublikasikan.
+// Alasan penolakan verifikasi tidak ikut ditampilkan ke publik.
+func (u *tutorUsecase) PublicProfile(ctx context.Context, id uint64) (*domain.TutorProfile, error) {
+	p, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.RejectionReason = ""
+
+	bimbels, err := u.bimbelRepo.FindByTutor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Bimbels = []domain.Bimbel{}
+	for _, b := range bimbels {
+		if domain.BimbelVisible(b.Status) {
+			p.Bimbels = append(p.Bimbels, b)
+		}
+	}
+	return p, nil
+}
+
+func (u *tutorUsecase) MyProfile(ctx context.Context, role string, tutorID uint64) (*domain.TutorProfile, error) {
+	actor := policy.Actor{Role: role, TutorID: tutorID}
+	if err := policy.Authorize(actor, policy.TutorProfileManage, &policy.Resource{TutorID: tutorID}); err != nil {
+		return nil, err
+	}
+	return u.withDocuments(ctx, tutorID)
+}
+
+func (u *tutorUsecase) UpdateProfile(ctx context.Context, role string, tutorID uint64, input TutorProfileInput) (*domain.TutorProfile, error) {
+	actor := policy.Actor{Role: role, TutorID: tutorID}
+	if err := policy.Authorize(actor, policy.TutorProfileManage, &policy.Resource{TutorID: tutorID}); err != nil {
+		return nil, err
+	}
+
+	p := &domain.TutorProfile{
+		ID:         tutorID,
+		Bio:        strings.TrimSpace(input.Bio),
+		Education:  strings.TrimSpace(input.Education),
+		Experience: strings.TrimSpace(input.Experience),
+		City:       strings.TrimSpace(input.City),
+	}
+	if len(p.Bio) > 2000 {
+		return nil, domain.InvalidField("bio", "bio maksimal 2000 karakter")
+	}
+	if len(p.City) > 100 {
+		return nil, domain.InvalidField("city", "city maksimal 100 karakter")
+	}
+
+	subjectIDs := []uint64{}
+	seen := map[uint64]bool{}
+	for _, id := range input.SubjectIDs {
+		if id == 0 || seen[id] {
+			continue
+		}
+		if _, err := u.matpelRepo.GetByID(ctx, id); err != nil {
+			return nil, domain.InvalidField("subject_ids", fmt.Sprintf("subject_id %d tidak ditemukan", id))
+		}
+		seen[id] = true
+		subjectIDs = append(subjectIDs, id)
+	}
+
+	if err := u.repo.UpdateProfile(ctx, p); err != nil {
+		return nil, err
+	}
+	if input.SubjectIDs != nil {
+		if err := u.repo.SetSubjects(ctx, tutorID, subjectIDs); err != nil {
+			return nil, err
+		}
+	}
+	return u.withDocuments(ctx, tutorID)
+}
+
+func (u *tutorUsecase) UpdateAvatar(ctx context.Context, role string, tutorID uint64, avatarKey string) (string, error) {
+	actor := policy.Actor{Role: role, TutorID: tutorID}
+	if err := policy.Authorize(actor, policy.TutorProfileManage, &policy.Resource{TutorID: tutorID}); err != nil {
+		return "", err
+	}
+
+	p, err := u.repo.FindByID(ctx, tutorID)
+	if err != nil {
+		return "", err
+	}
+	if err := u.repo.UpdateAvatar(ctx, tutorID, avatarKey); err != nil {
+		return "", err
+	}
+	return p.Avatar, nil
+}
+
+func (u *tutorUsecase) AddDocument(ctx context.Context, role string, tutorID uint64, doc *domain.TutorDocument) error {
+	actor := policy.Actor{Role: role, TutorID: tutorID}
+	if err := policy.Authorize(actor, policy.TutorProfileManage, &policy.Resource{TutorID: tutorID}); err != nil {
+		return err
+	}
+
+	p, err := u.repo.FindByID(ctx, tutorID)
+	if err != nil {
+		return err
+	}
+	if p.VerificationStatus == domain.TutorVerificationVerified || p.VerificationStatus == domain.TutorVerificationPending {
+		return domain.Conflict("TUTOR_DOCUMENTS_LOCKED", "dokumen tidak dapat ditambahkan saat status verifikasi "+p.VerificationStatus)
+	}
+
+	doc.TutorID = tutorID
+	doc.DocType = strings.TrimSpace(doc.DocType)
+	if doc.DocType == "" {
+		return domain.InvalidField("doc_type", "doc_type wajib diisi")
+	}
+	return u.repo.AddDocument(ctx, doc)
+}
+
+// SubmitVerification mengajukan verifikasi; tutor yang ditolak boleh mengajukan ulang
+func (u *tutorUsecase) SubmitVerification(ctx context.Context, role string, tutorID uint64) (*domain.TutorProfile, error) {
+	actor := policy.Actor{Role: role, TutorID: tutorID}
+	if err := policy.Authorize(actor, policy.TutorProfileManage, &policy.Resource{TutorID: tutorID}); err != nil {
+		return nil, err
+	}
+
+	docs, err := u.repo.FindDocuments(ctx, tutorID)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, domain.ErrVerificationNoDocuments
+	}
+
+	from := []string{domain.TutorVerificationUnverified, domain.TutorVerificationRejected}
+	if err := u.repo.SetVerificationStatus(ctx, tutorID, from, domain.TutorVerificationPending, "", nil); err != nil {
+		return nil, err
+	}
+	return u.withDocuments(ctx, tutorID)
+}
+
+func (u *tutorUsecase) ListVerifications(ctx context.Context, role string, status string) ([]domain.TutorProfile, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.TutorVerify, nil); err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case "":
+		status = domain.TutorVerificationPending
+	case domain.TutorVerificationUnverified, domain.TutorVerificationPending,
+		domain.TutorVerificationVerified, domain.TutorVerificationRejected:
+	default:
+		return nil, domain.InvalidField("verification_status", "status verifikasi tidak valid")
+	}
+	return u.repo.FindByVerificationStatus(ctx, status)
+}
+
+func (u *tutorUsecase) AdminDetail(ctx context.Context, role string, id uint64) (*domain.TutorProfile, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.TutorVerify, nil); err != nil {
+		return nil, err
+	}
+	return u.withDocuments(ctx, id)
+}
+
+// Document hanya bisa diakses admin atau tutor pemilik dokumen
+func (u *tutorUsecase) Document(ctx context.Context, role string, tutorID uint64, docID uint64) (*domain.TutorDocument, error) {
+	actor := policy.Actor{Role: role, TutorID: tutorID}
+	if err := policy.Authorize(actor, policy.TutorVerify, nil); err != nil {
+		if err := policy.Authorize(actor, policy.TutorProfileManage, &policy.Resource{TutorID: tutorID}); err != nil {
+			return nil, err
+		}
+	}
+	return u.repo.FindDocument(ctx, tutorID, docID)
+}
+
+func (u *tutorUsecase) Approve(ctx context.Context, role string, adminUserID uint64, id uint64) (*domain.TutorProfile, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.TutorVerify, nil); err != nil {
+		return nil, err
+	}
+
+	from := []string{domain.TutorVerificationPending}
+	if err := u.repo.SetVerificationStatus(ctx, id, from, domain.TutorVerificationVerified, "", &adminUserID); err != nil {
+		return nil, err
+	}
+	return u.withDocuments(ctx, id)
+}
+
+func (u *tutorUsecase) Reject(ctx context.Context, role string, adminUserID uint64, id uint64, reason string) (*domain.TutorProfile, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.TutorVerify, nil); err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, domain.InvalidField("reason", "alasan penolakan wajib diisi")
+	}
+
+	from := []string{domain.TutorVerificationPending}
+	if err := u.repo.SetVerificationStatus(ctx, id, from, domain.TutorVerificationRejected, reason, &adminUserID); err != nil {
+		return nil, err
+	}
+	return u.withDocuments(ctx, id)
+}
+
+func (u *tutorUsecase) withDocuments(ctx context.Context, id uint64) (*domain.TutorProfile, error) {
+	p, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Documents, err = u.repo.FindDocuments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
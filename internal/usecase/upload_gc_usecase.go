@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"main-service/internal/domain"
+	"main-service/internal/repository"
+	"main-service/internal/storage"
+	"time"
+)
+
+// thumbnailPrefix adalah prefix object thumbnail bimbel (beserta variannya)
+const thumbnailPrefix = "thumbnails/"
+
+// UploadGCUsecase membersihkan file upload yang tidak dirujuk database lagi,
+// mis. thumbnail dari request create yang gagal atau bimbel yang sudah di-purge
+type UploadGCUsecase interface {
+	// Run dengan dryRun=true hanya melaporkan orphan tanpa menghapus
+	Run(ctx context.Context, dryRun bool) (*domain.UploadGCReport, error)
+}
+
+type uploadGCUsecase struct {
+	bimbelRepo repository.BimbelRepository
+	store      storage.BlobStore
+	grace      time.Duration
+}
+
+// grace melindungi upload yang baru saja disimpan tetapi barisnya belum ter-commit
+func NewUploadGCUsecase(bimbelRepo repository.BimbelRepository, store storage.BlobStore, grace time.Duration) UploadGCUsecase {
+	return &uploadGCUsecase{bimbelRepo: bimbelRepo, store: store, grace: grace}
+}
+
+func (u *uploadGCUsecase) Run(ctx context.Context, dryRun bool) (*domain.UploadGCReport, error) {
+	report := &domain.UploadGCReport{
+		Prefix: thumbnailPrefix,
+		DryRun: dryRun,
+		Cutoff: time.Now().Add(-u.grace),
+	}
+
+	// Referensi dibaca sebelum listing: object yang di-upload setelahnya
+	// pasti lebih baru dari cutoff sehingga tidak ikut terhapus
+	inUse, err := u.bimbelRepo.ThumbnailKeysInUse(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.store.List(ctx, thumbnailPrefix, func(obj storage.ObjectInfo) error {
+		report.Scanned++
+		switch {
+		case inUse[obj.Key]:
+			report.Referenced++
+		case obj.ModTime.After(report.Cutoff):
+			report.InGracePeriod++
+		default:
+			report.Orphans = append(report.Orphans, domain.OrphanUpload{Key: obj.Key, Size: obj.Size, ModTime: obj.ModTime})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	// Hapus setelah listing selesai supaya tidak mengubah isi direktori/bucket yang sedang di-iterasi
+	for i := range report.Orphans {
+		o := &report.Orphans[i]
+		if err := u.store.Delete(ctx, o.Key); err != nil {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			log.Printf("Delete orphan upload %s failed: %v", o.Key, err)
+			report.Failed++
+			continue
+		}
+		o.Deleted = true
+		report.Deleted++
+		report.DeletedBytes += o.Size
+	}
+	return report, nil
+}
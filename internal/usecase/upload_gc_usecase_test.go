@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"main-service/internal/storage"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newGCFixture menyimpan object thumbnail dengan umur tertentu di LocalStore sementara
+func newGCFixture(t *testing.T, ages map[string]time.Duration) (string, *storage.LocalStore) {
+	t.Helper()
+	root := t.TempDir()
+	store := storage.NewLocalStore(root, "http://app/uploads", "http://app/api/v1/files", "rahasia")
+	for key, age := range ages {
+		if err := store.Put(context.Background(), key, strings.NewReader("img"), 3, "image/jpeg"); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(-age)
+		if err := os.Chtimes(filepath.Join(root, filepath.FromSlash(key)), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root, store
+}
+
+func exists(root, key string) bool {
+	_, err := os.Stat(filepath.Join(root, filepath.FromSlash(key)))
+	return err == nil
+}
+
+func TestUploadGCGraceWindow(t *testing.T) {
+	const grace = 24 * time.Hour
+	ages := map[string]time.Duration{
+		"thumbnails/dipakai.jpg":       72 * time.Hour,
+		"thumbnails/dipakai_320.webp":  72 * time.Hour,
+		"thumbnails/orphan_lama.jpg":   48 * time.Hour,
+		"thumbnails/orphan_batas.jpg":  grace + time.Minute,
+		"thumbnails/orphan_baru.jpg":   time.Hour,
+		"thumbnails/orphan_hampir.jpg": grace - time.Minute,
+		"avatars/bukan_thumbnail.jpg":  72 * time.Hour,
+	}
+	inUse := map[string]bool{"thumbnails/dipakai.jpg": true, "thumbnails/dipakai_320.webp": true}
+
+	for _, dryRun := range []bool{true, false} {
+		name := "hapus"
+		if dryRun {
+			name = "dry-run"
+		}
+		t.Run(name, func(t *testing.T) {
+			root, store := newGCFixture(t, ages)
+			u := NewUploadGCUsecase(&fakeBimbelRepo{thumbnails: inUse}, store, grace)
+
+			report, err := u.Run(context.Background(), dryRun)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if report.Scanned != 6 || report.Referenced != 2 || report.InGracePeriod != 2 || len(report.Orphans) != 2 {
+				t.Errorf("report = scanned %d, referenced %d, grace %d, orphans %d; want 6, 2, 2, 2",
+					report.Scanned, report.Referenced, report.InGracePeriod, len(report.Orphans))
+			}
+			orphans := map[string]bool{}
+			for _, o := range report.Orphans {
+				orphans[o.Key] = true
+				if o.Deleted == dryRun {
+					t.Errorf("%s: Deleted = %v pada dryRun=%v", o.Key, o.Deleted, dryRun)
+				}
+			}
+			if !orphans["thumbnails/orphan_lama.jpg"] || !orphans["thumbnails/orphan_batas.jpg"] {
+				t.Errorf("orphans = %v", orphans)
+			}
+
+			wantDeleted := 2
+			if dryRun {
+				wantDeleted = 0
+			}
+			if report.Deleted != wantDeleted || report.DeletedBytes != int64(3*wantDeleted) {
+				t.Errorf("deleted = %d (%d bytes), want %d", report.Deleted, report.DeletedBytes, wantDeleted)
+			}
+
+			for key := range ages {
+				want := dryRun || !orphans[key]
+				if exists(root, key) != want {
+					t.Errorf("%s: ada = %v, want %v", key, !want, want)
+				}
+			}
+		})
+	}
+}
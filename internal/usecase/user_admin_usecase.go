@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/policy"
+	"main-service/internal/repository"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type CreateUserInput struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=150"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	Role     string `json:"role" validate:"required,oneof=admin tutor peserta"`
+}
+
+type UserAdminUsecase interface {
+	List(ctx context.Context, role string, filterRole string, includeDeleted bool) ([]domain.User, error)
+	Detail(ctx context.Context, role string, id uint64) (*domain.User, error)
+	Create(ctx context.Context, role string, input CreateUserInput) (*domain.User, error)
+	ChangeRole(ctx context.Context, role string, actorID uint64, id uint64, newRole string) (*domain.User, error)
+	SetActive(ctx context.Context, role string, actorID uint64, id uint64, active bool) (*domain.User, error)
+	Delete(ctx context.Context, role string, actorID uint64, id uint64) error
+	Restore(ctx context.Context, role string, id uint64) (*domain.User, error)
+}
+
+type userAdminUsecase struct {
+	repo repository.UserRepository
+	tx   repository.TxManager
+}
+
+func NewUserAdminUsecase(r repository.UserRepository, tx repository.TxManager) UserAdminUsecase {
+	return &userAdminUsecase{repo: r, tx: tx}
+}
+
+func (u *userAdminUsecase) List(ctx context.Context, role string, filterRole string, includeDeleted bool) ([]domain.User, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.UserManage, nil); err != nil {
+		return nil, err
+	}
+	if filterRole != "" && !domain.IsValidRole(filterRole) {
+		return nil, domain.ErrInvalidRole
+	}
+	return u.repo.List(ctx, filterRole, includeDeleted)
+}
+
+func (u *userAdminUsecase) Detail(ctx context.Context, role string, id uint64) (*domain.User, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.UserManage, nil); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, id)
+}
+
+// Create membuat user dengan role apa pun, termasuk admin. Email dianggap
+// sudah terverifikasi karena dibuat langsung oleh admin.
+func (u *userAdminUsecase) Create(ctx context.Context, role string, input CreateUserInput) (*domain.User, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.UserManage, nil); err != nil {
+		return nil, err
+	}
+
+	input.Name = strings.TrimSpace(input.Name)
+	input.Email = strings.TrimSpace(input.Email)
+	if input.Name == "" || input.Email == "" || input.Password == "" || input.Role == "" {
+		return nil, domain.Validation("nama, email, password, dan role wajib diisi", nil)
+	}
+	if !domain.IsValidRole(input.Role) {
+		return nil, domain.ErrInvalidRole
+	}
+	if len(input.Password) < 8 {
+		return nil, domain.InvalidField("password", "password minimal 8 karakter")
+	}
+
+	exists, err := u.repo.ExistsEmail(ctx, input.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, domain.ErrEmailTaken
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.New("gagal mengenkripsi password")
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		Name:            input.Name,
+		Email:           input.Email,
+		Password:        string(hashed),
+		Role:            input.Role,
+		IsActive:        true,
+		EmailVerifiedAt: &now,
+	}
+	if err := u.repo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, user.ID)
+}
+
+// ChangeRole juga mencabut semua sesi user karena role tersimpan di access
+// token. Perubahan role dan pencabutan sesi berada dalam satu transaksi.
+func (u *userAdminUsecase) ChangeRole(ctx context.Context, role string, actorID uint64, id uint64, newRole string) (*domain.User, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.UserManage, nil); err != nil {
+		return nil, err
+	}
+	if actorID == id {
+		return nil, domain.ErrCannotModifySelf
+	}
+	if !domain.IsValidRole(newRole) {
+		return nil, domain.ErrInvalidRole
+	}
+
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if err := repos.User.ChangeRole(ctx, id, newRole); err != nil {
+			return err
+		}
+		_, err := repos.AuthSession.RevokeAllByUser(ctx, id, domain.SessionRevokedRoleChanged)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, id)
+}
+
+func (u *userAdminUsecase) SetActive(ctx context.Context, role string, actorID uint64, id uint64, active bool) (*domain.User, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.UserManage, nil); err != nil {
+		return nil, err
+	}
+	if actorID == id {
+		return nil, domain.ErrCannotModifySelf
+	}
+
+	err := u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if err := repos.User.SetActive(ctx, id, active); err != nil {
+			return err
+		}
+		if active {
+			return nil
+		}
+		_, err := repos.AuthSession.RevokeAllByUser(ctx, id, domain.SessionRevokedInactive)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, id)
+}
+
+func (u *userAdminUsecase) Delete(ctx context.Context, role string, actorID uint64, id uint64) error {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.UserManage, nil); err != nil {
+		return err
+	}
+	if actorID == id {
+		return domain.ErrCannotModifySelf
+	}
+
+	return u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if err := repos.User.SoftDelete(ctx, id); err != nil {
+			return err
+		}
+		_, err := repos.AuthSession.RevokeAllByUser(ctx, id, domain.SessionRevokedDeleted)
+		return err
+	})
+}
+
+func (u *userAdminUsecase) Restore(ctx context.Context, role string, id uint64) (*domain.User, error) {
+	if err := policy.Authorize(policy.Actor{Role: role}, policy.UserManage, nil); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.repo.FindByID(ctx, id)
+}
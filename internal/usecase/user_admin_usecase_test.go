@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/repository"
+	"testing"
+)
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	user  domain.User
+	steps *[]string
+}
+
+func (f *fakeUserRepo) FindByID(ctx context.Context, id uint64) (*domain.User, error) {
+	u := f.user
+	return &u, nil
+}
+
+func (f *fakeUserRepo) ChangeRole(ctx context.Context, id uint64, role string) error {
+	*f.steps = append(*f.steps, "change_role")
+	f.user.Role = role
+	return nil
+}
+
+func (f *fakeUserRepo) SetActive(ctx context.Context, id uint64, active bool) error {
+	*f.steps = append(*f.steps, "set_active")
+	f.user.IsActive = active
+	return nil
+}
+
+func (f *fakeUserRepo) SoftDelete(ctx context.Context, id uint64) error {
+	*f.steps = append(*f.steps, "soft_delete")
+	return nil
+}
+
+type fakeAuthSessionRepo struct {
+	repository.AuthSessionRepository
+	steps     *[]string
+	revokeErr error
+}
+
+func (f *fakeAuthSessionRepo) RevokeAllByUser(ctx context.Context, userID uint64, reason string) (int64, error) {
+	*f.steps = append(*f.steps, "revoke:"+reason)
+	return 1, f.revokeErr
+}
+
+func newUserAdminFixture(revokeErr error) (*userAdminUsecase, *fakeTx, *[]string) {
+	steps := &[]string{}
+	users := &fakeUserRepo{user: domain.User{ID: 2, Role: domain.RolePeserta, IsActive: true}, steps: steps}
+	sessions := &fakeAuthSessionRepo{steps: steps, revokeErr: revokeErr}
+	tx := &fakeTx{repos: &repository.Repositories{User: users, AuthSession: sessions}}
+	return &userAdminUsecase{repo: users, tx: tx}, tx, steps
+}
+
+func TestUserAdminRevokesSessionsInSameTx(t *testing.T) {
+	tests := []struct {
+		name  string
+		run   func(u *userAdminUsecase) error
+		steps []string
+	}{
+		{
+			name: "change role",
+			run: func(u *userAdminUsecase) error {
+				_, err := u.ChangeRole(context.Background(), domain.RoleAdmin, 1, 2, domain.RoleTutor)
+				return err
+			},
+			steps: []string{"change_role", "revoke:" + domain.SessionRevokedRoleChanged},
+		},
+		{
+			name: "nonaktifkan",
+			run: func(u *userAdminUsecase) error {
+				_, err := u.SetActive(context.Background(), domain.RoleAdmin, 1, 2, false)
+				return err
+			},
+			steps: []string{"set_active", "revoke:" + domain.SessionRevokedInactive},
+		},
+		{
+			name: "aktifkan tanpa revoke",
+			run: func(u *userAdminUsecase) error {
+				_, err := u.SetActive(context.Background(), domain.RoleAdmin, 1, 2, true)
+				return err
+			},
+			steps: []string{"set_active"},
+		},
+		{
+			name: "hapus",
+			run: func(u *userAdminUsecase) error {
+				return u.Delete(context.Background(), domain.RoleAdmin, 1, 2)
+			},
+			steps: []string{"soft_delete", "revoke:" + domain.SessionRevokedDeleted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, tx, steps := newUserAdminFixture(nil)
+			if err := tt.run(u); err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if tx.calls != 1 {
+				t.Errorf("WithinTx dipanggil %d kali, want 1", tx.calls)
+			}
+			if len(*steps) != len(tt.steps) {
+				t.Fatalf("steps = %v, want %v", *steps, tt.steps)
+			}
+			for i := range tt.steps {
+				if (*steps)[i] != tt.steps[i] {
+					t.Errorf("steps = %v, want %v", *steps, tt.steps)
+				}
+			}
+		})
+	}
+}
+
+func TestUserAdminRevokeFailureFailsChange(t *testing.T) {
+	revokeErr := errors.New("db down")
+	u, _, _ := newUserAdminFixture(revokeErr)
+
+	if _, err := u.ChangeRole(context.Background(), domain.RoleAdmin, 1, 2, domain.RoleTutor); !errors.Is(err, revokeErr) {
+		t.Errorf("ChangeRole err = %v, want %v", err, revokeErr)
+	}
+	if err := u.Delete(context.Background(), domain.RoleAdmin, 1, 2); !errors.Is(err, revokeErr) {
+		t.Errorf("Delete err = %v, want %v", err, revokeErr)
+	}
+}
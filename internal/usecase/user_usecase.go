@@ -1,83 +1,119 @@
 package usecase
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"main-service/internal/auth"
 	"main-service/internal/domain"
+	"main-service/internal/mailer"
 	"main-service/internal/repository"
 
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ClientInfo adalah informasi perangkat yang disimpan bersama sesi login
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// AuthConfig berisi pengaturan token dan email yang dibaca dari .env
+type AuthConfig struct {
+	JWTSecret              string
+	AccessTTL              time.Duration
+	RefreshTTL             time.Duration
+	PasswordResetTTL       time.Duration
+	EmailVerificationTTL   time.Duration
+	EmailVerificationRoles []string
+	AppBaseURL             string
+}
+
 type UserUsecase interface {
-	Login(email, password string) (map[string]interface{}, error)
-	Register(name, email, password, role string) (map[string]interface{}, error)
+	Login(ctx context.Context, email, password string, client ClientInfo) (map[string]interface{}, error)
+	Register(ctx context.Context, name, email, password, role string, client ClientInfo) (map[string]interface{}, error)
+	Refresh(ctx context.Context, refreshToken string) (map[string]interface{}, error)
+	Logout(ctx context.Context, userID, sessionID uint64) error
+	LogoutAll(ctx context.Context, userID uint64) (int64, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
 }
 
 type userUsecase struct {
-	repo       repository.UserRepository
-	jwtSecret  string
-	jwtExpHour int
+	repo        repository.UserRepository
+	sessionRepo repository.AuthSessionRepository
+	tokenRepo   repository.UserTokenRepository
+	tx          repository.TxManager
+	mailer      mailer.Mailer
+	cfg         AuthConfig
 }
 
-// NewUserUsecase inisialisasi usecase dengan repo + secret jwt dari .env
-func NewUserUsecase(repo repository.UserRepository, jwtSecret string, jwtExpHour int) UserUsecase {
+// NewUserUsecase inisialisasi usecase dengan repo + konfigurasi auth dari .env
+func NewUserUsecase(repo repository.UserRepository, sessionRepo repository.AuthSessionRepository, tokenRepo repository.UserTokenRepository, tx repository.TxManager, m mailer.Mailer, cfg AuthConfig) UserUsecase {
 	return &userUsecase{
-		repo:       repo,
-		jwtSecret:  jwtSecret,
-		jwtExpHour: jwtExpHour,
+		repo:        repo,
+		sessionRepo: sessionRepo,
+		tokenRepo:   tokenRepo,
+		tx:          tx,
+		mailer:      m,
+		cfg:         cfg,
 	}
 }
 
 // -------------------- LOGIN --------------------
 
-func (u *userUsecase) Login(email, password string) (map[string]interface{}, error) {
+func (u *userUsecase) Login(ctx context.Context, email, password string, client ClientInfo) (map[string]interface{}, error) {
 	if email == "" || password == "" {
-		return nil, errors.New("email dan password wajib diisi")
+		return nil, domain.Validation("email dan password wajib diisi", nil)
 	}
 
-	user, err := u.repo.FindByEmail(email)
+	// FindByEmail hanya mengembalikan akun aktif; akun nonaktif tidak bisa login
+	user, err := u.repo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, errors.New("email tidak ditemukan")
-	}
-
-	if user.IsActive == 0 {
-		return nil, errors.New("akun tidak aktif")
+		return nil, domain.Unauthorized("INVALID_CREDENTIALS", "email tidak ditemukan")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, errors.New("password salah")
+		return nil, domain.Unauthorized("INVALID_CREDENTIALS", "password salah")
 	}
 
-	tokenString, exp, err := u.generateToken(user)
-	if err != nil {
-		return nil, errors.New("gagal membuat token")
+	if u.requiresVerification(user) {
+		return nil, domain.ErrEmailNotVerified
 	}
 
-	return map[string]interface{}{
-		"token":      tokenString,
-		"expires_at": exp.Format(time.RFC3339),
-		"user": map[string]interface{}{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
-			"role":  user.Role,
-		},
-	}, nil
+	return u.startSession(ctx, user, client)
 }
 
 // -------------------- REGISTER --------------------
 
-func (u *userUsecase) Register(name, email, password, role string) (map[string]interface{}, error) {
+func (u *userUsecase) Register(ctx context.Context, name, email, password, role string, client ClientInfo) (map[string]interface{}, error) {
 	if name == "" || email == "" || password == "" || role == "" {
-		return nil, errors.New("nama, email, password, dan role wajib diisi")
+		return nil, domain.Validation("nama, email, password, dan role wajib diisi", nil)
 	}
 
-	existing, _ := u.repo.FindByEmail(email)
-	if existing != nil {
-		return nil, errors.New("email sudah terdaftar")
+	if !domain.IsValidRole(role) {
+		return nil, domain.ErrInvalidRole
+	}
+	if !domain.IsSelfRegistrableRole(role) {
+		return nil, domain.ErrRoleNotRegistrable
+	}
+
+	exists, err := u.repo.ExistsEmail(ctx, email)
+	if err != nil {
+		return nil, errors.New("gagal memeriksa email")
+	}
+	if exists {
+		return nil, domain.ErrEmailTaken
 	}
 
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -90,21 +126,234 @@ func (u *userUsecase) Register(name, email, password, role string) (map[string]i
 		Email:    email,
 		Password: string(hashed),
 		Role:     role,
-		IsActive: 1,
+		IsActive: true,
 	}
 
-	if err := u.repo.CreateUser(user); err != nil {
+	if err := u.repo.CreateUser(ctx, user); err != nil {
 		return nil, errors.New("gagal menyimpan user")
 	}
 
-	tokenString, exp, err := u.generateToken(user)
+	// Role yang wajib verifikasi belum mendapat token sampai email dikonfirmasi
+	if u.requiresVerification(user) {
+		if err := u.sendVerification(ctx, user); err != nil {
+			log.Printf("Send verification email failed: %v", err)
+		}
+		return map[string]interface{}{
+			"email_verification_required": true,
+			"user": map[string]interface{}{
+				"id":    user.ID,
+				"name":  user.Name,
+				"email": user.Email,
+				"role":  user.Role,
+			},
+		}, nil
+	}
+
+	return u.startSession(ctx, user, client)
+}
+
+// -------------------- REFRESH & LOGOUT --------------------
+
+// Refresh merotasi refresh token: token lama tidak bisa dipakai lagi dan
+// pemakaian ulang token lama mencabut seluruh sesi perangkat tersebut
+func (u *userUsecase) Refresh(ctx context.Context, refreshToken string) (map[string]interface{}, error) {
+	if refreshToken == "" {
+		return nil, domain.InvalidField("refresh_token", "refresh_token wajib diisi")
+	}
+
+	newToken, err := generateSecureToken()
+	if err != nil {
+		return nil, errors.New("gagal membuat token")
+	}
+	refreshExp := time.Now().Add(u.cfg.RefreshTTL)
+
+	session, err := u.sessionRepo.Rotate(ctx, hashToken(refreshToken), hashToken(newToken), refreshExp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Role diambil ulang agar perubahan role/status akun langsung berlaku
+	user, err := u.repo.FindTutorIDByUserID(ctx, session.UserID)
+	if err != nil || !user.IsActive {
+		_ = u.sessionRepo.Revoke(ctx, session.ID, session.UserID, domain.SessionRevokedInactive)
+		return nil, domain.ErrAccountInactive
+	}
+
+	return u.tokenResponse(user, session.ID, newToken, refreshExp)
+}
+
+func (u *userUsecase) Logout(ctx context.Context, userID, sessionID uint64) error {
+	if userID == 0 || sessionID == 0 {
+		return domain.ErrUnauthenticated
+	}
+	return u.sessionRepo.Revoke(ctx, sessionID, userID, domain.SessionRevokedLogout)
+}
+
+func (u *userUsecase) LogoutAll(ctx context.Context, userID uint64) (int64, error) {
+	if userID == 0 {
+		return 0, domain.ErrUnauthenticated
+	}
+	return u.sessionRepo.RevokeAllByUser(ctx, userID, domain.SessionRevokedLogoutAll)
+}
+
+// -------------------- PASSWORD RESET --------------------
+
+// ForgotPassword selalu sukses dari sisi pemanggil agar tidak membocorkan
+// email mana yang terdaftar; kegagalan hanya dicatat di log
+func (u *userUsecase) ForgotPassword(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return domain.InvalidField("email", "email wajib diisi")
+	}
+
+	user, err := u.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	token, err := generateSecureToken()
+	if err != nil {
+		return errors.New("gagal membuat token")
+	}
+	if err := u.tokenRepo.Create(ctx, user.ID, domain.UserTokenPasswordReset, hashToken(token), time.Now().Add(u.cfg.PasswordResetTTL)); err != nil {
+		log.Printf("Create reset password token failed: %v", err)
+		return nil
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password akun Bisolpin",
+		Body: fmt.Sprintf("Halo %s,\n\nGunakan link berikut untuk membuat password baru (berlaku %d menit):\n%s/reset-password?token=%s\n\nAbaikan email ini jika anda tidak meminta reset password.",
+			user.Name, int(u.cfg.PasswordResetTTL.Minutes()), u.cfg.AppBaseURL, token),
+	}
+	if err := u.mailer.Send(msg); err != nil {
+		log.Printf("Send reset password email failed: %v", err)
+	}
+	return nil
+}
+
+// ResetPassword mengganti password dan mencabut semua sesi login yang ada.
+// Token, password, dan sesi diubah dalam satu transaksi supaya token tidak
+// hangus tanpa password berganti.
+func (u *userUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" || newPassword == "" {
+		return domain.Validation("token dan password wajib diisi", nil)
+	}
+	if len(newPassword) < 8 {
+		return domain.InvalidField("password", "password minimal 8 karakter")
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errors.New("gagal mengenkripsi password")
+	}
+
+	return u.tx.WithinTx(ctx, func(repos *repository.Repositories) error {
+		userID, err := repos.UserToken.Consume(ctx, domain.UserTokenPasswordReset, hashToken(token))
+		if err != nil {
+			return err
+		}
+		if err := repos.User.UpdatePassword(ctx, userID, string(hashed)); err != nil {
+			return err
+		}
+		_, err = repos.AuthSession.RevokeAllByUser(ctx, userID, domain.SessionRevokedPasswordReset)
+		return err
+	})
+}
+
+// -------------------- EMAIL VERIFICATION --------------------
+
+func (u *userUsecase) VerifyEmail(ctx context.Context, token string) error {
+	if token == "" {
+		return domain.InvalidField("token", "token wajib diisi")
+	}
+
+	userID, err := u.tokenRepo.Consume(ctx, domain.UserTokenEmailVerification, hashToken(token))
+	if err != nil {
+		return err
+	}
+	return u.repo.MarkEmailVerified(ctx, userID)
+}
+
+// ResendVerification sama seperti ForgotPassword: respons tidak membedakan email terdaftar atau tidak
+func (u *userUsecase) ResendVerification(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return domain.InvalidField("email", "email wajib diisi")
+	}
+
+	user, err := u.repo.FindByEmail(ctx, email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	if err := u.sendVerification(ctx, user); err != nil {
+		log.Printf("Send verification email failed: %v", err)
+	}
+	return nil
+}
+
+func (u *userUsecase) requiresVerification(user *domain.User) bool {
+	if user.EmailVerifiedAt != nil {
+		return false
+	}
+	for _, role := range u.cfg.EmailVerificationRoles {
+		if role == user.Role {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *userUsecase) sendVerification(ctx context.Context, user *domain.User) error {
+	token, err := generateSecureToken()
+	if err != nil {
+		return err
+	}
+	if err := u.tokenRepo.Create(ctx, user.ID, domain.UserTokenEmailVerification, hashToken(token), time.Now().Add(u.cfg.EmailVerificationTTL)); err != nil {
+		return err
+	}
+
+	return u.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verifikasi email akun Bisolpin",
+		Body: fmt.Sprintf("Halo %s,\n\nKonfirmasi email anda lewat link berikut:\n%s/verify-email?token=%s\n\nLink berlaku %d jam.",
+			user.Name, u.cfg.AppBaseURL, token, int(u.cfg.EmailVerificationTTL.Hours())),
+	})
+}
+
+// -------------------- HELPER --------------------
+
+func (u *userUsecase) startSession(ctx context.Context, user *domain.User, client ClientInfo) (map[string]interface{}, error) {
+	refreshToken, err := generateSecureToken()
+	if err != nil {
+		return nil, errors.New("gagal membuat token")
+	}
+	refreshExp := time.Now().Add(u.cfg.RefreshTTL)
+
+	session := &domain.AuthSession{
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+	}
+	if err := u.sessionRepo.Create(ctx, session, hashToken(refreshToken), refreshExp); err != nil {
+		return nil, errors.New("gagal membuat sesi login")
+	}
+
+	return u.tokenResponse(user, session.ID, refreshToken, refreshExp)
+}
+
+func (u *userUsecase) tokenResponse(user *domain.User, sessionID uint64, refreshToken string, refreshExp time.Time) (map[string]interface{}, error) {
+	tokenString, exp, err := u.generateToken(user, sessionID)
 	if err != nil {
 		return nil, errors.New("gagal membuat token")
 	}
 
 	return map[string]interface{}{
-		"token":      tokenString,
-		"expires_at": exp.Format(time.RFC3339),
+		"token":              tokenString,
+		"expires_at":         exp.Format(time.RFC3339),
+		"refresh_token":      refreshToken,
+		"refresh_expires_at": refreshExp.Format(time.RFC3339),
 		"user": map[string]interface{}{
 			"id":    user.ID,
 			"name":  user.Name,
@@ -114,22 +363,40 @@ func (u *userUsecase) Register(name, email, password, role string) (map[string]i
 	}, nil
 }
 
-// -------------------- HELPER --------------------
-
-func (u *userUsecase) generateToken(user *domain.User) (string, time.Time, error) {
-	exp := time.Now().Add(time.Duration(u.jwtExpHour) * time.Hour)
+func (u *userUsecase) generateToken(user *domain.User, sessionID uint64) (string, time.Time, error) {
+	exp := time.Now().Add(u.cfg.AccessTTL)
 
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"role":    user.Role,
-		"exp":     exp.Unix(),
+	principal := domain.AuthPrincipal{
+		UserID:    user.ID,
+		Role:      user.Role,
+		SessionID: sessionID,
+	}
+	if user.TutorID != nil {
+		principal.TutorID = *user.TutorID
+	}
+	if user.PesertaID != nil {
+		principal.PesertaID = *user.PesertaID
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(u.jwtSecret))
+	tokenString, err := auth.Sign(principal, exp, u.cfg.JWTSecret)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
 	return tokenString, exp, nil
 }
+
+// generateSecureToken membuat token acak untuk refresh token dan link email;
+// hanya hash-nya yang disimpan di database
+func generateSecureToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"main-service/internal/domain"
+	"main-service/internal/repository"
+	"reflect"
+	"testing"
+)
+
+type fakeUserTokenRepo struct {
+	repository.UserTokenRepository
+	steps *[]string
+}
+
+func (f *fakeUserTokenRepo) Consume(ctx context.Context, purpose, tokenHash string) (uint64, error) {
+	*f.steps = append(*f.steps, "consume:"+purpose)
+	return 2, nil
+}
+
+func (f *fakeUserRepo) UpdatePassword(ctx context.Context, userID uint64, hashed string) error {
+	*f.steps = append(*f.steps, "update_password")
+	return nil
+}
+
+func TestResetPasswordRunsInOneTx(t *testing.T) {
+	revokeErr := errors.New("db down")
+	for _, tc := range []struct {
+		name      string
+		revokeErr error
+	}{
+		{"sukses", nil},
+		{"revoke gagal", revokeErr},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			steps := &[]string{}
+			users := &fakeUserRepo{steps: steps}
+			sessions := &fakeAuthSessionRepo{steps: steps, revokeErr: tc.revokeErr}
+			tokens := &fakeUserTokenRepo{steps: steps}
+			tx := &fakeTx{repos: &repository.Repositories{User: users, AuthSession: sessions, UserToken: tokens}}
+			// Repo di luar transaksi sengaja nil: semua penulisan harus lewat tx
+			u := &userUsecase{tx: tx}
+
+			err := u.ResetPassword(context.Background(), "token", "password-baru")
+			if !errors.Is(err, tc.revokeErr) {
+				t.Fatalf("err = %v, want %v", err, tc.revokeErr)
+			}
+			want := []string{"consume:" + domain.UserTokenPasswordReset, "update_password", "revoke:" + domain.SessionRevokedPasswordReset}
+			if !reflect.DeepEqual(*steps, want) {
+				t.Errorf("steps = %v, want %v", *steps, want)
+			}
+			// Gagal mencabut sesi membatalkan transaksi, termasuk token yang terpakai
+			if tx.calls != 1 || !errors.Is(tx.lastErr, tc.revokeErr) {
+				t.Errorf("WithinTx calls = %d, lastErr = %v", tx.calls, tx.lastErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"main-service/internal/domain"
+	"math"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeJSON mem-parse body JSON ke dst lalu menjalankan Struct. Body yang
+// bukan JSON menghasilkan domain.ErrInvalidBody; nilai dengan tipe salah
+// (mis. "harga": "abc") dilaporkan sebagai error field bersama aturan lain.
+func DecodeJSON(body []byte, dst any) error {
+	errs := Errors{}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		body = []byte("{}")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if err := dec.Decode(dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) || typeErr.Field == "" {
+			return domain.ErrInvalidBody
+		}
+		// encoding/json berhenti melapor setelah type error pertama, jadi
+		// body di-decode ulang per field untuk mengumpulkan semuanya.
+		// Field lain tetap terisi, jadi aturan sisanya masih bisa dicek.
+		collectTypeErrors(body, reflect.TypeOf(dst), "", errs)
+		if len(errs) == 0 {
+			errs.Add(typeErr.Field, typeErr.Field+" harus bertipe "+jsonTypeName(typeErr.Type))
+		}
+	}
+
+	check(reflect.ValueOf(dst), "", errs)
+	return errs.Err()
+}
+
+// collectTypeErrors mencoba men-decode raw ke tipe t per field (rekursif
+// untuk struct dan slice of struct) dan mencatat setiap field yang tipenya
+// salah dengan nama seperti "items[0].status"
+func collectTypeErrors(raw json.RawMessage, t reflect.Type, name string, errs Errors) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if string(bytes.TrimSpace(raw)) == "null" {
+		return
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && t != timeType:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			errs.Add(name, name+" harus bertipe "+jsonTypeName(t))
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() || sf.Tag.Get("json") == "-" {
+				continue
+			}
+			key := FieldName(sf)
+			value, ok := lookupJSONField(fields, key)
+			if !ok {
+				continue
+			}
+			collectTypeErrors(value, sf.Type, joinName(name, key), errs)
+		}
+
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			errs.Add(name, name+" harus bertipe "+jsonTypeName(t))
+			return
+		}
+		for i, item := range items {
+			collectTypeErrors(item, t.Elem(), fmt.Sprintf("%s[%d]", name, i), errs)
+		}
+
+	default:
+		var typeErr *json.UnmarshalTypeError
+		if err := json.Unmarshal(raw, reflect.New(t).Interface()); errors.As(err, &typeErr) {
+			errs.Add(name, name+" harus bertipe "+jsonTypeName(t))
+		}
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// lookupJSONField mencari key seperti encoding/json: persis dulu, lalu tanpa
+// membedakan huruf besar/kecil
+func lookupJSONField(fields map[string]json.RawMessage, key string) (json.RawMessage, bool) {
+	if v, ok := fields[key]; ok {
+		return v, true
+	}
+	for k, v := range fields {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func joinName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "bilangan bulat"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "bilangan bulat positif"
+	default:
+		return "angka"
+	}
+}
+
+// DecodeForm mengisi field bertag `form` dari body multipart lalu menjalankan
+// Struct. Berbeda dengan strconv yang error-nya diabaikan, nilai yang tidak
+// bisa di-parse dilaporkan per field. Value kosong dibiarkan zero (atau nil
+// untuk pointer) sehingga `required` yang menilainya. Field bertipe
+// *multipart.FileHeader diisi dari file upload dengan nama yang sama.
+func DecodeForm(form *multipart.Form, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		panic("validation: DecodeForm membutuhkan pointer ke struct")
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	errs := Errors{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key := sf.Tag.Get("form")
+		if key == "" || key == "-" || !sf.IsExported() {
+			continue
+		}
+
+		fv := v.Field(i)
+		if sf.Type == fileHeaderType {
+			if files := form.File[key]; len(files) > 0 {
+				fv.Set(reflect.ValueOf(files[0]))
+			}
+			continue
+		}
+
+		var raw string
+		if values := form.Value[key]; len(values) > 0 {
+			raw = strings.TrimSpace(values[0])
+		}
+		if raw == "" {
+			continue
+		}
+
+		if fv.Kind() == reflect.Pointer {
+			fv.Set(reflect.New(fv.Type().Elem()))
+			fv = fv.Elem()
+		}
+		if msg := setFormValue(fv, raw); msg != "" {
+			errs.Add(key, key+" "+msg)
+		}
+	}
+
+	check(v, "", errs)
+	return errs.Err()
+}
+
+var fileHeaderType = reflect.TypeOf(&multipart.FileHeader{})
+
+func setFormValue(fv reflect.Value, raw string) string {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "harus true atau false"
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return "harus berupa bilangan bulat"
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return "harus berupa bilangan bulat positif"
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		// ParseFloat menerima "NaN" dan "Inf", yang bukan angka valid untuk input
+		n, err := strconv.ParseFloat(raw, fv.Type().Bits())
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return "harus berupa angka"
+		}
+		fv.SetFloat(n)
+	default:
+		panic("validation: tipe form field tidak didukung: " + fv.Type().String())
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"main-service/internal/domain"
+	"math"
+	"mime/multipart"
+	"testing"
+)
+
+func fieldErrors(t *testing.T, err error) map[string]string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	de, ok := domain.AsError(err)
+	if !ok || de.Kind != domain.KindValidation {
+		t.Fatalf("err = %v, want validation error", err)
+	}
+	return de.Fields
+}
+
+func TestDecodeFormOptionalZeroPrice(t *testing.T) {
+	type priceForm struct {
+		Harga *float64 `form:"harga" validate:"required,min=0"`
+	}
+
+	tests := []struct {
+		name    string
+		value   []string
+		wantErr bool
+		want    float64
+	}{
+		{name: "tidak diisi", value: nil, wantErr: true},
+		{name: "gratis", value: []string{"0"}, want: 0},
+		{name: "berbayar", value: []string{"150000"}, want: 150000},
+		{name: "negatif", value: []string{"-1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := &multipart.Form{Value: map[string][]string{}}
+			if tt.value != nil {
+				form.Value["harga"] = tt.value
+			}
+
+			var dst priceForm
+			fields := fieldErrors(t, DecodeForm(form, &dst))
+			if tt.wantErr {
+				if fields["harga"] == "" {
+					t.Fatalf("harga lolos validasi: %+v", dst)
+				}
+				return
+			}
+			if len(fields) > 0 {
+				t.Fatalf("fields = %v", fields)
+			}
+			if *dst.Harga != tt.want {
+				t.Fatalf("harga = %v, want %v", *dst.Harga, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeFormRejectsNonFiniteNumbers(t *testing.T) {
+	type priceForm struct {
+		Harga float64 `form:"harga" validate:"required,gt=0"`
+	}
+
+	for _, raw := range []string{"NaN", "nan", "Inf", "+Inf", "-Inf", "infinity"} {
+		t.Run(raw, func(t *testing.T) {
+			form := &multipart.Form{Value: map[string][]string{"harga": {raw}}}
+			var dst priceForm
+			fields := fieldErrors(t, DecodeForm(form, &dst))
+			if fields["harga"] != "harga harus berupa angka" {
+				t.Fatalf("fields = %v", fields)
+			}
+		})
+	}
+}
+
+func TestStructRejectsNaNBounds(t *testing.T) {
+	type price struct {
+		Harga float64 `json:"harga" validate:"gt=0"`
+		Max   float64 `json:"max" validate:"max=10"`
+	}
+
+	fields := fieldErrors(t, Struct(price{Harga: math.NaN(), Max: math.Inf(1)}))
+	if fields["harga"] == "" || fields["max"] == "" {
+		t.Fatalf("NaN/Inf lolos validasi: %v", fields)
+	}
+}
+
+func TestDecodeJSONCollectsAllTypeErrors(t *testing.T) {
+	type item struct {
+		PesertaID uint64 `json:"peserta_id" validate:"required"`
+		Status    string `json:"status" validate:"required"`
+	}
+	type body struct {
+		Name  string  `json:"name" validate:"required"`
+		Harga float64 `json:"harga"`
+		Tags  []string
+		Items []item `json:"items"`
+	}
+
+	payload := `{
+		"name": 123,
+		"harga": "mahal",
+		"Tags": "a",
+		"items": [
+			{"peserta_id": 1, "status": "present"},
+			{"peserta_id": "dua", "status": 5}
+		]
+	}`
+
+	var dst body
+	fields := fieldErrors(t, DecodeJSON([]byte(payload), &dst))
+
+	want := map[string]string{
+		"name":                "name harus bertipe string",
+		"harga":               "harga harus bertipe angka",
+		"tags":                "tags harus bertipe array",
+		"items[1].peserta_id": "items[1].peserta_id harus bertipe bilangan bulat positif",
+		"items[1].status":     "items[1].status harus bertipe string",
+	}
+	for field, msg := range want {
+		if fields[field] != msg {
+			t.Errorf("fields[%q] = %q, want %q", field, fields[field], msg)
+		}
+	}
+	if len(fields) != len(want) {
+		t.Errorf("fields = %v", fields)
+	}
+}
+
+func TestDecodeJSONInvalidBody(t *testing.T) {
+	var dst struct {
+		Name string `json:"name"`
+	}
+	for _, body := range []string{`{`, `[1,2]`, `"x"`} {
+		if err := DecodeJSON([]byte(body), &dst); err != domain.ErrInvalidBody {
+			t.Errorf("DecodeJSON(%s) = %v, want ErrInvalidBody", body, err)
+		}
+	}
+}
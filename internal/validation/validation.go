@@ -0,0 +1,256 @@
+// Package validation memvalidasi request body secara deklaratif lewat tag
+// `validate` pada struct, mis.
+//
+//	Name string `json:"name" validate:"required,min=3,max=100"`
+//
+// Semua field diperiksa sekaligus sehingga client menerima seluruh kesalahan
+// dalam satu response, bukan satu per satu.
+//
+// Aturan yang tersedia:
+//   - required       string tidak kosong (setelah trim), angka bukan 0, pointer/file tidak nil, slice tidak kosong
+//   - min=N / max=N  panjang string (karakter), nilai angka, atau jumlah elemen slice
+//   - gt=N           angka harus lebih besar dari N
+//   - oneof=a b c    nilai harus salah satu dari daftar (dipisah spasi)
+//   - email          format email sederhana
+//
+// Field pointer yang nil dianggap tidak diisi: hanya `required` yang berlaku.
+// Struct dan slice of struct diperiksa rekursif dengan nama field seperti
+// "items[0].status".
+package validation
+
+import (
+	"fmt"
+	"main-service/internal/domain"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Message dipakai sebagai pesan utama error validasi
+const Message = "input tidak valid"
+
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// Errors mengumpulkan pesan error per field; field pertama yang gagal menang
+type Errors map[string]string
+
+func (e Errors) Add(field, msg string) {
+	if _, exists := e[field]; !exists {
+		e[field] = msg
+	}
+}
+
+// Err mengubah kumpulan error menjadi domain.Validation, atau nil jika kosong
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return domain.Validation(Message, e)
+}
+
+// With menambahkan satu error field ke hasil validasi sebelumnya (nil atau
+// error dari Struct/Decode*), untuk aturan yang bergantung pada konteks
+// handler, mis. file yang hanya wajib saat create. Error lain dikembalikan apa adanya.
+func With(err error, field, msg string) error {
+	errs := Errors{}
+	if err != nil {
+		de, ok := domain.AsError(err)
+		if !ok || de.Kind != domain.KindValidation {
+			return err
+		}
+		for k, v := range de.Fields {
+			errs[k] = v
+		}
+	}
+	errs.Add(field, msg)
+	return errs.Err()
+}
+
+// Struct menjalankan aturan `validate` pada v (pointer ke struct atau struct)
+func Struct(v any) error {
+	errs := Errors{}
+	check(reflect.ValueOf(v), "", errs)
+	return errs.Err()
+}
+
+func check(v reflect.Value, prefix string, errs Errors) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := prefix + FieldName(sf)
+		fv := v.Field(i)
+
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			if msg := checkRules(fv, name, tag); msg != "" {
+				errs.Add(name, msg)
+				continue
+			}
+		}
+
+		// Turun ke nested struct / slice of struct (file upload tidak diperiksa isinya)
+		if sf.Type == fileHeaderType {
+			continue
+		}
+		inner := fv
+		for inner.Kind() == reflect.Pointer && !inner.IsNil() {
+			inner = inner.Elem()
+		}
+		switch inner.Kind() {
+		case reflect.Struct:
+			check(inner, name+".", errs)
+		case reflect.Slice:
+			for j := 0; j < inner.Len(); j++ {
+				check(inner.Index(j), fmt.Sprintf("%s[%d].", name, j), errs)
+			}
+		}
+	}
+}
+
+// FieldName mengambil nama field dari tag json/form, sama dengan yang dikirim client
+func FieldName(sf reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		if tag := sf.Tag.Get(key); tag != "" && tag != "-" {
+			if name, _, _ := strings.Cut(tag, ","); name != "" {
+				return name
+			}
+		}
+	}
+	return strings.ToLower(sf.Name)
+}
+
+// checkRules mengembalikan pesan error aturan pertama yang gagal
+func checkRules(v reflect.Value, name, tag string) string {
+	rules := strings.Split(tag, ",")
+
+	// Untuk pointer, `required` hanya berarti "diisi": nilai nol seperti 0
+	// atau false tetap sah, sehingga pointer dipakai untuk field opsional-nol
+	present := false
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			for _, r := range rules {
+				if r == "required" {
+					return name + " wajib diisi"
+				}
+			}
+			return ""
+		}
+		v = v.Elem()
+		present = true
+	}
+
+	for _, rule := range rules {
+		key, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		var msg string
+		switch key {
+		case "required":
+			if !present {
+				msg = checkRequired(v, name)
+			}
+		case "min", "max", "gt":
+			msg = checkBound(v, name, key, arg)
+		case "oneof":
+			msg = checkOneOf(v, name, arg)
+		case "email":
+			if v.Kind() == reflect.String && v.String() != "" && !emailPattern.MatchString(v.String()) {
+				msg = name + " harus berupa email yang valid"
+			}
+		case "":
+		default:
+			panic(fmt.Sprintf("validation: aturan %q tidak dikenal pada field %s", key, name))
+		}
+		if msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func checkRequired(v reflect.Value, name string) string {
+	empty := false
+	switch v.Kind() {
+	case reflect.String:
+		empty = strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		empty = v.Len() == 0
+	case reflect.Bool, reflect.Struct:
+		// false / zero struct tetap dianggap nilai; pakai pointer untuk membedakan "tidak diisi"
+	default:
+		empty = v.IsZero()
+	}
+	if empty {
+		return name + " wajib diisi"
+	}
+	return ""
+}
+
+func checkBound(v reflect.Value, name, key, arg string) string {
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: argumen %s=%q tidak valid pada field %s", key, arg, name))
+	}
+
+	var (
+		n    float64
+		unit string
+	)
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() == "" {
+			return "" // kosong urusan `required`
+		}
+		n, unit = float64(utf8.RuneCountInString(strings.TrimSpace(v.String()))), " karakter"
+	case reflect.Slice, reflect.Map:
+		n, unit = float64(v.Len()), " item"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return name + " harus berupa angka"
+		}
+	default:
+		return ""
+	}
+
+	switch {
+	case key == "min" && n < limit:
+		return fmt.Sprintf("%s minimal %s%s", name, arg, unit)
+	case key == "max" && n > limit:
+		return fmt.Sprintf("%s maksimal %s%s", name, arg, unit)
+	case key == "gt" && n <= limit:
+		return fmt.Sprintf("%s harus lebih dari %s", name, arg)
+	}
+	return ""
+}
+
+func checkOneOf(v reflect.Value, name, arg string) string {
+	options := strings.Fields(arg)
+	value := fmt.Sprint(v.Interface())
+	if v.Kind() == reflect.String && value == "" {
+		return ""
+	}
+	for _, o := range options {
+		if value == o {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s harus salah satu dari %s", name, strings.Join(options, ", "))
+}